@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
 	"github.com/mattn/go-isatty"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -22,6 +24,11 @@ var defaultSseHost = "127.0.0.1"
 var defaultSsePort = 13080
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		runSync(os.Args[2:])
+		return
+	}
+
 	var transport string
 	var enabledToolsFlag string
 	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio, sse or http)")
@@ -76,9 +83,69 @@ func main() {
 		)
 	}
 
+	_, err = server.ParseConfirmTools(os.Getenv("SLACK_MCP_CONFIRM_TOOLS"))
+	if err != nil {
+		logger.Fatal("error in SLACK_MCP_CONFIRM_TOOLS",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	_, err = auth.OAuthConfigFromEnv()
+	if err != nil {
+		logger.Fatal("error in SLACK_MCP_OAUTH_ISSUER",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	_, err = provider.SyncPatterns()
+	if err != nil {
+		logger.Fatal("error in SLACK_MCP_SYNC_CHANNELS",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	workspaceNames, err := provider.WorkspaceNames()
+	if err != nil {
+		logger.Fatal("error in SLACK_MCP_WORKSPACES",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	if server.MultiTenant() {
+		if transport != "http" {
+			logger.Fatal("error in SLACK_MCP_MULTI_TENANT",
+				zap.String("context", "console"),
+				zap.Error(fmt.Errorf("multi-tenant mode needs the http transport, not %s", transport)),
+			)
+		}
+		serveTenants(transport, enabledTools, logger)
+		return
+	}
+
 	p := provider.New(transport, logger)
 	s := server.NewMCPServer(p, logger, enabledTools)
 
+	workspaces := make(map[string]*provider.ApiProvider)
+	for _, name := range workspaceNames {
+		workspaces[name] = provider.NewWorkspace(transport, name, logger)
+		s.AddWorkspace(name, workspaces[name], enabledTools)
+	}
+
+	var socketMode *provider.SocketMode
+	if appToken := os.Getenv("SLACK_MCP_APP_TOKEN"); appToken != "" {
+		socketMode, err = provider.NewSocketMode(s.EventRouter(), appToken, logger)
+		if err != nil {
+			logger.Fatal("error in SLACK_MCP_APP_TOKEN",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Set up signal handling for graceful shutdown (ensures cleanup runs)
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
@@ -105,8 +172,42 @@ func main() {
 		newUsersWatcher(p, &once, logger)()
 		newChannelsWatcher(p, &once, logger)()
 		newEmojiWatcher(p, &once, logger)()
+
+		for name, wp := range workspaces {
+			var once sync.Once
+			wsLogger := logger.With(zap.String("workspace", name))
+
+			newUsersWatcher(wp, &once, wsLogger)()
+			newChannelsWatcher(wp, &once, wsLogger)()
+			newEmojiWatcher(wp, &once, wsLogger)()
+		}
+
+		if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+			return
+		}
+		for _, wp := range workspaces {
+			go wp.RefreshPeriodically(context.Background())
+			if syncer := wp.Syncer(); syncer != nil {
+				go syncer.Run(context.Background())
+			}
+		}
+		if syncer := p.Syncer(); syncer != nil {
+			go syncer.Run(context.Background())
+		}
+		p.RefreshPeriodically(context.Background())
 	}()
 
+	if socketMode != nil {
+		go func() {
+			if err := socketMode.Run(context.Background()); err != nil {
+				logger.Error("Socket Mode stopped, caches fall back to TTL refreshes",
+					zap.String("context", "console"),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+
 	switch transport {
 	case "stdio":
 		if err := s.ServeStdio(); err != nil {
@@ -184,6 +285,61 @@ func main() {
 	}
 }
 
+// serveTenants runs the HTTP transport in multi-tenant mode, where every client brings its own Slack
+// credentials and no tokens are read from the environment
+func serveTenants(transport string, enabledTools []string, logger *zap.Logger) {
+	if os.Getenv("SLACK_MCP_APP_TOKEN") != "" || os.Getenv("SLACK_MCP_SIGNING_SECRET") != "" {
+		logger.Warn("Slack events are not supported in multi-tenant mode, SLACK_MCP_APP_TOKEN and SLACK_MCP_SIGNING_SECRET are ignored",
+			zap.String("context", "console"),
+		)
+	}
+
+	if os.Getenv("SLACK_MCP_WORKSPACES") != "" {
+		logger.Warn("SLACK_MCP_WORKSPACES is ignored in multi-tenant mode, each client brings its own Slack credentials",
+			zap.String("context", "console"),
+		)
+	}
+
+	tenants := server.NewTenants(transport, logger, enabledTools)
+	ctx, cancel := context.WithCancel(context.Background())
+	go tenants.Run(ctx)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-sigChan
+		logger.Info("Received shutdown signal",
+			zap.String("context", "console"),
+			zap.String("signal", sig.String()))
+		cancel()
+		tenants.Close()
+		os.Exit(0)
+	}()
+
+	host := os.Getenv("SLACK_MCP_HOST")
+	if host == "" {
+		host = defaultSseHost
+	}
+	port := os.Getenv("SLACK_MCP_PORT")
+	if port == "" {
+		port = strconv.Itoa(defaultSsePort)
+	}
+
+	logger.Info(
+		fmt.Sprintf("Multi-tenant HTTP server listening on %s", fmt.Sprintf("%s:%s", host, port)),
+		zap.String("context", "console"),
+		zap.String("host", host),
+		zap.String("port", port),
+	)
+	httpServer := &http.Server{Addr: host + ":" + port, Handler: tenants.Handler()}
+	if err := httpServer.ListenAndServe(); err != nil {
+		logger.Fatal("Server error",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+}
+
 func newUsersWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Logger) func() {
 	return func() {
 		logger.Info("Caching users collection...",
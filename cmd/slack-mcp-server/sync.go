@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// runSync implements "slack-mcp-server sync": it syncs the channels of SLACK_MCP_SYNC_CHANNELS into the
+// message archive without serving MCP, once or until interrupted
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	once := fs.Bool("once", false, "Sync every channel once and exit instead of tailing them")
+	fs.Parse(args)
+
+	logger, err := newLogger("stdio")
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	patterns, err := provider.SyncPatterns()
+	if err == nil && len(patterns) == 0 {
+		err = fmt.Errorf("no channels to sync")
+	}
+	if err != nil {
+		logger.Fatal("error in SLACK_MCP_SYNC_CHANNELS",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	p := provider.New("stdio", logger)
+
+	syncer := p.Syncer()
+	if syncer == nil {
+		logger.Fatal("Message archive could not be opened",
+			zap.String("context", "console"),
+		)
+	}
+	for name, refresh := range map[string]func(context.Context) error{
+		"users":    p.RefreshUsers,
+		"channels": p.RefreshChannels,
+	} {
+		if err := refresh(ctx); err != nil {
+			logger.Fatal("Failed to cache "+name,
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+	}
+
+	logger.Info("Syncing channels",
+		zap.String("context", "console"),
+		zap.Strings("patterns", patterns),
+		zap.String("archive", p.MessageArchive().Location()),
+		zap.Int("channels", len(syncer.Channels())),
+	)
+	if *once {
+		err = syncer.SyncOnce(ctx)
+	} else {
+		syncer.Run(ctx)
+	}
+	if closeErr := p.Close(); closeErr != nil {
+		logger.Warn("Failed to close cache store", zap.String("context", "console"), zap.Error(closeErr))
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Error("Channel sync failed",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+		os.Exit(1)
+	}
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +18,12 @@ type CurrentUser struct {
 	TeamName     string `csv:"team_name"`
 	WorkspaceURL string `csv:"workspace_url"`
 	EnterpriseID string `csv:"enterprise_id,omitempty"`
+
+	// Do Not Disturb state, empty when it could not be fetched
+	DNDEnabled    string `csv:"dnd_enabled"`
+	NextDNDStart  string `csv:"next_dnd_start"`
+	NextDNDEnd    string `csv:"next_dnd_end"`
+	SnoozeEnabled string `csv:"snooze_enabled"`
 }
 
 type AuthHandler struct {
@@ -62,6 +70,8 @@ func (ah *AuthHandler) GetCurrentUserHandler(ctx context.Context, request mcp.Ca
 		currentUser.EnterpriseID = authResponse.EnterpriseID
 	}
 
+	ah.addDNDInfo(ctx, &currentUser)
+
 	// Format as CSV
 	csvBytes, err := gocsv.MarshalBytes([]*CurrentUser{&currentUser})
 	if err != nil {
@@ -76,3 +86,29 @@ func (ah *AuthHandler) GetCurrentUserHandler(ctx context.Context, request mcp.Ca
 
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
+
+// addDNDInfo fills the Do Not Disturb columns. Browser tokens already receive them in client.userBoot,
+// OAuth tokens use dnd.info. Failures only leave the columns empty.
+func (ah *AuthHandler) addDNDInfo(ctx context.Context, currentUser *CurrentUser) {
+	if !ah.apiProvider.IsOAuth() {
+		boot, err := ah.apiProvider.Slack().ClientUserBoot(ctx)
+		if err == nil {
+			currentUser.DNDEnabled = strconv.FormatBool(boot.DND.DNDEnabled)
+			currentUser.NextDNDStart = formatJSONTime(slack.JSONTime(int64(boot.DND.NextDNDStartTs)))
+			currentUser.NextDNDEnd = formatJSONTime(slack.JSONTime(int64(boot.DND.NextDNDEndTs)))
+			currentUser.SnoozeEnabled = strconv.FormatBool(boot.DND.SnoozeEnabled)
+			return
+		}
+		ah.logger.Debug("client.userBoot failed, falling back to dnd.info", zap.Error(err))
+	}
+
+	status, err := ah.apiProvider.Slack().GetDNDInfoContext(ctx, nil)
+	if err != nil {
+		ah.logger.Warn("Failed to get Do Not Disturb info", zap.Error(err))
+		return
+	}
+	currentUser.DNDEnabled = strconv.FormatBool(status.Enabled)
+	currentUser.NextDNDStart = formatJSONTime(slack.JSONTime(int64(status.NextStartTimestamp)))
+	currentUser.NextDNDEnd = formatJSONTime(slack.JSONTime(int64(status.NextEndTimestamp)))
+	currentUser.SnoozeEnabled = strconv.FormatBool(status.SnoozeEnabled)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// ChannelUpdated is the row returned by the channel lifecycle tools
+type ChannelUpdated struct {
+	ID         string `csv:"channel_id"`
+	Name       string `csv:"name"`
+	Topic      string `csv:"topic"`
+	Purpose    string `csv:"purpose"`
+	IsArchived bool   `csv:"is_archived"`
+}
+
+// SetChannelTopicHandler sets the topic of a channel. An empty topic clears it.
+func (ch *ChannelsHandler) SetChannelTopicHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("SetChannelTopicHandler called", zap.Any("params", request.Params))
+
+	topic, ok := request.GetArguments()["topic"].(string)
+	if !ok {
+		return mcp.NewToolResultError("topic must be provided, pass an empty string to clear it"), nil
+	}
+
+	channelID, err := ch.resolveLifecycleChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	channel, err := ch.apiProvider.Slack().SetTopicOfConversationContext(ctx, channelID, topic)
+	if err != nil {
+		ch.logger.Error("Failed to set channel topic", zap.String("channel_id", channelID), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to set channel topic", err), nil
+	}
+
+	return ch.channelUpdatedResult(ctx, channelID, channel, "Channel topic updated")
+}
+
+// SetChannelPurposeHandler sets the purpose (description) of a channel. An empty purpose clears it.
+func (ch *ChannelsHandler) SetChannelPurposeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("SetChannelPurposeHandler called", zap.Any("params", request.Params))
+
+	purpose, ok := request.GetArguments()["purpose"].(string)
+	if !ok {
+		return mcp.NewToolResultError("purpose must be provided, pass an empty string to clear it"), nil
+	}
+
+	channelID, err := ch.resolveLifecycleChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	channel, err := ch.apiProvider.Slack().SetPurposeOfConversationContext(ctx, channelID, purpose)
+	if err != nil {
+		ch.logger.Error("Failed to set channel purpose", zap.String("channel_id", channelID), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to set channel purpose", err), nil
+	}
+
+	return ch.channelUpdatedResult(ctx, channelID, channel, "Channel purpose updated")
+}
+
+// RenameChannelHandler renames a channel
+func (ch *ChannelsHandler) RenameChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("RenameChannelHandler called", zap.Any("params", request.Params))
+
+	name := strings.TrimPrefix(strings.TrimSpace(request.GetString("name", "")), "#")
+	if name == "" {
+		return mcp.NewToolResultError("name must be provided"), nil
+	}
+
+	channelID, err := ch.resolveLifecycleChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	channel, err := ch.apiProvider.Slack().RenameConversationContext(ctx, channelID, name)
+	if err != nil {
+		ch.logger.Error("Failed to rename channel",
+			zap.String("channel_id", channelID),
+			zap.String("name", name),
+			zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to rename channel", err), nil
+	}
+
+	ch.logger.Info("Channel renamed successfully",
+		zap.String("channel_id", channelID),
+		zap.String("name", name))
+
+	return ch.channelUpdatedResult(ctx, channelID, channel, "Channel renamed successfully")
+}
+
+// UnarchiveChannelHandler restores an archived channel
+func (ch *ChannelsHandler) UnarchiveChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("UnarchiveChannelHandler called", zap.Any("params", request.Params))
+
+	channelID, err := ch.resolveLifecycleChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	if err := ch.apiProvider.Slack().UnArchiveConversationContext(ctx, channelID); err != nil {
+		ch.logger.Error("Failed to unarchive channel", zap.String("channel_id", channelID), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to unarchive channel", err), nil
+	}
+
+	ch.logger.Info("Channel unarchived successfully", zap.String("channel_id", channelID))
+
+	// conversations.unarchive returns no channel object, the current state is fetched below
+	return ch.channelUpdatedResult(ctx, channelID, nil, "Channel unarchived successfully")
+}
+
+// resolveLifecycleChannel resolves a channel ID or #name. Archived channels are not part of the
+// channels cache, so unarchive_channel generally needs the channel ID.
+func (ch *ChannelsHandler) resolveLifecycleChannel(ctx context.Context, channel string) (string, error) {
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		return "", err
+	}
+	if channel == "" {
+		return "", errors.New("channel_id must be provided")
+	}
+	if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
+		return channel, nil
+	}
+	return NewConversationsHandler(ch.apiProvider, ch.logger).resolveChannelID(ctx, channel)
+}
+
+// channelUpdatedResult stores the changed channel in the channels cache right away and renders it.
+// When the API response carried no channel, its current state is fetched with conversations.info.
+// A dry run changed nothing and answered with a placeholder, so the cache is left alone.
+func (ch *ChannelsHandler) channelUpdatedResult(ctx context.Context, channelID string, channel *slack.Channel, header string) (*mcp.CallToolResult, error) {
+	dryRun := transport.IsDryRun(ctx)
+	if !dryRun && (channel == nil || channel.ID == "") {
+		info, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+			ChannelID: channelID,
+		})
+		if err != nil {
+			ch.logger.Warn("Failed to fetch channel info after update, cache not refreshed",
+				zap.String("channel_id", channelID),
+				zap.Error(err))
+		}
+		channel = info
+	}
+
+	row := ChannelUpdated{ID: channelID, Name: channelID}
+	if !dryRun && channel != nil && channel.ID != "" {
+		updated := ch.apiProvider.UpdateChannel(*channel)
+		row = ChannelUpdated{
+			ID:         channelID,
+			Name:       updated.Name,
+			Topic:      updated.Topic,
+			Purpose:    updated.Purpose,
+			IsArchived: updated.IsArchived,
+		}
+	}
+
+	rows := []ChannelUpdated{row}
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		ch.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# %s\n", header))
+	output.WriteString(fmt.Sprintf("# Channel ID: %s\n", channelID))
+	output.WriteString(fmt.Sprintf("# Channel Name: %s\n", row.Name))
+	output.Write(csvBytes)
+
+	return mcp.NewToolResultText(output.String()), nil
+}
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
@@ -45,39 +48,10 @@ func (ch *ChatHandler) ChatPostMessageHandler(ctx context.Context, request mcp.C
 		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
 	}
 
-	var options []slack.MsgOption
-	// Add reply_broadcast support
-	if params.threadTs != "" {
-		options = append(options, slack.MsgOptionTS(params.threadTs))
-
-		// Check if reply should be broadcast to channel
-		replyBroadcast := request.GetBool("reply_broadcast", false)
-		if replyBroadcast {
-			options = append(options, slack.MsgOptionBroadcast())
-		}
-	}
-
-	// Add text if provided (also serves as fallback when blocks present)
-	if params.text != "" {
-		options = append(options, slack.MsgOptionText(params.text, false))
-	}
-
-	// Add blocks if provided
-	if params.blocksJSON != "" {
-		var blocks slack.Blocks
-		if err := json.Unmarshal([]byte(params.blocksJSON), &blocks); err != nil {
-			ch.logger.Error("Failed to parse blocks JSON", zap.Error(err))
-			return mcp.NewToolResultErrorFromErr("Failed to parse blocks JSON", err), nil
-		}
-		options = append(options, slack.MsgOptionBlocks(blocks.BlockSet...))
-	}
-
-	unfurlOpt := os.Getenv("SLACK_MCP_ADD_MESSAGE_UNFURLING")
-	if text.IsUnfurlingEnabled(params.text, unfurlOpt, ch.logger) {
-		options = append(options, slack.MsgOptionEnableLinkUnfurl())
-	} else {
-		options = append(options, slack.MsgOptionDisableLinkUnfurl())
-		options = append(options, slack.MsgOptionDisableMediaUnfurl())
+	options, err := ch.buildAddMessageOptions(request, params)
+	if err != nil {
+		ch.logger.Error("Failed to parse blocks JSON", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse blocks JSON", err), nil
 	}
 
 	ch.logger.Debug("Posting Slack message",
@@ -119,6 +93,46 @@ func (ch *ChatHandler) ChatPostMessageHandler(ctx context.Context, request mcp.C
 	return marshalMessagesToCSV(messages)
 }
 
+// buildAddMessageOptions converts parsed add-message params into Slack message options.
+// Shared by post_message and schedule_message so both honour threads, blocks and unfurling the same way.
+func (ch *ChatHandler) buildAddMessageOptions(request mcp.CallToolRequest, params *addMessageParams) ([]slack.MsgOption, error) {
+	var options []slack.MsgOption
+	// Add reply_broadcast support
+	if params.threadTs != "" {
+		options = append(options, slack.MsgOptionTS(params.threadTs))
+
+		// Check if reply should be broadcast to channel
+		replyBroadcast := request.GetBool("reply_broadcast", false)
+		if replyBroadcast {
+			options = append(options, slack.MsgOptionBroadcast())
+		}
+	}
+
+	// Add text if provided (also serves as fallback when blocks present)
+	if params.text != "" {
+		options = append(options, slack.MsgOptionText(params.text, false))
+	}
+
+	// Add blocks if provided
+	if params.blocksJSON != "" {
+		var blocks slack.Blocks
+		if err := json.Unmarshal([]byte(params.blocksJSON), &blocks); err != nil {
+			return nil, err
+		}
+		options = append(options, slack.MsgOptionBlocks(blocks.BlockSet...))
+	}
+
+	unfurlOpt := os.Getenv("SLACK_MCP_ADD_MESSAGE_UNFURLING")
+	if text.IsUnfurlingEnabled(params.text, unfurlOpt, ch.logger) {
+		options = append(options, slack.MsgOptionEnableLinkUnfurl())
+	} else {
+		options = append(options, slack.MsgOptionDisableLinkUnfurl())
+		options = append(options, slack.MsgOptionDisableMediaUnfurl())
+	}
+
+	return options, nil
+}
+
 func (ch *ChatHandler) parseParamsToolAddMessage(request mcp.CallToolRequest) (*addMessageParams, error) {
 	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
 	if toolConfig == "" {
@@ -189,6 +203,278 @@ func (ch *ChatHandler) parseParamsToolAddMessage(request mcp.CallToolRequest) (*
 	}, nil
 }
 
+// maxScheduleAhead mirrors Slack's limit for chat.scheduleMessage post_at values.
+const maxScheduleAhead = 120 * 24 * time.Hour
+
+// ScheduledMessage is a pending message created by chat.scheduleMessage
+type ScheduledMessage struct {
+	ID          string `csv:"ID"`
+	Channel     string `csv:"Channel"`
+	PostAt      string `csv:"PostAt"`
+	DateCreated string `csv:"DateCreated"`
+	Text        string `csv:"Text"`
+}
+
+// ChatScheduleMessageHandler schedules a message for later delivery and returns the pending message as CSV
+func (ch *ChatHandler) ChatScheduleMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatScheduleMessageHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolAddMessage(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse schedule-message params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
+	}
+
+	postAtRaw := request.GetString("post_at", "")
+	if postAtRaw == "" {
+		ch.logger.Error("post_at missing in schedule-message params")
+		return mcp.NewToolResultError("post_at must be provided"), nil
+	}
+	now := time.Now()
+	postAt, err := parseFutureTime(postAtRaw, now)
+	if err != nil {
+		ch.logger.Error("Invalid post_at", zap.String("post_at", postAtRaw), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse post_at", err), nil
+	}
+	if postAt.Sub(now) > maxScheduleAhead {
+		return mcp.NewToolResultError(fmt.Sprintf("post_at %s is too far ahead: Slack only accepts messages scheduled up to 120 days in advance", postAt.Format(time.RFC3339))), nil
+	}
+
+	options, err := ch.buildAddMessageOptions(request, params)
+	if err != nil {
+		ch.logger.Error("Failed to parse blocks JSON", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse blocks JSON", err), nil
+	}
+
+	ch.logger.Debug("Scheduling Slack message",
+		zap.String("channel", params.channel),
+		zap.String("thread_ts", params.threadTs),
+		zap.Time("post_at", postAt),
+		zap.Bool("has_blocks", params.blocksJSON != ""),
+	)
+	respChannel, scheduledID, err := ch.apiProvider.Slack().ScheduleMessageContext(ctx, params.channel, strconv.FormatInt(postAt.Unix(), 10), options...)
+	if err != nil {
+		ch.logger.Error("Slack ScheduleMessageContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to schedule message", err), nil
+	}
+
+	result := []ScheduledMessage{{
+		ID:          scheduledID,
+		Channel:     respChannel,
+		PostAt:      postAt.Format(time.RFC3339),
+		DateCreated: now.Format(time.RFC3339),
+		Text:        params.text,
+	}}
+	csvBytes, err := gocsv.MarshalBytes(result)
+	if err != nil {
+		ch.logger.Error("Failed to marshal scheduled message to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format scheduled message", err), nil
+	}
+
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// ChatListScheduledMessagesHandler lists pending scheduled messages in channels the post_message policy allows
+func (ch *ChatHandler) ChatListScheduledMessagesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatListScheduledMessagesHandler called", zap.Any("params", request.Params))
+
+	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
+	if toolConfig == "" {
+		ch.logger.Error("List-scheduled-messages tool disabled by default")
+		return mcp.NewToolResultError(
+			"scheduled message tools follow the post_message policy and are disabled by default. " +
+				"To enable them, set the SLACK_MCP_ADD_MESSAGE_TOOL environment variable to true, 1, or comma separated list of channels",
+		), nil
+	}
+
+	channel := request.GetString("channel_id", "")
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
+		chn, ok := channelsMaps.ChannelsInv[channel]
+		if !ok {
+			ch.logger.Error("Channel not found", zap.String("channel", channel))
+			return mcp.NewToolResultError(fmt.Sprintf("channel %q not found", channel)), nil
+		}
+		channel = channelsMaps.Channels[chn].ID
+	}
+	if channel != "" && !isChannelAllowed(channel) {
+		ch.logger.Warn("List-scheduled-messages tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return mcp.NewToolResultError(fmt.Sprintf("list_scheduled_messages tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)), nil
+	}
+
+	limit := request.GetInt("limit", 100)
+	if limit > 1000 {
+		limit = 1000
+	}
+	cursor := request.GetString("cursor", "")
+
+	scheduled, nextCursor, err := ch.apiProvider.Slack().GetScheduledMessagesContext(ctx, &slack.GetScheduledMessagesParameters{
+		Channel: channel,
+		Cursor:  cursor,
+		Limit:   limit,
+	})
+	if err != nil {
+		ch.logger.Error("Slack GetScheduledMessagesContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to list scheduled messages", err), nil
+	}
+
+	result := make([]ScheduledMessage, 0, len(scheduled))
+	for _, sm := range scheduled {
+		// Without a channel filter Slack returns every pending message; hide the ones outside the policy
+		if !isChannelAllowed(sm.Channel) {
+			continue
+		}
+		result = append(result, ScheduledMessage{
+			ID:          sm.ID,
+			Channel:     sm.Channel,
+			PostAt:      time.Unix(int64(sm.PostAt), 0).Format(time.RFC3339),
+			DateCreated: time.Unix(int64(sm.DateCreated), 0).Format(time.RFC3339),
+			Text:        sm.Text,
+		})
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&result)
+	if err != nil {
+		ch.logger.Error("Failed to marshal scheduled messages to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format scheduled messages", err), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Total scheduled messages returned: %d\n", len(result)))
+	if nextCursor != "" {
+		sb.WriteString(fmt.Sprintf("# Next cursor: %s\n", nextCursor))
+	} else {
+		sb.WriteString("# Next cursor: (none - all scheduled messages returned)\n")
+	}
+	sb.Write(csvBytes)
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// ChatDeleteScheduledMessageHandler cancels a pending scheduled message
+func (ch *ChatHandler) ChatDeleteScheduledMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatDeleteScheduledMessageHandler called", zap.Any("params", request.Params))
+
+	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
+	if toolConfig == "" {
+		ch.logger.Error("Delete-scheduled-message tool disabled by default")
+		return mcp.NewToolResultError(
+			"scheduled message tools follow the post_message policy and are disabled by default. " +
+				"To enable them, set the SLACK_MCP_ADD_MESSAGE_TOOL environment variable to true, 1, or comma separated list of channels",
+		), nil
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		ch.logger.Error("channel_id missing in delete-scheduled-message params")
+		return mcp.NewToolResultError("channel_id must be provided"), nil
+	}
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
+		chn, ok := channelsMaps.ChannelsInv[channel]
+		if !ok {
+			ch.logger.Error("Channel not found", zap.String("channel", channel))
+			return mcp.NewToolResultError(fmt.Sprintf("channel %q not found", channel)), nil
+		}
+		channel = channelsMaps.Channels[chn].ID
+	}
+	if !isChannelAllowed(channel) {
+		ch.logger.Warn("Delete-scheduled-message tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return mcp.NewToolResultError(fmt.Sprintf("delete_scheduled_message tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)), nil
+	}
+
+	scheduledID := request.GetString("scheduled_message_id", "")
+	if scheduledID == "" {
+		ch.logger.Error("scheduled_message_id missing in delete-scheduled-message params")
+		return mcp.NewToolResultError("scheduled_message_id must be provided"), nil
+	}
+
+	ch.logger.Debug("Deleting scheduled Slack message",
+		zap.String("channel", channel),
+		zap.String("scheduled_message_id", scheduledID),
+	)
+	if _, err := ch.apiProvider.Slack().DeleteScheduledMessageContext(ctx, &slack.DeleteScheduledMessageParameters{
+		Channel:            channel,
+		ScheduledMessageID: scheduledID,
+	}); err != nil {
+		ch.logger.Error("Slack DeleteScheduledMessageContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to delete scheduled message", err), nil
+	}
+
+	type DeleteScheduledResult struct {
+		ID      string `csv:"ID"`
+		Channel string `csv:"Channel"`
+		Status  string `csv:"Status"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]DeleteScheduledResult{{
+		ID:      scheduledID,
+		Channel: channel,
+		Status:  "deleted",
+	}})
+	if err != nil {
+		ch.logger.Error("Failed to marshal delete result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format delete result", err), nil
+	}
+
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// ChatGetPermalinkHandler returns the permalink of a message
+func (ch *ChatHandler) ChatGetPermalinkHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatGetPermalinkHandler called", zap.Any("params", request.Params))
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return mcp.NewToolResultError("channel_id must be provided"), nil
+	}
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
+		chn, ok := channelsMaps.ChannelsInv[channel]
+		if !ok {
+			ch.logger.Error("Channel not found", zap.String("channel", channel))
+			return mcp.NewToolResultError(fmt.Sprintf("channel %q not found", channel)), nil
+		}
+		channel = channelsMaps.Channels[chn].ID
+	}
+
+	timestamp := request.GetString("timestamp", "")
+	if timestamp == "" {
+		return mcp.NewToolResultError("timestamp must be provided"), nil
+	}
+	if !strings.Contains(timestamp, ".") {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid timestamp format: %s (must be like 1234567890.123456)", timestamp)), nil
+	}
+
+	permalink, err := ch.apiProvider.Slack().GetPermalinkContext(ctx, &slack.PermalinkParameters{
+		Channel: channel,
+		Ts:      timestamp,
+	})
+	if err != nil {
+		ch.logger.Error("Slack GetPermalinkContext failed",
+			zap.String("channel", channel),
+			zap.String("timestamp", timestamp),
+			zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to get permalink", err), nil
+	}
+
+	type PermalinkResult struct {
+		Channel   string `csv:"Channel"`
+		Timestamp string `csv:"Timestamp"`
+		Permalink string `csv:"Permalink"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]PermalinkResult{{
+		Channel:   channel,
+		Timestamp: timestamp,
+		Permalink: permalink,
+	}})
+	if err != nil {
+		ch.logger.Error("Failed to marshal permalink to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format permalink", err), nil
+	}
+
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
 // ChatDeleteMessageHandler deletes a message from a channel
 func (ch *ChatHandler) ChatDeleteMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	ch.logger.Debug("ChatDeleteMessageHandler called", zap.Any("params", request.Params))
@@ -873,6 +1159,250 @@ func (ch *ChatHandler) parseParamsToolAddMessageAsBot(request mcp.CallToolReques
 	}, nil
 }
 
+// EphemeralResult is returned by the ephemeral tools. Ephemeral messages are not stored by Slack,
+// so unlike post_message there is no message to fetch back from the channel history.
+type EphemeralResult struct {
+	Channel   string `csv:"Channel"`
+	UserID    string `csv:"UserID"`
+	UserName  string `csv:"UserName"`
+	Timestamp string `csv:"Timestamp"`
+	Status    string `csv:"Status"`
+}
+
+// ChatPostEphemeralHandler posts a message only one member of the channel can see
+func (ch *ChatHandler) ChatPostEphemeralHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatPostEphemeralHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolAddMessage(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse post-ephemeral params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
+	}
+
+	userID, userName, err := ch.resolveEphemeralUser(request)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve user", err), nil
+	}
+
+	options, err := ch.buildAddMessageOptions(request, params)
+	if err != nil {
+		ch.logger.Error("Failed to parse blocks JSON", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse blocks JSON", err), nil
+	}
+
+	ch.logger.Debug("Posting ephemeral Slack message",
+		zap.String("channel", params.channel),
+		zap.String("user", userID),
+		zap.String("thread_ts", params.threadTs),
+	)
+	respTimestamp, err := ch.apiProvider.Slack().PostEphemeralContext(ctx, params.channel, userID, options...)
+	if err != nil {
+		ch.logger.Error("Slack PostEphemeralContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to post ephemeral message", err), nil
+	}
+
+	return ch.ephemeralResult(params.channel, userID, userName, respTimestamp, "sent_ephemeral")
+}
+
+// ChatPostEphemeralAsBotHandler posts a message only one member of the channel can see, as the bot user
+func (ch *ChatHandler) ChatPostEphemeralAsBotHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatPostEphemeralAsBotHandler called", zap.Any("params", request.Params))
+
+	if !ch.apiProvider.HasSlackBot() {
+		ch.logger.Error("Bot posting not available - SLACK_MCP_BOT_TOKEN not configured")
+		return mcp.NewToolResultError(
+			"Bot posting is not available. To enable it, set the SLACK_MCP_BOT_TOKEN environment variable " +
+				"to a valid Slack bot token (xoxb-...). This token is separate from your user token.",
+		), nil
+	}
+
+	params, err := ch.parseParamsToolAddMessageAsBot(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse post-ephemeral-as-bot params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
+	}
+
+	userID, userName, err := ch.resolveEphemeralUser(request)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve user", err), nil
+	}
+
+	options, err := ch.buildAddMessageOptions(request, params)
+	if err != nil {
+		ch.logger.Error("Failed to parse blocks JSON", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse blocks JSON", err), nil
+	}
+
+	ch.logger.Debug("Posting ephemeral Slack message as bot",
+		zap.String("channel", params.channel),
+		zap.String("user", userID),
+		zap.String("thread_ts", params.threadTs),
+	)
+	respTimestamp, err := ch.apiProvider.SlackBot().PostEphemeralContext(ctx, params.channel, userID, options...)
+	if err != nil {
+		ch.logger.Error("Slack PostEphemeralContext (bot) failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to post ephemeral message as bot", err), nil
+	}
+
+	return ch.ephemeralResult(params.channel, userID, userName, respTimestamp, "sent_ephemeral_as_bot")
+}
+
+// ChatPostMeMessageHandler posts a /me message ("_username is deploying_") as the authenticated user
+func (ch *ChatHandler) ChatPostMeMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatPostMeMessageHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolAddMessage(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse me-message params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
+	}
+	if params.text == "" || params.blocksJSON != "" {
+		return mcp.NewToolResultError("post_me_message supports plain text only, text must be provided and blocks are not allowed"), nil
+	}
+
+	ch.logger.Debug("Posting Slack me message", zap.String("channel", params.channel))
+	respChannel, respTimestamp, err := ch.apiProvider.Slack().PostMessageContext(ctx, params.channel,
+		slack.MsgOptionText(params.text, false),
+		slack.MsgOptionMeMessage(),
+	)
+	if err != nil {
+		ch.logger.Error("Slack PostMessageContext (me message) failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to post me message", err), nil
+	}
+
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: respChannel,
+		Limit:     1,
+		Oldest:    respTimestamp,
+		Latest:    respTimestamp,
+		Inclusive: true,
+	}
+	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+	if err != nil {
+		ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to fetch posted message", err), nil
+	}
+
+	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false)
+	return marshalMessagesToCSV(messages)
+}
+
+// ChatPostMeMessageAsBotHandler posts a /me message as the bot user
+func (ch *ChatHandler) ChatPostMeMessageAsBotHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChatPostMeMessageAsBotHandler called", zap.Any("params", request.Params))
+
+	if !ch.apiProvider.HasSlackBot() {
+		ch.logger.Error("Bot posting not available - SLACK_MCP_BOT_TOKEN not configured")
+		return mcp.NewToolResultError(
+			"Bot posting is not available. To enable it, set the SLACK_MCP_BOT_TOKEN environment variable " +
+				"to a valid Slack bot token (xoxb-...). This token is separate from your user token.",
+		), nil
+	}
+
+	params, err := ch.parseParamsToolAddMessageAsBot(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse me-message-as-bot params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse message parameters", err), nil
+	}
+	if params.text == "" || params.blocksJSON != "" {
+		return mcp.NewToolResultError("post_me_message_as_bot supports plain text only, text must be provided and blocks are not allowed"), nil
+	}
+
+	ch.logger.Debug("Posting Slack me message as bot", zap.String("channel", params.channel))
+	respChannel, respTimestamp, err := ch.apiProvider.SlackBot().PostMessageContext(ctx, params.channel,
+		slack.MsgOptionText(params.text, false),
+		slack.MsgOptionMeMessage(),
+	)
+	if err != nil {
+		ch.logger.Error("Slack PostMessageContext (bot me message) failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to post me message as bot", err), nil
+	}
+
+	// The bot might not be able to read history, so fall back to a minimal response like post_message_as_bot
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: respChannel,
+		Limit:     1,
+		Oldest:    respTimestamp,
+		Latest:    respTimestamp,
+		Inclusive: true,
+	}
+	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+	if err != nil {
+		ch.logger.Warn("GetConversationHistoryContext failed (returning minimal response)", zap.Error(err))
+		type PostResult struct {
+			Channel   string `csv:"Channel"`
+			Timestamp string `csv:"Timestamp"`
+			Status    string `csv:"Status"`
+		}
+		csvBytes, _ := gocsv.MarshalBytes([]PostResult{{
+			Channel:   respChannel,
+			Timestamp: respTimestamp,
+			Status:    "posted_as_bot",
+		}})
+		return mcp.NewToolResultText(string(csvBytes)), nil
+	}
+
+	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false)
+	return marshalMessagesToCSV(messages)
+}
+
+// resolveEphemeralUser resolves the recipient of an ephemeral message from a user ID, @handle, or a bare
+// display or real name
+func (ch *ChatHandler) resolveEphemeralUser(request mcp.CallToolRequest) (string, string, error) {
+	raw := strings.TrimSpace(request.GetString("user", ""))
+	if raw == "" {
+		return "", "", errors.New("user must be provided")
+	}
+	usersMap := ch.apiProvider.ProvideUsersMap()
+	var (
+		userID string
+		err    error
+	)
+	if kind, _ := classifyMembershipEntry(raw); kind == membershipEntryInvalid {
+		userID, err = resolveDisplayName(raw, usersMap.Users)
+	} else {
+		userID, err = resolveUserID(raw, usersMap.Users, usersMap.UsersInv)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	userName, _, _ := getUserInfo(userID, usersMap.Users)
+	return userID, userName, nil
+}
+
+// resolveDisplayName finds the one user whose display name or real name is name, ignoring case
+func resolveDisplayName(name string, usersMap map[string]slack.User) (string, error) {
+	var matches []string
+	for id, u := range usersMap {
+		if strings.EqualFold(u.Profile.DisplayName, name) || strings.EqualFold(u.RealName, name) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("user %q not found, expected a user ID, @handle or display name", name)
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return "", fmt.Errorf("display name %q matches several users (%s), use a user ID or @handle", name, strings.Join(matches, ", "))
+}
+
+func (ch *ChatHandler) ephemeralResult(channel, userID, userName, timestamp, status string) (*mcp.CallToolResult, error) {
+	csvBytes, err := gocsv.MarshalBytes([]EphemeralResult{{
+		Channel:   channel,
+		UserID:    userID,
+		UserName:  userName,
+		Timestamp: timestamp,
+		Status:    status,
+	}})
+	if err != nil {
+		ch.logger.Error("Failed to marshal ephemeral result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result", err), nil
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
 // isChannelAllowedForBot checks if bot posting is allowed for a channel
 func isChannelAllowedForBot(channel string) bool {
 	// First check bot-specific config
@@ -1113,6 +1643,20 @@ func (ch *ChatHandler) convertMessagesFromHistory(slackMessages []slack.Message,
 func (ch *ChatHandler) GetSlackTemplatesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	ch.logger.Debug("GetSlackTemplatesHandler called")
 
+	content, foundPath, err := ReadSlackTemplates()
+	if err != nil {
+		ch.logger.Error("Failed to read SLACK_TEMPLATES.md from any path", zap.Error(err))
+		return mcp.NewToolResultError("Failed to read templates file. Ensure SLACK_TEMPLATES.md exists in the project root."), nil
+	}
+
+	ch.logger.Debug("Successfully read SLACK_TEMPLATES.md",
+		zap.String("path", foundPath),
+		zap.Int("size_bytes", len(content)))
+	return mcp.NewToolResultText(string(content)), nil
+}
+
+// ReadSlackTemplates reads SLACK_TEMPLATES.md and returns its content and the path it was found at
+func ReadSlackTemplates() ([]byte, string, error) {
 	// Try multiple paths to find SLACK_TEMPLATES.md
 	paths := []string{
 		"SLACK_TEMPLATES.md",                               // Current directory
@@ -1121,25 +1665,13 @@ func (ch *ChatHandler) GetSlackTemplatesHandler(ctx context.Context, request mcp
 		"/Users/chris/slack-mcp-server/SLACK_TEMPLATES.md", // Absolute path (fallback)
 	}
 
-	var content []byte
 	var err error
-	var foundPath string
-
 	for _, path := range paths {
+		var content []byte
 		content, err = os.ReadFile(path)
 		if err == nil {
-			foundPath = path
-			break
+			return content, path, nil
 		}
 	}
-
-	if err != nil {
-		ch.logger.Error("Failed to read SLACK_TEMPLATES.md from any path", zap.Error(err))
-		return mcp.NewToolResultError("Failed to read templates file. Ensure SLACK_TEMPLATES.md exists in the project root."), nil
-	}
-
-	ch.logger.Debug("Successfully read SLACK_TEMPLATES.md",
-		zap.String("path", foundPath),
-		zap.Int("size_bytes", len(content)))
-	return mcp.NewToolResultText(string(content)), nil
+	return nil, "", err
 }
@@ -21,6 +21,27 @@ func getUserInfo(userID string, usersMap map[string]slack.User) (userName, realN
 	return userID, userID, false
 }
 
+// resolveUserID resolves a user ID, @handle or <@U...> mention to a user ID using the users cache.
+// IDs are passed through even when the user is not cached (e.g. external Slack Connect users).
+func resolveUserID(raw string, usersMap map[string]slack.User, usersInv map[string]string) (string, error) {
+	kind, value := classifyMembershipEntry(raw)
+	switch kind {
+	case membershipEntryUserID:
+		return value, nil
+	case membershipEntryHandle:
+		if id, ok := usersInv[value]; ok {
+			return id, nil
+		}
+		for id, u := range usersMap {
+			if strings.EqualFold(u.Profile.DisplayName, value) {
+				return id, nil
+			}
+		}
+		return "", fmt.Errorf("user @%s not found", value)
+	}
+	return "", fmt.Errorf("invalid user %q, expected a user ID or @handle", raw)
+}
+
 func getBotInfo(botID string, apiProvider *provider.ApiProvider) (slack.User, bool) {
 	return apiProvider.ResolveBotIDToUser(botID)
 }
@@ -112,6 +133,7 @@ func parseMessageFields(fields string) map[string]bool {
 		requestedFields["time"] = true
 		requestedFields["reactions"] = true
 		requestedFields["files"] = true
+		requestedFields["permalink"] = true
 		requestedFields["cursor"] = true
 	} else {
 		// Parse comma-separated fields
@@ -147,6 +169,7 @@ func marshalMessagesWithFields(messages []Message, fields map[string]bool, inclu
 		{"reactions", "Reactions"},
 		{"files", "Files"},
 		{"filesFull", "FilesFull"},
+		{"permalink", "Permalink"},
 		{"cursor", "Cursor"},
 	}
 
@@ -196,6 +219,8 @@ func marshalMessagesWithFields(messages []Message, fields map[string]bool, inclu
 				row = append(row, msg.Files)
 			case "filesFull":
 				row = append(row, msg.FilesFull)
+			case "permalink":
+				row = append(row, msg.Permalink)
 			case "cursor":
 				row = append(row, msg.Cursor)
 			}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxConfirmationPreview caps how much of a message is quoted in a confirmation prompt
+const maxConfirmationPreview = 300
+
+// ConfirmationSummarizer describes what a tool call is about to change, so a person can approve it
+// before it runs. The summary is built from the call arguments: a channel_id is shown by name, a
+// channel_id with a timestamp quotes the message, and usergroup_id with users shows the member diff.
+type ConfirmationSummarizer struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewConfirmationSummarizer(apiProvider *provider.ApiProvider, logger *zap.Logger) *ConfirmationSummarizer {
+	return &ConfirmationSummarizer{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// Summarize renders a plain-text summary of the call. Lookups that fail are reported in the summary
+// rather than as errors, the person approving should still see what is known.
+func (cs *ConfirmationSummarizer) Summarize(ctx context.Context, tool string, args map[string]any) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Allow %s?\n", tool)
+
+	stringArg := func(key string) string {
+		v, _ := args[key].(string)
+		return strings.TrimSpace(v)
+	}
+	shown := map[string]bool{"dry_run": true}
+
+	if channel := stringArg("channel_id"); channel != "" {
+		shown["channel_id"] = true
+		channelID, label := cs.channelLabel(ctx, channel)
+		fmt.Fprintf(&b, "\nChannel: %s\n", label)
+
+		if ts := stringArg("timestamp"); ts != "" {
+			shown["timestamp"] = true
+			fmt.Fprintf(&b, "Message: %s\n", cs.messagePreview(ctx, channelID, ts))
+		}
+	}
+
+	if usergroupID := stringArg("usergroup_id"); usergroupID != "" {
+		shown["usergroup_id"] = true
+		fmt.Fprintf(&b, "\nUser group: %s\n", cs.usergroupLabel(ctx, usergroupID))
+		if _, ok := args["users"]; ok {
+			shown["users"] = true
+			b.WriteString(cs.memberDiff(ctx, usergroupID, parseCommaSeparatedList(stringArg("users"))))
+		}
+	}
+
+	var rest []string
+	for key := range args {
+		if !shown[key] {
+			rest = append(rest, key)
+		}
+	}
+	if len(rest) > 0 {
+		sort.Strings(rest)
+		b.WriteString("\nArguments:\n")
+		for _, key := range rest {
+			fmt.Fprintf(&b, "  %s: %s\n", key, truncatePreview(fmt.Sprint(args[key])))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// channelLabel resolves a channel ID or name to "#name (C...)" using the channels cache, falling back
+// to conversations.info for channels the cache does not know (archived, or not yet synced)
+func (cs *ConfirmationSummarizer) channelLabel(ctx context.Context, channel string) (string, string) {
+	channelsMaps := cs.apiProvider.ProvideChannelsMaps()
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		id, ok := channelsMaps.ChannelsInv[channel]
+		if !ok {
+			return channel, channel + " (not found)"
+		}
+		channel = id
+	}
+
+	if c, ok := channelsMaps.Channels[channel]; ok {
+		return channel, fmt.Sprintf("%s (%s, %d members)", c.Name, channel, c.MemberCount)
+	}
+	info, err := cs.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channel})
+	if err != nil || info == nil {
+		cs.logger.Debug("Channel lookup for confirmation failed", zap.String("channel", channel), zap.Error(err))
+		return channel, channel
+	}
+	return channel, fmt.Sprintf("#%s (%s)", info.Name, channel)
+}
+
+func (cs *ConfirmationSummarizer) messagePreview(ctx context.Context, channelID, ts string) string {
+	history, err := cs.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Limit:     1,
+		Oldest:    ts,
+		Latest:    ts,
+		Inclusive: true,
+	})
+	var msg *slack.Message
+	if err == nil && len(history.Messages) > 0 {
+		msg = &history.Messages[0]
+	} else {
+		// Thread replies are not part of the channel history
+		replies, _, _, repliesErr := cs.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+			ChannelID: channelID,
+			Timestamp: ts,
+			Limit:     1,
+			Oldest:    ts,
+			Latest:    ts,
+			Inclusive: true,
+		})
+		for i := range replies {
+			if replies[i].Timestamp == ts {
+				msg = &replies[i]
+				break
+			}
+		}
+		if msg == nil {
+			cs.logger.Debug("Message lookup for confirmation failed", zap.String("channel", channelID), zap.String("ts", ts), zap.Error(repliesErr))
+			return ts + " (message not found)"
+		}
+	}
+
+	author := msg.User
+	if userName, _, ok := getUserInfo(msg.User, cs.apiProvider.ProvideUsersMap().Users); ok {
+		author = "@" + userName
+	} else if msg.Username != "" {
+		author = msg.Username
+	}
+	when, err := text.TimestampToIsoRFC3339(ts)
+	if err != nil {
+		when = ts
+	}
+	return fmt.Sprintf("%s at %s\n> %s", author, when, truncatePreview(text.ProcessText(msg.Text)))
+}
+
+func (cs *ConfirmationSummarizer) usergroupLabel(ctx context.Context, usergroupID string) string {
+	groups, err := cs.apiProvider.Slack().GetUserGroupsContext(ctx, slack.GetUserGroupsOptionIncludeDisabled(true))
+	if err != nil {
+		return usergroupID
+	}
+	for _, g := range groups {
+		if g.ID == usergroupID {
+			return fmt.Sprintf("@%s %q (%s)", g.Handle, g.Name, usergroupID)
+		}
+	}
+	return usergroupID + " (not found)"
+}
+
+// memberDiff compares the requested member list of a user group with its current members
+func (cs *ConfirmationSummarizer) memberDiff(ctx context.Context, usergroupID string, requested []string) string {
+	current, err := cs.apiProvider.Slack().GetUserGroupMembersContext(ctx, usergroupID)
+	if err != nil {
+		return fmt.Sprintf("Current members could not be fetched (%v), new member list: %s\n", err, cs.userList(requested))
+	}
+
+	added, removed, kept := diffMembers(current, requested)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Added (%d): %s\n", len(added), cs.userList(added))
+	fmt.Fprintf(&b, "Removed (%d): %s\n", len(removed), cs.userList(removed))
+	fmt.Fprintf(&b, "Unchanged: %d\n", len(kept))
+	return b.String()
+}
+
+func (cs *ConfirmationSummarizer) userList(ids []string) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	users := cs.apiProvider.ProvideUsersMap().Users
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if userName, _, ok := getUserInfo(id, users); ok {
+			names = append(names, "@"+userName)
+		} else {
+			names = append(names, id)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// diffMembers splits the requested members into those being added, the current members being
+// removed, and those present in both. Results keep the order of their input lists.
+func diffMembers(current, requested []string) (added, removed, kept []string) {
+	inCurrent := make(map[string]bool, len(current))
+	for _, id := range current {
+		inCurrent[id] = true
+	}
+	inRequested := make(map[string]bool, len(requested))
+	for _, id := range requested {
+		inRequested[id] = true
+		if inCurrent[id] {
+			kept = append(kept, id)
+		} else {
+			added = append(added, id)
+		}
+	}
+	for _, id := range current {
+		if !inRequested[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed, kept
+}
+
+func truncatePreview(s string) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > maxConfirmationPreview {
+		return string(r[:maxConfirmationPreview]) + "…"
+	}
+	return s
+}
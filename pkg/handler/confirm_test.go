@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitDiffMembers(t *testing.T) {
+	added, removed, kept := diffMembers(
+		[]string{"U01AAAAAAAA", "U02BBBBBBBB", "U03CCCCCCCC"},
+		[]string{"U03CCCCCCCC", "U04DDDDDDDD", "U01AAAAAAAA"},
+	)
+	assert.Equal(t, []string{"U04DDDDDDDD"}, added)
+	assert.Equal(t, []string{"U02BBBBBBBB"}, removed)
+	assert.Equal(t, []string{"U03CCCCCCCC", "U01AAAAAAAA"}, kept)
+
+	added, removed, kept = diffMembers(nil, []string{"U01AAAAAAAA"})
+	assert.Equal(t, []string{"U01AAAAAAAA"}, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, kept)
+}
+
+func TestUnitTruncatePreview(t *testing.T) {
+	assert.Equal(t, "short", truncatePreview("  short \n"))
+	long := truncatePreview(string(make([]rune, maxConfirmationPreview+10)))
+	assert.Len(t, []rune(long), maxConfirmationPreview+1)
+}
@@ -46,6 +46,7 @@ type Message struct {
 	FileCount     int    `json:"fileCount,omitempty"`
 	AttachmentIDs string `json:"attachmentIDs,omitempty"`
 	HasMedia      bool   `json:"hasMedia,omitempty"`
+	Permalink     string `json:"permalink,omitempty"`
 	Cursor        string `json:"cursor,omitempty"`
 }
 
@@ -512,21 +513,32 @@ func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context,
 	requestedFields := parseMessageFields(fields)
 	ch.logger.Debug("Requested fields", zap.Any("fields", requestedFields))
 
-	historyParams := slack.GetConversationHistoryParameters{
-		ChannelID: params.channel,
-		Limit:     params.limit,
-		Oldest:    params.oldest,
-		Latest:    params.latest,
-		Cursor:    params.cursor,
-		Inclusive: false,
-	}
-	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
-	if err != nil {
-		ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
-		return mcp.NewToolResultErrorFromErr("Failed to fetch conversation history", err), nil
+	// Synced channels are read from the archive, unless paging needs Slack's cursors
+	var history *slack.GetConversationHistoryResponse
+	if params.cursor == "" && !requestedFields["cursor"] {
+		if archived, ok := ch.apiProvider.SyncedHistory(params.channel, params.oldest, params.latest, params.limit); ok {
+			ch.logger.Debug("Read conversation history from the message archive", zap.Int("message_count", len(archived)))
+			history = &slack.GetConversationHistoryResponse{Messages: archived}
+		}
 	}
+	if history == nil {
+		historyParams := slack.GetConversationHistoryParameters{
+			ChannelID: params.channel,
+			Limit:     params.limit,
+			Oldest:    params.oldest,
+			Latest:    params.latest,
+			Cursor:    params.cursor,
+			Inclusive: false,
+		}
+		history, err = ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to fetch conversation history", err), nil
+		}
 
-	ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
+		ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
+		ch.apiProvider.ArchiveHistory(params.channel, history.Messages)
+	}
 
 	messages := ch.convertMessagesFromHistoryWithFields(history.Messages, params.channel, params.activity, requestedFields)
 
@@ -562,21 +574,30 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 	requestedFields := parseMessageFields(fields)
 	ch.logger.Debug("Requested fields", zap.Any("fields", requestedFields))
 
-	repliesParams := slack.GetConversationRepliesParameters{
-		ChannelID: params.channel,
-		Timestamp: threadTs,
-		Limit:     params.limit,
-		Oldest:    params.oldest,
-		Latest:    params.latest,
-		Cursor:    params.cursor,
-		Inclusive: false,
+	replies, fromArchive := []slack.Message(nil), false
+	if params.cursor == "" {
+		replies, fromArchive = ch.apiProvider.SyncedReplies(params.channel, threadTs, params.oldest, params.latest, params.limit)
 	}
-	replies, _, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &repliesParams)
-	if err != nil {
-		ch.logger.Error("GetConversationRepliesContext failed", zap.Error(err))
-		return mcp.NewToolResultErrorFromErr("Failed to fetch conversation replies", err), nil
+	if fromArchive {
+		ch.logger.Debug("Read conversation replies from the message archive", zap.Int("count", len(replies)))
+	} else {
+		repliesParams := slack.GetConversationRepliesParameters{
+			ChannelID: params.channel,
+			Timestamp: threadTs,
+			Limit:     params.limit,
+			Oldest:    params.oldest,
+			Latest:    params.latest,
+			Cursor:    params.cursor,
+			Inclusive: false,
+		}
+		replies, _, _, err = ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &repliesParams)
+		if err != nil {
+			ch.logger.Error("GetConversationRepliesContext failed", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to fetch conversation replies", err), nil
+		}
+		ch.logger.Debug("Fetched conversation replies", zap.Int("count", len(replies)))
+		ch.apiProvider.ArchiveHistory(params.channel, replies)
 	}
-	ch.logger.Debug("Fetched conversation replies", zap.Int("count", len(replies)))
 
 	messages := ch.convertMessagesFromHistoryWithFields(replies, params.channel, params.activity, requestedFields)
 
@@ -1426,7 +1447,7 @@ func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel st
 	ch.logger.Debug("Channel not found in cache, attempting refresh",
 		zap.String("channel", channel))
 
-	refreshErr := ch.apiProvider.ForceRefreshChannels(ctx)
+	refreshErr := ch.apiProvider.ForceRefreshChannels(ctx, channel)
 	wasRateLimited := errors.Is(refreshErr, provider.ErrRefreshRateLimited)
 
 	if refreshErr != nil && !wasRateLimited {
@@ -1563,6 +1584,12 @@ func (ch *ConversationsHandler) convertMessagesFromHistoryWithFields(slackMessag
 	needText := fields["text"]
 	needTime := fields["time"]
 
+	// Permalinks are built from the workspace URL instead of a chat.getPermalink call per message
+	var workspaceURL string
+	if fields["permalink"] {
+		workspaceURL = ch.apiProvider.WorkspaceURL()
+	}
+
 	for _, msg := range slackMessages {
 		// Skip activity messages unless specifically requested
 		// Common message subtypes that should be included:
@@ -1674,6 +1701,7 @@ func (ch *ConversationsHandler) convertMessagesFromHistoryWithFields(slackMessag
 			Reactions: parsedReactions,
 			Files:     parsedFiles,
 			FilesFull: parsedFilesFull,
+			Permalink: text.MessagePermalink(workspaceURL, channelID, msg.Timestamp, msg.ThreadTimestamp),
 		})
 	}
 
@@ -2036,7 +2064,7 @@ func (ch *ConversationsHandler) parseParamsToolMark(request mcp.CallToolRequest)
 
 func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*searchParams, error) {
 	rawQuery := strings.TrimSpace(req.GetString("search_query", ""))
-	freeText, filters := splitQuery(rawQuery)
+	freeText, filters := provider.SplitSearchQuery(rawQuery)
 
 	if req.GetBool("filter_threads_only", false) {
 		addFilter(filters, "is", "thread")
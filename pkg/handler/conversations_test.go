@@ -185,226 +185,6 @@ func TestIntegrationConversations(t *testing.T) {
 	}
 }
 
-func TestUnitParseFlexibleDate(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		wantDate string
-		wantErr  bool
-	}{
-		// Standard formats (existing)
-		{
-			name:     "YYYY-MM-DD",
-			input:    "2025-07-15",
-			wantDate: "2025-07-15",
-			wantErr:  false,
-		},
-		{
-			name:     "YYYY/MM/DD",
-			input:    "2025/07/15",
-			wantDate: "2025-07-15",
-			wantErr:  false,
-		},
-
-		// New flexible month-year formats
-		{
-			name:     "Month Year - July 2025",
-			input:    "July 2025",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Year Month - 2025 July",
-			input:    "2025 July",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Abbreviated Month Year - Jul 2025",
-			input:    "Jul 2025",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Year Abbreviated Month - 2025 Jul",
-			input:    "2025 Jul",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Case insensitive - july 2025",
-			input:    "july 2025",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Case insensitive - JULY 2025",
-			input:    "JULY 2025",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-
-		// Day-Month-Year formats
-		{
-			name:     "1-July-2025",
-			input:    "1-July-2025",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "July-25-2025",
-			input:    "July-25-2025",
-			wantDate: "2025-07-25",
-			wantErr:  false,
-		},
-		{
-			name:     "July 10 2025",
-			input:    "July 10 2025",
-			wantDate: "2025-07-10",
-			wantErr:  false,
-		},
-		{
-			name:     "10 July 2025",
-			input:    "10 July 2025",
-			wantDate: "2025-07-10",
-			wantErr:  false,
-		},
-		{
-			name:     "31-December-2025",
-			input:    "31-December-2025",
-			wantDate: "2025-12-31",
-			wantErr:  false,
-		},
-		{
-			name:     "2025 July 10",
-			input:    "2025 July 10",
-			wantDate: "2025-07-10",
-			wantErr:  false,
-		},
-
-		// Various month names
-		{
-			name:     "January full name",
-			input:    "January 2025",
-			wantDate: "2025-01-01",
-			wantErr:  false,
-		},
-		{
-			name:     "February abbreviated",
-			input:    "Feb 2025",
-			wantDate: "2025-02-01",
-			wantErr:  false,
-		},
-		{
-			name:     "September with Sept abbreviation",
-			input:    "Sept 2025",
-			wantDate: "2025-09-01",
-			wantErr:  false,
-		},
-
-		// Relative dates
-		{
-			name:     "today",
-			input:    "today",
-			wantDate: time.Now().UTC().Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "yesterday",
-			input:    "yesterday",
-			wantDate: time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "Today with capital T",
-			input:    "Today",
-			wantDate: time.Now().UTC().Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "Yesterday with capital Y",
-			input:    "Yesterday",
-			wantDate: time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "TODAY all caps",
-			input:    "TODAY",
-			wantDate: time.Now().UTC().Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "YESTERDAY all caps",
-			input:    "YESTERDAY",
-			wantDate: time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "tomorrow",
-			input:    "tomorrow",
-			wantDate: time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "5 days ago",
-			input:    "5 days ago",
-			wantDate: time.Now().UTC().AddDate(0, 0, -5).Format("2006-01-02"),
-			wantErr:  false,
-		},
-		{
-			name:     "1 day ago",
-			input:    "1 day ago",
-			wantDate: time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"),
-			wantErr:  false,
-		},
-
-		// Edge cases
-		{
-			name:     "Whitespace trimming",
-			input:    "  July 2025  ",
-			wantDate: "2025-07-01",
-			wantErr:  false,
-		},
-		{
-			name:     "Invalid month name",
-			input:    "Jully 2025",
-			wantDate: "",
-			wantErr:  true,
-		},
-		{
-			name:     "Invalid date format",
-			input:    "2025-13-01",
-			wantDate: "",
-			wantErr:  true,
-		},
-		{
-			name:     "Invalid day for month",
-			input:    "31-February-2025",
-			wantDate: "",
-			wantErr:  true,
-		},
-		{
-			name:     "Empty string",
-			input:    "",
-			wantDate: "",
-			wantErr:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, gotDate, err := parseFlexibleDate(tt.input)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("parseFlexibleDate() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr && gotDate != tt.wantDate {
-				t.Errorf("parseFlexibleDate() gotDate = %v, want %v", gotDate, tt.wantDate)
-			}
-		})
-	}
-}
-
 func TestUnitBuildDateFiltersUnit(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// MembershipResult is one row of the per-user report returned by invite_to_channel and remove_from_channel
+type MembershipResult struct {
+	UserID   string `csv:"user_id"`
+	UserName string `csv:"user_name"`
+	Source   string `csv:"source"`
+	Status   string `csv:"status"`
+	Error    string `csv:"error"`
+}
+
+type membershipEntryKind int
+
+const (
+	membershipEntryInvalid membershipEntryKind = iota
+	membershipEntryUserID
+	membershipEntryHandle
+	membershipEntryGroupID
+)
+
+var slackIDRe = regexp.MustCompile(`^[A-Z0-9]{8,}$`)
+
+// classifyMembershipEntry decides how a single entry of the users list should be resolved.
+// Handles (@name) are ambiguous between users and usergroups and are resolved later against the caches.
+func classifyMembershipEntry(entry string) (membershipEntryKind, string) {
+	entry = strings.TrimSpace(entry)
+	switch {
+	case strings.HasPrefix(entry, "<!subteam^") && strings.HasSuffix(entry, ">"):
+		id := strings.TrimSuffix(strings.TrimPrefix(entry, "<!subteam^"), ">")
+		if i := strings.Index(id, "|"); i >= 0 {
+			id = id[:i]
+		}
+		return membershipEntryGroupID, id
+	case strings.HasPrefix(entry, "<@") && strings.HasSuffix(entry, ">"):
+		id := strings.TrimSuffix(strings.TrimPrefix(entry, "<@"), ">")
+		if i := strings.Index(id, "|"); i >= 0 {
+			id = id[:i]
+		}
+		return membershipEntryUserID, id
+	case strings.HasPrefix(entry, "@") && len(entry) > 1:
+		return membershipEntryHandle, entry[1:]
+	case slackIDRe.MatchString(entry) && isSlackUserIDPrefix(entry):
+		return membershipEntryUserID, entry
+	case slackIDRe.MatchString(entry) && strings.HasPrefix(entry, "S"):
+		return membershipEntryGroupID, entry
+	}
+	return membershipEntryInvalid, entry
+}
+
+// InviteToChannelHandler invites users, or whole usergroups, to a channel and reports the outcome per user
+func (ch *ChannelsHandler) InviteToChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("InviteToChannelHandler called", zap.Any("params", request.Params))
+
+	channelID, err := ch.resolveMembershipChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	targets, results, err := ch.resolveMembershipUsers(ctx, request.GetString("users", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve users", err), nil
+	}
+
+	rl := limiter.Tier3.Limiter()
+	for _, t := range targets {
+		_, err := limiter.CallWithRetry(ctx, rl, 2, slackRetryAfter, func() (*slack.Channel, error) {
+			return ch.apiProvider.Slack().InviteUsersToConversationContext(ctx, channelID, t.UserID)
+		})
+		switch {
+		case err == nil:
+			t.Status = "invited"
+		case strings.Contains(err.Error(), "already_in_channel"):
+			t.Status = "already_member"
+		default:
+			ch.logger.Warn("Failed to invite user", zap.String("channel_id", channelID), zap.String("user_id", t.UserID), zap.Error(err))
+			t.Status = "failed"
+			t.Error = err.Error()
+		}
+		results = append(results, t)
+	}
+
+	return ch.membershipReport(channelID, "Invited", "invited", results)
+}
+
+// RemoveFromChannelHandler removes users, or whole usergroups, from a channel and reports the outcome per user
+func (ch *ChannelsHandler) RemoveFromChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("RemoveFromChannelHandler called", zap.Any("params", request.Params))
+
+	channelID, err := ch.resolveMembershipChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	targets, results, err := ch.resolveMembershipUsers(ctx, request.GetString("users", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve users", err), nil
+	}
+
+	rl := limiter.Tier3.Limiter()
+	for _, t := range targets {
+		_, err := limiter.CallWithRetry(ctx, rl, 2, slackRetryAfter, func() (struct{}, error) {
+			return struct{}{}, ch.apiProvider.Slack().KickUserFromConversationContext(ctx, channelID, t.UserID)
+		})
+		switch {
+		case err == nil:
+			t.Status = "removed"
+		case strings.Contains(err.Error(), "not_in_channel"):
+			t.Status = "not_member"
+		default:
+			ch.logger.Warn("Failed to remove user", zap.String("channel_id", channelID), zap.String("user_id", t.UserID), zap.Error(err))
+			t.Status = "failed"
+			t.Error = err.Error()
+		}
+		results = append(results, t)
+	}
+
+	return ch.membershipReport(channelID, "Removed", "removed", results)
+}
+
+// JoinChannelHandler joins the authenticated user to a channel
+func (ch *ChannelsHandler) JoinChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("JoinChannelHandler called", zap.Any("params", request.Params))
+
+	channelID, err := ch.resolveMembershipChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	channel, warning, _, err := ch.apiProvider.Slack().JoinConversationContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Error("Failed to join channel", zap.String("channel_id", channelID), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to join channel", err), nil
+	}
+
+	status := "joined"
+	if warning == "already_in_channel" {
+		status = "already_member"
+	}
+	name := channelID
+	if channel != nil && channel.Name != "" {
+		name = channel.Name
+	}
+
+	return ch.selfMembershipResult(channelID, name, status)
+}
+
+// LeaveChannelHandler removes the authenticated user from a channel
+func (ch *ChannelsHandler) LeaveChannelHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("LeaveChannelHandler called", zap.Any("params", request.Params))
+
+	channelID, err := ch.resolveMembershipChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	notInChannel, err := ch.apiProvider.Slack().LeaveConversationContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Error("Failed to leave channel", zap.String("channel_id", channelID), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to leave channel", err), nil
+	}
+
+	status := "left"
+	if notInChannel {
+		status = "not_member"
+	}
+	name := channelID
+	if chn, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channelID]; ok {
+		name = chn.Name
+	}
+
+	return ch.selfMembershipResult(channelID, name, status)
+}
+
+// resolveMembershipChannel resolves the channel and applies the SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL policy
+func (ch *ChannelsHandler) resolveMembershipChannel(ctx context.Context, channel string) (string, error) {
+	toolConfig := os.Getenv("SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL")
+	if toolConfig == "" {
+		return "", errors.New(
+			"by default, channel membership tools are disabled. " +
+				"To enable them, set the SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL environment variable to true, 1, or comma separated list of channels " +
+				"to limit where the MCP can invite, remove, join or leave, e.g. 'SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL=C1234567890' or 'SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL=!C1234567890'",
+		)
+	}
+	if channel == "" {
+		return "", errors.New("channel_id must be provided")
+	}
+
+	channelID, err := NewConversationsHandler(ch.apiProvider, ch.logger).resolveChannelID(ctx, channel)
+	if err != nil {
+		return "", err
+	}
+	if !isMembershipAllowed(channelID) {
+		ch.logger.Warn("Channel membership tools not allowed for channel", zap.String("channel", channelID), zap.String("policy", toolConfig))
+		return "", fmt.Errorf("channel membership tools are not allowed for channel %q, applied policy: %s", channelID, toolConfig)
+	}
+	return channelID, nil
+}
+
+// resolveMembershipUsers expands a comma-separated list of users and usergroups into unique user IDs.
+// Entries that cannot be resolved are returned as already-failed results so the caller can report them alongside the rest.
+func (ch *ChannelsHandler) resolveMembershipUsers(ctx context.Context, raw string) ([]MembershipResult, []MembershipResult, error) {
+	entries := parseCommaSeparatedList(raw)
+	if len(entries) == 0 {
+		return nil, nil, errors.New("users must contain at least one @handle, user ID or usergroup")
+	}
+
+	usersMap := ch.apiProvider.ProvideUsersMap()
+	var groups []slack.UserGroup
+	groupsLoaded := false
+
+	seen := make(map[string]bool)
+	var targets, failed []MembershipResult
+	add := func(userID, source string) {
+		if seen[userID] {
+			return
+		}
+		seen[userID] = true
+		userName, _, _ := getUserInfo(userID, usersMap.Users)
+		targets = append(targets, MembershipResult{UserID: userID, UserName: userName, Source: source})
+	}
+	fail := func(entry, reason string) {
+		failed = append(failed, MembershipResult{Source: entry, Status: "not_found", Error: reason})
+	}
+	expandGroup := func(entry, groupID string) {
+		members, err := ch.apiProvider.Slack().GetUserGroupMembersContext(ctx, groupID)
+		if err != nil {
+			ch.logger.Warn("Failed to expand usergroup", zap.String("usergroup", groupID), zap.Error(err))
+			fail(entry, err.Error())
+			return
+		}
+		for _, uid := range members {
+			add(uid, entry)
+		}
+	}
+
+	for _, entry := range entries {
+		kind, value := classifyMembershipEntry(entry)
+		switch kind {
+		case membershipEntryUserID:
+			add(value, entry)
+		case membershipEntryGroupID:
+			expandGroup(entry, value)
+		case membershipEntryHandle:
+			if uid, ok := usersMap.UsersInv[value]; ok {
+				add(uid, entry)
+				continue
+			}
+			if !groupsLoaded {
+				var err error
+				groups, err = ch.apiProvider.Slack().GetUserGroupsContext(ctx)
+				if err != nil {
+					ch.logger.Warn("Failed to list usergroups for handle lookup", zap.Error(err))
+				}
+				groupsLoaded = true
+			}
+			groupID := ""
+			for _, g := range groups {
+				if strings.EqualFold(g.Handle, value) {
+					groupID = g.ID
+					break
+				}
+			}
+			if groupID == "" {
+				fail(entry, "no user or usergroup with this handle")
+				continue
+			}
+			expandGroup(entry, groupID)
+		default:
+			fail(entry, "expected @handle, user ID (U...) or usergroup ID (S...)")
+		}
+	}
+
+	return targets, failed, nil
+}
+
+func (ch *ChannelsHandler) membershipReport(channelID, verb, okStatus string, results []MembershipResult) (*mcp.CallToolResult, error) {
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&results)
+	if err != nil {
+		ch.logger.Error("Failed to marshal membership results to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Channel ID: %s\n", channelID))
+	output.WriteString(fmt.Sprintf("# %s: %d\n", verb, counts[okStatus]))
+	output.WriteString(fmt.Sprintf("# Unchanged: %d\n", counts["already_member"]+counts["not_member"]))
+	output.WriteString(fmt.Sprintf("# Failed: %d\n", counts["failed"]+counts["not_found"]))
+	output.Write(csvBytes)
+
+	return mcp.NewToolResultText(output.String()), nil
+}
+
+func (ch *ChannelsHandler) selfMembershipResult(channelID, name, status string) (*mcp.CallToolResult, error) {
+	type ChannelMembership struct {
+		ID     string `csv:"channel_id"`
+		Name   string `csv:"name"`
+		Status string `csv:"status"`
+	}
+
+	csvBytes, err := gocsv.MarshalBytes([]ChannelMembership{{ID: channelID, Name: name, Status: status}})
+	if err != nil {
+		ch.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// isMembershipAllowed checks the SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL policy; unset means disabled
+func isMembershipAllowed(channel string) bool {
+	config := os.Getenv("SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL")
+	if config == "" {
+		return false
+	}
+	return isChannelAllowedForConfig(channel, config)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"testing"
+)
+
+func TestUnitClassifyMembershipEntry(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantKind  membershipEntryKind
+		wantValue string
+	}{
+		{"U0123456789", membershipEntryUserID, "U0123456789"},
+		{" W0123456789 ", membershipEntryUserID, "W0123456789"},
+		{"<@U0123456789>", membershipEntryUserID, "U0123456789"},
+		{"<@U0123456789|alice>", membershipEntryUserID, "U0123456789"},
+		{"@alice", membershipEntryHandle, "alice"},
+		{"@platform-team", membershipEntryHandle, "platform-team"},
+		{"S0123456789", membershipEntryGroupID, "S0123456789"},
+		{"<!subteam^S0123456789|@oncall>", membershipEntryGroupID, "S0123456789"},
+		{"alice", membershipEntryInvalid, "alice"},
+		{"Ulysses", membershipEntryInvalid, "Ulysses"},
+		{"C0123456789", membershipEntryInvalid, "C0123456789"},
+		{"@", membershipEntryInvalid, "@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			kind, value := classifyMembershipEntry(tt.input)
+			if kind != tt.wantKind || value != tt.wantValue {
+				t.Errorf("classifyMembershipEntry(%q) = (%d, %q), want (%d, %q)", tt.input, kind, value, tt.wantKind, tt.wantValue)
+			}
+		})
+	}
+}
+
+// TestUnitIsMembershipAllowed validates the SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL policy parsing.
+func TestUnitIsMembershipAllowed(t *testing.T) {
+	t.Run("default disabled when unset", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL", "")
+		if isMembershipAllowed("C123") {
+			t.Fatalf("expected disabled by default, got allowed")
+		}
+	})
+
+	t.Run("allowlist specific channels", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL", "C111,C222")
+		if !isMembershipAllowed("C111") {
+			t.Fatalf("expected C111 allowed")
+		}
+		if isMembershipAllowed("C999") {
+			t.Fatalf("expected C999 not allowed")
+		}
+	})
+
+	t.Run("denylist specific channels with !", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL", "!C111")
+		if isMembershipAllowed("C111") {
+			t.Fatalf("expected C111 denied by ! list")
+		}
+		if !isMembershipAllowed("C999") {
+			t.Fatalf("expected C999 allowed")
+		}
+	})
+}
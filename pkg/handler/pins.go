@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// Pin is a pinned item of a channel as returned by list_pins
+type Pin struct {
+	Type      string `csv:"Type"`
+	Channel   string `csv:"Channel"`
+	MsgID     string `csv:"MsgID"`
+	UserID    string `csv:"UserID"`
+	UserName  string `csv:"UserName"`
+	Text      string `csv:"Text"`
+	Time      string `csv:"Time"`
+	FileID    string `csv:"FileID"`
+	FileName  string `csv:"FileName"`
+	Permalink string `csv:"Permalink"`
+}
+
+// Bookmark is a channel bookmark as returned by list_bookmarks and add_bookmark
+type Bookmark struct {
+	ID          string `csv:"ID"`
+	Channel     string `csv:"Channel"`
+	Title       string `csv:"Title"`
+	Link        string `csv:"Link"`
+	Emoji       string `csv:"Emoji"`
+	Type        string `csv:"Type"`
+	DateCreated string `csv:"DateCreated"`
+	DateUpdated string `csv:"DateUpdated"`
+}
+
+type PinsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewPinsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *PinsHandler {
+	return &PinsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// PinMessageHandler pins a message to a channel
+func (ph *PinsHandler) PinMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("PinMessageHandler called", zap.Any("params", request.Params))
+
+	channel, timestamp, err := ph.parsePinParams(ctx, request)
+	if err != nil {
+		ph.logger.Error("Failed to parse pin params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse pin parameters", err), nil
+	}
+
+	status := "pinned"
+	if err := ph.apiProvider.Slack().AddPinContext(ctx, channel, slack.NewRefToMessage(channel, timestamp)); err != nil {
+		if !strings.Contains(err.Error(), "already_pinned") {
+			ph.logger.Error("Slack AddPinContext failed", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to pin message", err), nil
+		}
+		status = "already_pinned"
+	}
+
+	return ph.pinResult(channel, timestamp, status)
+}
+
+// UnpinMessageHandler removes a pinned message from a channel
+func (ph *PinsHandler) UnpinMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("UnpinMessageHandler called", zap.Any("params", request.Params))
+
+	channel, timestamp, err := ph.parsePinParams(ctx, request)
+	if err != nil {
+		ph.logger.Error("Failed to parse unpin params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse pin parameters", err), nil
+	}
+
+	status := "unpinned"
+	if err := ph.apiProvider.Slack().RemovePinContext(ctx, channel, slack.NewRefToMessage(channel, timestamp)); err != nil {
+		if !strings.Contains(err.Error(), "no_pin") {
+			ph.logger.Error("Slack RemovePinContext failed", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to unpin message", err), nil
+		}
+		status = "not_pinned"
+	}
+
+	return ph.pinResult(channel, timestamp, status)
+}
+
+// ListPinsHandler lists the items pinned to a channel
+func (ph *PinsHandler) ListPinsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("ListPinsHandler called", zap.Any("params", request.Params))
+
+	channel, err := ph.resolveChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	items, _, err := ph.apiProvider.Slack().ListPinsContext(ctx, channel)
+	if err != nil {
+		ph.logger.Error("Slack ListPinsContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to list pins", err), nil
+	}
+
+	usersMap := ph.apiProvider.ProvideUsersMap()
+	pins := make([]Pin, 0, len(items))
+	for _, item := range items {
+		pin := Pin{Type: item.Type, Channel: channel}
+		switch {
+		case item.Message != nil:
+			msg := item.Message
+			userName, _, _ := getUserInfo(msg.User, usersMap.Users)
+			pin.MsgID = msg.Timestamp
+			pin.UserID = msg.User
+			pin.UserName = userName
+			pin.Text = text.ProcessText(msg.Text + text.AttachmentsTo2CSV(msg.Text, msg.Attachments) + text.BlocksToText(msg.Blocks))
+			pin.Permalink = msg.Permalink
+			if ts, err := text.TimestampToIsoRFC3339(msg.Timestamp); err == nil {
+				pin.Time = ts
+			}
+		case item.File != nil:
+			pin.FileID = item.File.ID
+			pin.FileName = item.File.Name
+			pin.UserID = item.File.User
+			pin.UserName, _, _ = getUserInfo(item.File.User, usersMap.Users)
+			pin.Permalink = item.File.Permalink
+		}
+		pins = append(pins, pin)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&pins)
+	if err != nil {
+		ph.logger.Error("Failed to marshal pins to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format pins", err), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Channel: %s\n", channel))
+	sb.WriteString(fmt.Sprintf("# Total pins: %d\n", len(pins)))
+	sb.Write(csvBytes)
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// AddBookmarkHandler adds a link bookmark to a channel
+func (ph *PinsHandler) AddBookmarkHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("AddBookmarkHandler called", zap.Any("params", request.Params))
+
+	channel, err := ph.resolveWritableChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	title := request.GetString("title", "")
+	if title == "" {
+		return mcp.NewToolResultError("title must be provided"), nil
+	}
+	link := request.GetString("link", "")
+	if link == "" {
+		return mcp.NewToolResultError("link must be provided"), nil
+	}
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return mcp.NewToolResultError("link must be an http:// or https:// URL"), nil
+	}
+
+	bookmark, err := ph.apiProvider.Slack().AddBookmarkContext(ctx, channel, slack.AddBookmarkParameters{
+		Title: title,
+		Type:  "link",
+		Link:  link,
+		Emoji: request.GetString("emoji", ""),
+	})
+	if err != nil {
+		ph.logger.Error("Slack AddBookmarkContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to add bookmark", err), nil
+	}
+
+	csvBytes, err := gocsv.MarshalBytes([]Bookmark{toBookmark(bookmark, channel)})
+	if err != nil {
+		ph.logger.Error("Failed to marshal bookmark to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format bookmark", err), nil
+	}
+
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// ListBookmarksHandler lists the bookmarks of a channel
+func (ph *PinsHandler) ListBookmarksHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("ListBookmarksHandler called", zap.Any("params", request.Params))
+
+	channel, err := ph.resolveChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	bookmarks, err := ph.apiProvider.Slack().ListBookmarksContext(ctx, channel)
+	if err != nil {
+		ph.logger.Error("Slack ListBookmarksContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to list bookmarks", err), nil
+	}
+
+	result := make([]Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		result = append(result, toBookmark(b, channel))
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&result)
+	if err != nil {
+		ph.logger.Error("Failed to marshal bookmarks to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format bookmarks", err), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Channel: %s\n", channel))
+	sb.WriteString(fmt.Sprintf("# Total bookmarks: %d\n", len(result)))
+	sb.Write(csvBytes)
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// RemoveBookmarkHandler removes a bookmark from a channel
+func (ph *PinsHandler) RemoveBookmarkHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ph.logger.Debug("RemoveBookmarkHandler called", zap.Any("params", request.Params))
+
+	channel, err := ph.resolveWritableChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resolve channel", err), nil
+	}
+
+	bookmarkID := request.GetString("bookmark_id", "")
+	if bookmarkID == "" {
+		return mcp.NewToolResultError("bookmark_id must be provided"), nil
+	}
+
+	if err := ph.apiProvider.Slack().RemoveBookmarkContext(ctx, channel, bookmarkID); err != nil {
+		ph.logger.Error("Slack RemoveBookmarkContext failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to remove bookmark", err), nil
+	}
+
+	type RemoveBookmarkResult struct {
+		ID      string `csv:"ID"`
+		Channel string `csv:"Channel"`
+		Status  string `csv:"Status"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]RemoveBookmarkResult{{ID: bookmarkID, Channel: channel, Status: "removed"}})
+	if err != nil {
+		ph.logger.Error("Failed to marshal remove result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format remove result", err), nil
+	}
+
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+func (ph *PinsHandler) parsePinParams(ctx context.Context, request mcp.CallToolRequest) (string, string, error) {
+	channel, err := ph.resolveWritableChannel(ctx, request.GetString("channel_id", ""))
+	if err != nil {
+		return "", "", err
+	}
+
+	timestamp := request.GetString("timestamp", "")
+	if timestamp == "" {
+		return "", "", errors.New("timestamp must be provided")
+	}
+	if !strings.Contains(timestamp, ".") {
+		return "", "", fmt.Errorf("invalid timestamp format: %s (must be like 1234567890.123456)", timestamp)
+	}
+
+	return channel, timestamp, nil
+}
+
+func (ph *PinsHandler) pinResult(channel, timestamp, status string) (*mcp.CallToolResult, error) {
+	type PinResult struct {
+		Channel   string `csv:"Channel"`
+		Timestamp string `csv:"Timestamp"`
+		Status    string `csv:"Status"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]PinResult{{Channel: channel, Timestamp: timestamp, Status: status}})
+	if err != nil {
+		ph.logger.Error("Failed to marshal pin result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format pin result", err), nil
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// resolveChannel resolves #channel and @user names the same way the conversations tools do,
+// including a one-off cache refresh when the name is unknown.
+func (ph *PinsHandler) resolveChannel(ctx context.Context, channel string) (string, error) {
+	if channel == "" {
+		return "", errors.New("channel_id must be provided")
+	}
+	return NewConversationsHandler(ph.apiProvider, ph.logger).resolveChannelID(ctx, channel)
+}
+
+// resolveWritableChannel resolves the channel and applies the SLACK_MCP_PIN_TOOL policy
+func (ph *PinsHandler) resolveWritableChannel(ctx context.Context, channel string) (string, error) {
+	toolConfig := os.Getenv("SLACK_MCP_PIN_TOOL")
+	if toolConfig == "" {
+		return "", errors.New(
+			"by default, pin and bookmark changes are disabled. " +
+				"To enable them, set the SLACK_MCP_PIN_TOOL environment variable to true, 1, or comma separated list of channels " +
+				"to limit where the MCP can pin messages and edit bookmarks, e.g. 'SLACK_MCP_PIN_TOOL=C1234567890' or 'SLACK_MCP_PIN_TOOL=!C1234567890'",
+		)
+	}
+
+	resolved, err := ph.resolveChannel(ctx, channel)
+	if err != nil {
+		return "", err
+	}
+	if !isPinAllowed(resolved) {
+		ph.logger.Warn("Pin tools not allowed for channel", zap.String("channel", resolved), zap.String("policy", toolConfig))
+		return "", fmt.Errorf("pin and bookmark tools are not allowed for channel %q, applied policy: %s", resolved, toolConfig)
+	}
+	return resolved, nil
+}
+
+// isPinAllowed checks the SLACK_MCP_PIN_TOOL policy; unset means disabled
+func isPinAllowed(channel string) bool {
+	config := os.Getenv("SLACK_MCP_PIN_TOOL")
+	if config == "" {
+		return false
+	}
+	return isChannelAllowedForConfig(channel, config)
+}
+
+func toBookmark(b slack.Bookmark, channel string) Bookmark {
+	if b.ChannelID != "" {
+		channel = b.ChannelID
+	}
+	return Bookmark{
+		ID:          b.ID,
+		Channel:     channel,
+		Title:       b.Title,
+		Link:        b.Link,
+		Emoji:       b.Emoji,
+		Type:        b.Type,
+		DateCreated: formatJSONTime(b.Created),
+		DateUpdated: formatJSONTime(b.Updated),
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+)
+
+// TestUnitIsPinAllowed validates the SLACK_MCP_PIN_TOOL policy parsing.
+func TestUnitIsPinAllowed(t *testing.T) {
+	t.Run("default disabled when unset", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_PIN_TOOL", "")
+		if isPinAllowed("C123") {
+			t.Fatalf("expected disabled by default, got allowed")
+		}
+	})
+
+	t.Run("enabled for all with true", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_PIN_TOOL", "true")
+		if !isPinAllowed("C123") {
+			t.Fatalf("expected allowed for all when true")
+		}
+	})
+
+	t.Run("allowlist specific channels", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_PIN_TOOL", "C111, C222")
+		if !isPinAllowed("C222") {
+			t.Fatalf("expected C222 allowed")
+		}
+		if isPinAllowed("C999") {
+			t.Fatalf("expected C999 not allowed")
+		}
+	})
+
+	t.Run("denylist specific channels with !", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_PIN_TOOL", "!C111")
+		if isPinAllowed("C111") {
+			t.Fatalf("expected C111 denied by ! list")
+		}
+		if !isPinAllowed("C999") {
+			t.Fatalf("expected channels not in ! list to be allowed")
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SlackTemplate is one Block Kit template section of SLACK_TEMPLATES.md
+type SlackTemplate struct {
+	Name         string
+	UseFor       string
+	TextFallback string
+	Blocks       string
+	Notes        string
+}
+
+// PromptName is the name the template is registered under as an MCP prompt, e.g. template_status_update
+func (t SlackTemplate) PromptName() string {
+	return "template_" + strings.Trim(nonWordRe.ReplaceAllString(strings.ToLower(t.Name), "_"), "_")
+}
+
+var (
+	nonWordRe    = regexp.MustCompile(`[^a-z0-9]+`)
+	jsonBlockRe  = regexp.MustCompile("(?s)```json\\n(.*?)\\n```")
+	useForRe     = regexp.MustCompile(`(?m)^\*\*Use for:\*\*\s*(.+)$`)
+	fallbackRe   = regexp.MustCompile(`(?m)^\*\*Text fallback:\*\*\s*(.+)$`)
+	separatorsRe = regexp.MustCompile(`(?m)^---\s*$`)
+)
+
+// ParseSlackTemplates splits SLACK_TEMPLATES.md into its templates. Sections without a JSON example, such
+// as the quick reference and best practices, are not templates and are skipped.
+func ParseSlackTemplates(markdown string) []SlackTemplate {
+	var templates []SlackTemplate
+	for _, section := range strings.Split(markdown, "\n## ")[1:] {
+		name, body, _ := strings.Cut(section, "\n")
+		blocks := jsonBlockRe.FindStringSubmatchIndex(body)
+		if blocks == nil {
+			continue
+		}
+
+		t := SlackTemplate{
+			Name:   strings.TrimSpace(name),
+			Blocks: body[blocks[2]:blocks[3]],
+		}
+		if m := useForRe.FindStringSubmatch(body); m != nil {
+			t.UseFor = strings.TrimSpace(m[1])
+		}
+		if m := fallbackRe.FindStringSubmatch(body); m != nil {
+			t.TextFallback = strings.TrimSpace(m[1])
+		}
+		t.Notes = strings.TrimSpace(separatorsRe.ReplaceAllString(body[blocks[1]:], ""))
+		templates = append(templates, t)
+	}
+	return templates
+}
+
+type promptArgument struct {
+	name        string
+	description string
+	required    bool
+}
+
+// templateArguments are the details each template is filled with. Templates added to SLACK_TEMPLATES.md
+// without an entry here take a single free-form content argument.
+var templateArguments = map[string][]promptArgument{
+	"Status Update": {
+		{"project", "Project or workstream the update is about", true},
+		{"status", "Overall status, e.g. on track, at risk, blocked", false},
+		{"completed", "What was done since the last update", false},
+		{"next", "What comes next", false},
+	},
+	"Alert": {
+		{"severity", "Severity, e.g. critical, warning, info", true},
+		{"summary", "One-line description of what is happening", true},
+		{"impact", "Who or what is affected", false},
+		{"actions", "What is being done, or what readers should do", false},
+	},
+	"Meeting Summary": {
+		{"meeting", "Name of the meeting", true},
+		{"notes", "Notes or transcript to summarize", true},
+		{"attendees", "Who attended", false},
+	},
+	"Announcement": {
+		{"headline", "What is being announced", true},
+		{"details", "Background and details", false},
+		{"link", "Link to more information", false},
+	},
+	"Request": {
+		{"request", "What you need", true},
+		{"from", "Who should act, as @user or @group", false},
+		{"deadline", "When it is needed by", false},
+	},
+	"Report": {
+		{"title", "Name of the report", true},
+		{"data", "Metrics or findings to report", true},
+		{"period", "Time period covered", false},
+	},
+	"Error": {
+		{"error", "What failed", true},
+		{"action", "What readers should do next", false},
+	},
+	"Empty State": {
+		{"context", "What has no data", true},
+		{"next_step", "How to get data there", false},
+	},
+	"Compact Fields": {
+		{"title", "Heading of the message", false},
+		{"fields", "Key: value pairs to show, one per line", true},
+	},
+}
+
+var templateChannelArgument = promptArgument{"channel", "Channel to post to, as #name or ID. When empty the message is only drafted.", false}
+
+func (t SlackTemplate) arguments() []promptArgument {
+	args, ok := templateArguments[t.Name]
+	if !ok {
+		args = []promptArgument{{"content", "What the message should say", true}}
+	}
+	return append(append([]promptArgument(nil), args...), templateChannelArgument)
+}
+
+// TemplatePrompt describes the MCP prompt for a template
+func TemplatePrompt(t SlackTemplate) mcp.Prompt {
+	description := fmt.Sprintf("Write a %s message with Block Kit.", t.Name)
+	if t.UseFor != "" {
+		description += " Use for: " + t.UseFor
+	}
+	return newPrompt(t.PromptName(), description, t.arguments())
+}
+
+// TemplatePromptHandler renders the prompt for a template with the caller's details
+func TemplatePromptHandler(t SlackTemplate) func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := t.arguments()
+		values, err := promptValues(request, args)
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Write a Slack message using the %q Block Kit template.\n", t.Name)
+		if t.UseFor != "" {
+			fmt.Fprintf(&b, "This template is meant for: %s\n", t.UseFor)
+		}
+		b.WriteString("\nDetails:\n")
+		for _, arg := range args {
+			if arg.name != templateChannelArgument.name && values[arg.name] != "" {
+				fmt.Fprintf(&b, "- %s: %s\n", arg.name, values[arg.name])
+			}
+		}
+		fmt.Fprintf(&b, "\nStart from these blocks and replace the sample content with the details above. Leave out sections the details do not cover.\n```json\n%s\n```\n", t.Blocks)
+		if t.Notes != "" {
+			fmt.Fprintf(&b, "\n%s\n", t.Notes)
+		}
+		if t.TextFallback != "" {
+			fmt.Fprintf(&b, "\nAlso write the plain-text fallback used in notifications. %s\n", t.TextFallback)
+		}
+		if channel := values[templateChannelArgument.name]; channel != "" {
+			fmt.Fprintf(&b, "\nPost it to %s with post_message, passing the blocks as `blocks` and the fallback as `text`.\n", channel)
+		} else {
+			b.WriteString("\nShow me the blocks JSON and the fallback text. Do not post anything.\n")
+		}
+
+		return promptResult(TemplatePrompt(t).Description, b.String()), nil
+	}
+}
+
+// WorkflowPrompt is a prompt that walks the model through a task with the server's tools
+type WorkflowPrompt struct {
+	Prompt mcp.Prompt
+	// Tools the prompt relies on. The prompt is only offered when all of them are enabled.
+	Tools   []string
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+// WorkflowPrompts returns the task prompts built on the server's tools
+func WorkflowPrompts() []WorkflowPrompt {
+	summarizeUnread := []promptArgument{
+		{"channel_types", "Which conversations to include: all, dm, group_dm, partner or internal. Default is all.", false},
+		{"mentions_only", "Set to true to only include conversations where I was @mentioned", false},
+	}
+	incidentUpdate := []promptArgument{
+		{"channel", "Incident channel, as #name or ID", true},
+		{"incident", "Short description of the incident", true},
+		{"status", "Current status, e.g. investigating, identified, monitoring, resolved", false},
+		{"since", "How far back to read, as a get_channel_messages limit such as 4h, 1d or 1w. Default is 1d.", false},
+	}
+	catchUp := []promptArgument{
+		{"channel", "Channel to catch up on, as #name or ID", true},
+		{"since", "How far back to read, as a get_channel_messages limit such as 1d (since yesterday), 1w or 30d. Default is 1d.", false},
+	}
+
+	return []WorkflowPrompt{
+		{
+			Prompt: newPrompt("summarize_unread", "Summarize my unread messages and list what needs a reply", summarizeUnread),
+			Tools:  []string{"conversations_unreads", "get_thread_messages"},
+			Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				values, err := promptValues(request, summarizeUnread)
+				if err != nil {
+					return nil, err
+				}
+				channelTypes := valueOr(values["channel_types"], "all")
+				mentionsOnly := strings.EqualFold(values["mentions_only"], "true")
+
+				var b strings.Builder
+				b.WriteString("Summarize my unread Slack messages.\n\n")
+				fmt.Fprintf(&b, "1. Call conversations_unreads with channel_types=%s", channelTypes)
+				if mentionsOnly {
+					b.WriteString(" and mentions_only=true")
+				}
+				b.WriteString(".\n")
+				b.WriteString("2. Group the results by conversation, DMs first. Summarize each in one or two lines and call out direct questions to me, @mentions and anything urgent.\n")
+				b.WriteString("3. Where a thread is needed to understand a message, read it with get_thread_messages.\n")
+				b.WriteString("4. End with a short list of what needs a reply from me.\n\n")
+				b.WriteString("Do not mark anything as read.")
+				return promptResult("Summarize unread messages", b.String()), nil
+			},
+		},
+		{
+			Prompt: newPrompt("draft_incident_update", "Draft a status update for an ongoing incident from the discussion in its channel", incidentUpdate),
+			Tools:  []string{"get_channel_messages", "get_thread_messages"},
+			Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				values, err := promptValues(request, incidentUpdate)
+				if err != nil {
+					return nil, err
+				}
+				channel := values["channel"]
+
+				var b strings.Builder
+				fmt.Fprintf(&b, "Draft an incident update for %q in %s.\n\n", values["incident"], channel)
+				fmt.Fprintf(&b, "1. Read the recent discussion with get_channel_messages (channel_id=%s, limit=%s) and follow the relevant threads with get_thread_messages.\n", channel, valueOr(values["since"], "1d"))
+				b.WriteString("2. If search_messages is available, search for the incident to find related discussion in other channels.\n")
+				b.WriteString("3. Write the update with the Alert template (the template_alert prompt or get_slack_templates). Cover the current status")
+				if status := values["status"]; status != "" {
+					fmt.Fprintf(&b, " (%s)", status)
+				}
+				b.WriteString(", the impact, what changed since the last update, the next steps and when the next update will be.\n")
+				b.WriteString("4. Show me the draft. Only post it with post_message after I approve it.")
+				return promptResult("Draft an incident update", b.String()), nil
+			},
+		},
+		{
+			Prompt: newPrompt("catch_up_on_channel", "Catch me up on a channel: decisions, open questions and action items since a point in time", catchUp),
+			Tools:  []string{"get_channel_messages", "get_thread_messages"},
+			Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				values, err := promptValues(request, catchUp)
+				if err != nil {
+					return nil, err
+				}
+				channel := values["channel"]
+
+				var b strings.Builder
+				fmt.Fprintf(&b, "Catch me up on %s.\n\n", channel)
+				fmt.Fprintf(&b, "1. Call get_channel_messages with channel_id=%s and limit=%s. If the last row has a cursor, keep paging.\n", channel, valueOr(values["since"], "1d"))
+				b.WriteString("2. Read the replies of messages that started threads with get_thread_messages.\n")
+				b.WriteString("3. Summarize the key decisions, open questions, action items with their owners, and anything addressed to me (get_current_user tells you who I am).\n\n")
+				b.WriteString("Keep it short. Link to the important messages with get_permalink where it helps.")
+				return promptResult("Catch up on "+channel, b.String()), nil
+			},
+		},
+	}
+}
+
+func newPrompt(name, description string, args []promptArgument) mcp.Prompt {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(description)}
+	for _, arg := range args {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.description)}
+		if arg.required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(arg.name, argOpts...))
+	}
+	return mcp.NewPrompt(name, opts...)
+}
+
+// promptValues returns the trimmed argument values of a prompt request, checking the required ones
+func promptValues(request mcp.GetPromptRequest, args []promptArgument) (map[string]string, error) {
+	values := make(map[string]string, len(args))
+	for _, arg := range args {
+		value := strings.TrimSpace(request.Params.Arguments[arg.name])
+		if value == "" && arg.required {
+			return nil, fmt.Errorf("argument %q is required", arg.name)
+		}
+		values[arg.name] = value
+	}
+	return values, nil
+}
+
+func promptResult(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
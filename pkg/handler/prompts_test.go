@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getPrompt(t *testing.T, handler func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error), args map[string]string) string {
+	t.Helper()
+	var request mcp.GetPromptRequest
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	return result.Messages[0].Content.(mcp.TextContent).Text
+}
+
+func TestUnitParseSlackTemplates(t *testing.T) {
+	content, _, err := ReadSlackTemplates()
+	require.NoError(t, err)
+
+	templates := ParseSlackTemplates(string(content))
+	var names []string
+	for _, tpl := range templates {
+		names = append(names, tpl.Name)
+		var blocks []any
+		assert.NoError(t, json.Unmarshal([]byte(tpl.Blocks), &blocks), tpl.Name)
+		assert.NotEmpty(t, tpl.UseFor, tpl.Name)
+		assert.Contains(t, templateArguments, tpl.Name, "template without typed arguments")
+	}
+	assert.Equal(t, []string{"Status Update", "Alert", "Meeting Summary", "Announcement", "Request", "Report", "Error", "Empty State", "Compact Fields"}, names)
+	assert.Equal(t, "template_status_update", templates[0].PromptName())
+	assert.Equal(t, `Include severity and summary - "[SEVERITY] Alert: [description]"`, templates[1].TextFallback)
+}
+
+func TestUnitTemplatePrompt(t *testing.T) {
+	tpl := ParseSlackTemplates("# Templates\n\n## Release Notes\n\n**Use for:** Shipping news\n\n```json\n[{\"type\":\"divider\"}]\n```\n\nKeep it short.\n\n---\n")[0]
+
+	prompt := TemplatePrompt(tpl)
+	assert.Equal(t, "template_release_notes", prompt.Name)
+	require.Len(t, prompt.Arguments, 2)
+	assert.Equal(t, "content", prompt.Arguments[0].Name)
+	assert.True(t, prompt.Arguments[0].Required)
+	assert.Equal(t, "channel", prompt.Arguments[1].Name)
+
+	_, err := TemplatePromptHandler(tpl)(context.Background(), mcp.GetPromptRequest{})
+	assert.ErrorContains(t, err, `"content" is required`)
+
+	text := getPrompt(t, TemplatePromptHandler(tpl), map[string]string{"content": "v2 is out"})
+	assert.Contains(t, text, "- content: v2 is out")
+	assert.Contains(t, text, "[{\"type\":\"divider\"}]")
+	assert.Contains(t, text, "Keep it short.")
+	assert.Contains(t, text, "Do not post anything.")
+
+	text = getPrompt(t, TemplatePromptHandler(tpl), map[string]string{"content": "v2 is out", "channel": "#releases"})
+	assert.Contains(t, text, "Post it to #releases with post_message")
+}
+
+func TestUnitWorkflowPrompts(t *testing.T) {
+	prompts := make(map[string]WorkflowPrompt)
+	for _, p := range WorkflowPrompts() {
+		prompts[p.Prompt.Name] = p
+	}
+	require.Contains(t, prompts, "summarize_unread")
+	require.Contains(t, prompts, "draft_incident_update")
+	require.Contains(t, prompts, "catch_up_on_channel")
+
+	text := getPrompt(t, prompts["catch_up_on_channel"].Handler, map[string]string{"channel": "#general"})
+	assert.Contains(t, text, "get_channel_messages with channel_id=#general and limit=1d")
+
+	text = getPrompt(t, prompts["summarize_unread"].Handler, map[string]string{"mentions_only": "true"})
+	assert.Contains(t, text, "conversations_unreads with channel_types=all and mentions_only=true")
+
+	_, err := prompts["draft_incident_update"].Handler(context.Background(), mcp.GetPromptRequest{})
+	assert.Error(t, err)
+}
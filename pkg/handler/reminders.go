@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// Reminder is a reminder as returned by add_reminder and list_reminders
+type Reminder struct {
+	ID          string `csv:"id"`
+	UserID      string `csv:"user_id"`
+	UserName    string `csv:"user_name"`
+	CreatorID   string `csv:"creator_id"`
+	Text        string `csv:"text"`
+	Time        string `csv:"time"`
+	Recurring   bool   `csv:"recurring"`
+	Status      string `csv:"status"`
+	CompletedAt string `csv:"completed_at"`
+}
+
+type RemindersHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewRemindersHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *RemindersHandler {
+	return &RemindersHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// AddReminderHandler creates a reminder for the authenticated user or for another user
+func (rh *RemindersHandler) AddReminderHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("AddReminderHandler called", zap.Any("params", request.Params))
+
+	text, err := reminderText(request.GetString("text", ""), request.GetString("permalink", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	rawTime := strings.TrimSpace(request.GetString("time", ""))
+	if rawTime == "" {
+		return mcp.NewToolResultError("time must be provided"), nil
+	}
+	slackTime, err := reminderTime(rawTime, time.Now())
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid time", err), nil
+	}
+
+	var userID string
+	if target := strings.TrimSpace(request.GetString("user", "")); target != "" && !strings.EqualFold(target, "me") {
+		usersMap := rh.apiProvider.ProvideUsersMap()
+		userID, err = resolveUserID(target, usersMap.Users, usersMap.UsersInv)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to resolve user", err), nil
+		}
+	}
+
+	reminder, err := rh.apiProvider.Slack().AddUserReminderContext(ctx, userID, text, slackTime)
+	if err != nil {
+		rh.logger.Error("Failed to add reminder",
+			zap.String("user", userID),
+			zap.String("time", slackTime),
+			zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to add reminder", err), nil
+	}
+
+	rh.logger.Info("Reminder added", zap.String("reminder_id", reminder.ID), zap.String("user", reminder.User))
+
+	return rh.remindersResult("# Reminder created\n", []Reminder{rh.toReminder(reminder)})
+}
+
+// ListRemindersHandler lists reminders created by or for the authenticated user
+func (rh *RemindersHandler) ListRemindersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("ListRemindersHandler called", zap.Any("params", request.Params))
+
+	includeCompleted := request.GetBool("include_completed", false)
+
+	reminders, err := rh.apiProvider.Slack().ListRemindersContext(ctx)
+	if err != nil {
+		rh.logger.Error("Failed to list reminders", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to list reminders", err), nil
+	}
+
+	rows := make([]Reminder, 0, len(reminders))
+	for _, r := range reminders {
+		if r.CompleteTS != 0 && !includeCompleted {
+			continue
+		}
+		rows = append(rows, rh.toReminder(r))
+	}
+	// Soonest first, Slack returns them in creation order
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Time < rows[j].Time })
+
+	return rh.remindersResult(fmt.Sprintf("# Total reminders returned: %d\n", len(rows)), rows)
+}
+
+// CompleteReminderHandler marks a reminder as complete
+func (rh *RemindersHandler) CompleteReminderHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("CompleteReminderHandler called", zap.Any("params", request.Params))
+
+	id := strings.TrimSpace(request.GetString("reminder_id", ""))
+	if id == "" {
+		return mcp.NewToolResultError("reminder_id must be provided"), nil
+	}
+
+	if err := rh.apiProvider.Slack().CompleteReminderContext(ctx, id); err != nil {
+		rh.logger.Error("Failed to complete reminder", zap.String("reminder_id", id), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to complete reminder", err), nil
+	}
+
+	return rh.reminderStatusResult(id, "completed")
+}
+
+// DeleteReminderHandler deletes a reminder
+func (rh *RemindersHandler) DeleteReminderHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("DeleteReminderHandler called", zap.Any("params", request.Params))
+
+	id := strings.TrimSpace(request.GetString("reminder_id", ""))
+	if id == "" {
+		return mcp.NewToolResultError("reminder_id must be provided"), nil
+	}
+
+	if err := rh.apiProvider.Slack().DeleteReminderContext(ctx, id); err != nil {
+		rh.logger.Error("Failed to delete reminder", zap.String("reminder_id", id), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to delete reminder", err), nil
+	}
+
+	return rh.reminderStatusResult(id, "deleted")
+}
+
+func (rh *RemindersHandler) toReminder(r *slack.Reminder) Reminder {
+	userName, _, _ := getUserInfo(r.User, rh.apiProvider.ProvideUsersMap().Users)
+	status := "pending"
+	if r.CompleteTS != 0 {
+		status = "complete"
+	}
+	return Reminder{
+		ID:          r.ID,
+		UserID:      r.User,
+		UserName:    userName,
+		CreatorID:   r.Creator,
+		Text:        r.Text,
+		Time:        formatJSONTime(slack.JSONTime(r.Time)),
+		Recurring:   r.Recurring,
+		Status:      status,
+		CompletedAt: formatJSONTime(slack.JSONTime(r.CompleteTS)),
+	}
+}
+
+func (rh *RemindersHandler) remindersResult(header string, rows []Reminder) (*mcp.CallToolResult, error) {
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		rh.logger.Error("Failed to marshal reminders to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format reminders as CSV", err), nil
+	}
+	return mcp.NewToolResultText(header + string(csvBytes)), nil
+}
+
+func (rh *RemindersHandler) reminderStatusResult(id, status string) (*mcp.CallToolResult, error) {
+	type ReminderStatus struct {
+		ID     string `csv:"id"`
+		Status string `csv:"status"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]ReminderStatus{{ID: id, Status: status}})
+	if err != nil {
+		rh.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// reminderText builds the reminder text, appending the permalink of the message it is about
+func reminderText(text, permalink string) (string, error) {
+	text = strings.TrimSpace(text)
+	permalink = strings.TrimSpace(permalink)
+	if permalink != "" {
+		u, err := url.Parse(permalink)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("permalink must be an http(s) URL of a Slack message, got %q", permalink)
+		}
+		if text == "" {
+			return permalink, nil
+		}
+		return text + " " + permalink, nil
+	}
+	if text == "" {
+		return "", errors.New("text or permalink must be provided")
+	}
+	return text, nil
+}
+
+// reminderTime converts the time argument to what reminders.add expects. One-off times are parsed
+// locally to a Unix timestamp; recurring schedules ("every weekday at 9am") are passed through for
+// Slack to interpret since they cannot be expressed as a single timestamp.
+func reminderTime(raw string, now time.Time) (string, error) {
+	if strings.HasPrefix(strings.ToLower(raw), "every ") {
+		return raw, nil
+	}
+	t, err := parseFutureTime(raw, now)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(t.Unix(), 10), nil
+}
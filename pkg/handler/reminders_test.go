@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitReminderText(t *testing.T) {
+	link := "https://team.slack.com/archives/C0123456789/p1700000000000100"
+
+	got, err := reminderText("check the rollout", "")
+	require.NoError(t, err)
+	assert.Equal(t, "check the rollout", got)
+
+	got, err = reminderText("follow up on this thread", link)
+	require.NoError(t, err)
+	assert.Equal(t, "follow up on this thread "+link, got)
+
+	got, err = reminderText("  ", link)
+	require.NoError(t, err)
+	assert.Equal(t, link, got, "permalink alone is a valid reminder")
+
+	_, err = reminderText("", "")
+	assert.Error(t, err)
+
+	_, err = reminderText("x", "slack.com/archives/C1/p1")
+	assert.Error(t, err, "permalink without scheme is rejected")
+}
+
+func TestUnitReminderTime(t *testing.T) {
+	now := time.Date(2025, 7, 16, 14, 30, 0, 0, time.UTC)
+
+	got, err := reminderTime("tomorrow 9am", now)
+	require.NoError(t, err)
+	assert.Equal(t, "1752742800", got)
+
+	got, err = reminderTime("in 2 hours", now)
+	require.NoError(t, err)
+	assert.Equal(t, "1752683400", got)
+
+	got, err = reminderTime("Every weekday at 9am", now)
+	require.NoError(t, err)
+	assert.Equal(t, "Every weekday at 9am", got, "recurring schedules are left to Slack")
+
+	_, err = reminderTime("2025-07-01 10:00", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxCompletionValues is the most values a completion/complete response may carry
+const maxCompletionValues = 100
+
+// The resource templates below serve what the matching read tools return, so a client can attach a
+// channel, thread, user or file as context without a tool call.
+
+// ChannelHistoryResource serves slack://channel/{id}/history: the latest messages of a channel
+func (ch *ConversationsHandler) ChannelHistoryResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ch.logger.Debug("ChannelHistoryResource called", zap.Any("params", request.Params))
+
+	return toolResultResource(ctx, ch.apiProvider, ch.logger, request, func(ctx context.Context) (*mcp.CallToolResult, error) {
+		return ch.ConversationsHistoryHandler(ctx, toolRequest(map[string]any{
+			"channel_id": templateArgument(request, "id"),
+			"limit":      "100",
+			"fields":     "msgID,userUser,realName,threadTs,text,time",
+		}))
+	})
+}
+
+// ThreadResource serves slack://channel/{id}/thread/{ts}: a thread with its replies
+func (ch *ConversationsHandler) ThreadResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ch.logger.Debug("ThreadResource called", zap.Any("params", request.Params))
+
+	return toolResultResource(ctx, ch.apiProvider, ch.logger, request, func(ctx context.Context) (*mcp.CallToolResult, error) {
+		return ch.ConversationsRepliesHandler(ctx, toolRequest(map[string]any{
+			"channel_id": templateArgument(request, "id"),
+			"thread_ts":  templateArgument(request, "ts"),
+			"limit":      "1000",
+		}))
+	})
+}
+
+// UserResource serves slack://user/{id}: the profile of a user, by ID or @handle
+func (uh *UsersHandler) UserResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uh.logger.Debug("UserResource called", zap.Any("params", request.Params))
+
+	return toolResultResource(ctx, uh.apiProvider, uh.logger, request, func(ctx context.Context) (*mcp.CallToolResult, error) {
+		return uh.GetUserInfoHandler(ctx, toolRequest(map[string]any{
+			"user_id": templateArgument(request, "id"),
+			"fields":  "extended",
+		}))
+	})
+}
+
+// FileResource serves slack://file/{id}: the metadata of a file
+func (fh *FileHandler) FileResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	fh.logger.Debug("FileResource called", zap.Any("params", request.Params))
+
+	return toolResultResource(ctx, fh.apiProvider, fh.logger, request, func(ctx context.Context) (*mcp.CallToolResult, error) {
+		return fh.GetFileInfoHandler(ctx, toolRequest(map[string]any{
+			"file_id": templateArgument(request, "id"),
+		}))
+	})
+}
+
+// UsergroupResource serves slack://usergroup/{handle}: a user group and its members, by handle or ID
+func (h *UsergroupsHandler) UsergroupResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	h.logger.Debug("UsergroupResource called", zap.Any("params", request.Params))
+
+	return toolResultResource(ctx, h.apiProvider, h.logger, request, func(ctx context.Context) (*mcp.CallToolResult, error) {
+		handle := strings.TrimPrefix(templateArgument(request, "handle"), "@")
+		if handle == "" {
+			return mcp.NewToolResultError("handle must be provided"), nil
+		}
+
+		groups, err := h.apiProvider.Slack().GetUserGroupsContext(ctx,
+			slack.GetUserGroupsOptionIncludeUsers(true),
+			slack.GetUserGroupsOptionIncludeDisabled(true),
+		)
+		if err != nil {
+			h.logger.Error("GetUserGroupsContext failed", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to get user groups", err), nil
+		}
+
+		var group *slack.UserGroup
+		for i := range groups {
+			if strings.EqualFold(groups[i].Handle, handle) || groups[i].ID == handle {
+				group = &groups[i]
+				break
+			}
+		}
+		if group == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("user group @%s not found", handle)), nil
+		}
+
+		users := h.apiProvider.ProvideUsersMap().Users
+		members := make([]User, 0, len(group.Users))
+		for _, id := range group.Users {
+			userName, realName, _ := getUserInfo(id, users)
+			members = append(members, User{UserID: id, UserName: userName, RealName: realName})
+		}
+		csvBytes, err := gocsv.MarshalBytes(&members)
+		if err != nil {
+			h.logger.Error("Failed to marshal user group members to CSV", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to format user group members", err), nil
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "# User group: @%s %q (%s)\n", group.Handle, group.Name, group.ID)
+		if group.Description != "" {
+			fmt.Fprintf(&b, "# Description: %s\n", group.Description)
+		}
+		if group.DateDelete != 0 {
+			b.WriteString("# Disabled: true\n")
+		}
+		fmt.Fprintf(&b, "# Members: %d\n", len(members))
+		b.Write(csvBytes)
+		return mcp.NewToolResultText(b.String()), nil
+	})
+}
+
+// toolResultResource runs a tool handler for a resource read and returns its text as text/csv contents.
+// Tool errors become read errors; resources have no middleware, so authentication is checked here.
+func toolResultResource(ctx context.Context, apiProvider *provider.ApiProvider, logger *zap.Logger, request mcp.ReadResourceRequest, call func(ctx context.Context) (*mcp.CallToolResult, error)) ([]mcp.ResourceContents, error) {
+	if authenticated, err := auth.IsAuthenticated(ctx, apiProvider.ServerTransport(), logger); !authenticated {
+		logger.Error("Authentication failed for resource", zap.String("uri", request.Params.URI), zap.Error(err))
+		return nil, err
+	}
+
+	result, err := call(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var text string
+	if result != nil && len(result.Content) > 0 {
+		if content, ok := result.Content[0].(mcp.TextContent); ok {
+			text = content.Text
+		}
+	}
+	if result == nil || result.IsError {
+		if text == "" {
+			text = "resource could not be read"
+		}
+		return nil, errors.New(text)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/csv",
+			Text:     text,
+		},
+	}, nil
+}
+
+// toolRequest builds the tool call a resource read is answered with
+func toolRequest(arguments map[string]any) mcp.CallToolRequest {
+	var request mcp.CallToolRequest
+	request.Params.Arguments = arguments
+	return request
+}
+
+// templateArgument returns a URI template variable of a resource read. Values are percent-decoded, so
+// slack://channel/%23general/history reads #general.
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	var value string
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) > 0 {
+			value = v[0]
+		}
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+	return strings.TrimSpace(value)
+}
+
+// CompletionsHandler suggests channel and user names for resource template variables and prompt
+// arguments, from the channels and users caches
+type CompletionsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewCompletionsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *CompletionsHandler {
+	return &CompletionsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// CompleteResourceArgument completes the {id} of the channel and user resource templates
+func (c *CompletionsHandler) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	switch {
+	case strings.HasPrefix(uri, "slack://channel/") && argument.Name == "id":
+		return c.completeChannels(argument.Value), nil
+	case strings.HasPrefix(uri, "slack://user/") && argument.Name == "id":
+		return c.completeUsers(argument.Value), nil
+	}
+	return &mcp.Completion{Values: []string{}}, nil
+}
+
+// CompletePromptArgument completes the channel argument of prompts
+func (c *CompletionsHandler) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	if argument.Name == "channel" {
+		return c.completeChannels(argument.Value), nil
+	}
+	return &mcp.Completion{Values: []string{}}, nil
+}
+
+func (c *CompletionsHandler) completeChannels(value string) *mcp.Completion {
+	prefix := strings.ToLower(strings.TrimLeft(value, "#@"))
+	var values []string
+	for id, channel := range c.apiProvider.ProvideChannelsMaps().Channels {
+		switch {
+		case strings.HasPrefix(strings.ToLower(strings.TrimLeft(channel.Name, "#@")), prefix):
+			values = append(values, channel.Name)
+		case value != "" && strings.HasPrefix(id, value):
+			values = append(values, id)
+		}
+	}
+	return completion(values)
+}
+
+func (c *CompletionsHandler) completeUsers(value string) *mcp.Completion {
+	prefix := strings.ToLower(strings.TrimPrefix(value, "@"))
+	var values []string
+	for id, user := range c.apiProvider.ProvideUsersMap().Users {
+		if user.Deleted {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(strings.ToLower(user.Name), prefix):
+			values = append(values, "@"+user.Name)
+		case value != "" && strings.HasPrefix(id, value):
+			values = append(values, id)
+		}
+	}
+	return completion(values)
+}
+
+// completion sorts the matches and keeps the first maxCompletionValues of them
+func completion(values []string) *mcp.Completion {
+	sort.Strings(values)
+	result := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		result.Values = values[:maxCompletionValues]
+		result.HasMore = true
+	}
+	if result.Values == nil {
+		result.Values = []string{}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitTemplateArgument(t *testing.T) {
+	var request mcp.ReadResourceRequest
+	request.Params.Arguments = map[string]any{
+		"id":     "%23general",
+		"ts":     []string{"1700000000.000100"},
+		"handle": "@oncall",
+	}
+
+	assert.Equal(t, "#general", templateArgument(request, "id"))
+	assert.Equal(t, "1700000000.000100", templateArgument(request, "ts"))
+	assert.Equal(t, "@oncall", templateArgument(request, "handle"))
+	assert.Equal(t, "", templateArgument(request, "missing"))
+}
+
+func TestUnitCompletion(t *testing.T) {
+	result := completion([]string{"#random", "#general", "C01AAAAAAAA"})
+	assert.Equal(t, []string{"#general", "#random", "C01AAAAAAAA"}, result.Values)
+	assert.Equal(t, 3, result.Total)
+	assert.False(t, result.HasMore)
+
+	result = completion(nil)
+	assert.NotNil(t, result.Values)
+	assert.Empty(t, result.Values)
+
+	many := make([]string, maxCompletionValues+5)
+	for i := range many {
+		many[i] = fmt.Sprintf("#channel-%03d", i)
+	}
+	result = completion(many)
+	assert.Len(t, result.Values, maxCompletionValues)
+	assert.Equal(t, maxCompletionValues+5, result.Total)
+	assert.True(t, result.HasMore)
+}
+
+// newResourceProvider serves a small Slack export through an offline provider, so resources read a known
+// channel, thread and users without Slack
+func newResourceProvider(t *testing.T, transport string) *provider.ApiProvider {
+	dir := t.TempDir()
+	files := map[string]string{
+		"channels.json": `[{"id": "C01AAAAAAAA", "name": "general", "members": ["U01AAAAAAAA", "U02BBBBBBBB"]}]`,
+		"users.json": `[
+			{"id": "U01AAAAAAAA", "name": "alice", "real_name": "Alice Liddell", "profile": {"title": "SRE"}},
+			{"id": "U02BBBBBBBB", "name": "bob", "real_name": "Bob Dobbs"}
+		]`,
+		"general/2023-11-14.json": `[
+			{"type": "message", "user": "U01AAAAAAAA", "text": "deploy is stuck", "ts": "1699999200.000100",
+			 "thread_ts": "1699999200.000100", "reply_count": 1, "latest_reply": "1699999300.000100"},
+			{"type": "message", "user": "U02BBBBBBBB", "text": "retrying it", "ts": "1699999300.000100",
+			 "thread_ts": "1699999200.000100", "parent_user_id": "U01AAAAAAAA"},
+			{"type": "message", "user": "U02BBBBBBBB", "text": "deploy done", "ts": "1699999400.000100"}
+		]`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	t.Setenv("SLACK_MCP_OFFLINE_ARCHIVE", dir)
+	p := provider.New(transport, zap.NewNop())
+	t.Cleanup(func() { p.Close() })
+	require.NoError(t, p.RefreshUsers(context.Background()))
+	require.NoError(t, p.RefreshChannels(context.Background()))
+	return p
+}
+
+// readRequest matches uri against a resource template the way the MCP server does before calling the
+// template's handler
+func readRequest(t *testing.T, uriTemplate, uri string) mcp.ReadResourceRequest {
+	template := mcp.NewResourceTemplate(uriTemplate, "test")
+	require.NotNil(t, template.URITemplate.Regexp().FindStringIndex(uri), "%s does not match %s", uri, uriTemplate)
+
+	var request mcp.ReadResourceRequest
+	request.Params.URI = uri
+	request.Params.Arguments = make(map[string]any)
+	for name, value := range template.URITemplate.Match(uri) {
+		request.Params.Arguments[name] = value.V
+	}
+	return request
+}
+
+func resourceText(t *testing.T, contents []mcp.ResourceContents) string {
+	t.Helper()
+	require.Len(t, contents, 1)
+	text, ok := contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, "text/csv", text.MIMEType)
+	return text.Text
+}
+
+func TestUnitChannelHistoryResource(t *testing.T) {
+	ch := NewConversationsHandler(newResourceProvider(t, "stdio"), zap.NewNop())
+	ctx := context.Background()
+
+	for _, uri := range []string{"slack://channel/C01AAAAAAAA/history", "slack://channel/%23general/history"} {
+		contents, err := ch.ChannelHistoryResource(ctx, readRequest(t, "slack://channel/{id}/history", uri))
+		require.NoError(t, err, uri)
+		text := resourceText(t, contents)
+		assert.Equal(t, uri, contents[0].(mcp.TextResourceContents).URI)
+		assert.Contains(t, text, "deploy done")
+		assert.Contains(t, text, "deploy is stuck")
+		assert.NotContains(t, text, "retrying it", "thread replies are not part of the history")
+	}
+
+	_, err := ch.ChannelHistoryResource(ctx, readRequest(t, "slack://channel/{id}/history", "slack://channel/%23nowhere/history"))
+	assert.ErrorContains(t, err, "nowhere")
+}
+
+func TestUnitThreadResource(t *testing.T) {
+	ch := NewConversationsHandler(newResourceProvider(t, "stdio"), zap.NewNop())
+	ctx := context.Background()
+	const template = "slack://channel/{id}/thread/{ts}"
+
+	contents, err := ch.ThreadResource(ctx, readRequest(t, template, "slack://channel/C01AAAAAAAA/thread/1699999200.000100"))
+	require.NoError(t, err)
+	text := resourceText(t, contents)
+	assert.Contains(t, text, "deploy is stuck")
+	assert.Contains(t, text, "retrying it")
+	assert.NotContains(t, text, "deploy done")
+
+	_, err = ch.ThreadResource(ctx, readRequest(t, template, "slack://channel/C01AAAAAAAA/thread/1699999999.000100"))
+	assert.Error(t, err, "a thread that does not exist")
+}
+
+func TestUnitUserResource(t *testing.T) {
+	uh := NewUsersHandler(newResourceProvider(t, "stdio"), zap.NewNop())
+	ctx := context.Background()
+
+	for _, uri := range []string{"slack://user/U01AAAAAAAA", "slack://user/%40alice"} {
+		contents, err := uh.UserResource(ctx, readRequest(t, "slack://user/{id}", uri))
+		require.NoError(t, err, uri)
+		text := resourceText(t, contents)
+		assert.Contains(t, text, "Alice Liddell")
+		assert.Contains(t, text, "SRE")
+	}
+
+	_, err := uh.UserResource(ctx, readRequest(t, "slack://user/{id}", "slack://user/%40nobody"))
+	assert.EqualError(t, err, "user @nobody not found")
+	_, err = uh.UserResource(ctx, readRequest(t, "slack://user/{id}", "slack://user/U09ZZZZZZZZ"))
+	assert.ErrorContains(t, err, "user_not_found")
+}
+
+func TestUnitResourceAuthentication(t *testing.T) {
+	t.Setenv("SLACK_MCP_API_KEY", "secret")
+	uh := NewUsersHandler(newResourceProvider(t, "http"), zap.NewNop())
+	request := readRequest(t, "slack://user/{id}", "slack://user/U01AAAAAAAA")
+
+	_, err := uh.UserResource(context.Background(), request)
+	assert.ErrorContains(t, err, "missing auth")
+
+	withKey := func(key string) context.Context {
+		r, err := http.NewRequest(http.MethodPost, "/mcp", nil)
+		require.NoError(t, err)
+		r.Header.Set("Authorization", "Bearer "+key)
+		return auth.AuthFromRequest(zap.NewNop())(context.Background(), r)
+	}
+	_, err = uh.UserResource(withKey("wrong"), request)
+	assert.ErrorContains(t, err, "invalid auth token")
+
+	contents, err := uh.UserResource(withKey("secret"), request)
+	require.NoError(t, err)
+	assert.Contains(t, resourceText(t, contents), "Alice Liddell")
+}
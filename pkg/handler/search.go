@@ -8,10 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
@@ -21,17 +19,6 @@ import (
 	"go.uber.org/zap"
 )
 
-var validFilterKeys = map[string]struct{}{
-	"is":     {},
-	"in":     {},
-	"from":   {},
-	"with":   {},
-	"before": {},
-	"after":  {},
-	"on":     {},
-	"during": {},
-}
-
 type SearchHandler struct {
 	apiProvider *provider.ApiProvider
 	logger      *zap.Logger
@@ -108,6 +95,7 @@ func (sh *SearchHandler) SearchMessagesHandler(ctx context.Context, request mcp.
 		return mcp.NewToolResultErrorFromErr("Failed to search messages", err), nil
 	}
 	sh.logger.Debug("Search completed", zap.Int("matches", len(messagesRes.Matches)))
+	sh.apiProvider.ArchiveSearchMatches(messagesRes.Matches)
 
 	messages := sh.convertMessagesFromSearch(messagesRes.Matches)
 
@@ -222,7 +210,7 @@ func (sh *SearchHandler) convertMessagesFromSearch(slackMessages []slack.SearchM
 
 func (sh *SearchHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*searchParams, error) {
 	rawQuery := strings.TrimSpace(req.GetString("search_query", ""))
-	freeText, filters := splitQuery(rawQuery)
+	freeText, filters := provider.SplitSearchQuery(rawQuery)
 
 	if req.GetBool("filter_threads_only", false) {
 		addFilter(filters, "is", "thread")
@@ -278,28 +266,9 @@ func (sh *SearchHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*search
 	cursor := req.GetString("cursor", "")
 	sort := req.GetString("sort", "relevance")
 
-	var (
-		page          int
-		decodedCursor []byte
-	)
-	if cursor != "" {
-		decodedCursor, err = base64.StdEncoding.DecodeString(cursor)
-		if err != nil {
-			sh.logger.Error("Invalid cursor decoding", zap.String("cursor", cursor), zap.Error(err))
-			return nil, fmt.Errorf("invalid cursor: %v", err)
-		}
-		parts := strings.Split(string(decodedCursor), ":")
-		if len(parts) != 2 {
-			sh.logger.Error("Invalid cursor format", zap.String("cursor", cursor))
-			return nil, fmt.Errorf("invalid cursor: %v", cursor)
-		}
-		page, err = strconv.Atoi(parts[1])
-		if err != nil || page < 1 {
-			sh.logger.Error("Invalid cursor page", zap.String("cursor", cursor), zap.Error(err))
-			return nil, fmt.Errorf("invalid cursor page: %v", err)
-		}
-	} else {
-		page = 1
+	page, err := sh.parsePageCursor(cursor)
+	if err != nil {
+		return nil, err
 	}
 
 	sh.logger.Debug("Search parameters built",
@@ -316,6 +285,29 @@ func (sh *SearchHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*search
 	}, nil
 }
 
+// parsePageCursor returns the page a "page:N" cursor points to, 1 without a cursor
+func (sh *SearchHandler) parsePageCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 1, nil
+	}
+	decodedCursor, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		sh.logger.Error("Invalid cursor decoding", zap.String("cursor", cursor), zap.Error(err))
+		return 0, fmt.Errorf("invalid cursor: %v", err)
+	}
+	parts := strings.Split(string(decodedCursor), ":")
+	if len(parts) != 2 {
+		sh.logger.Error("Invalid cursor format", zap.String("cursor", cursor))
+		return 0, fmt.Errorf("invalid cursor: %v", cursor)
+	}
+	page, err := strconv.Atoi(parts[1])
+	if err != nil || page < 1 {
+		sh.logger.Error("Invalid cursor page", zap.String("cursor", cursor), zap.Error(err))
+		return 0, fmt.Errorf("invalid cursor page: %v", err)
+	}
+	return page, nil
+}
+
 func (sh *SearchHandler) paramFormatUser(raw string) (string, error) {
 	users := sh.apiProvider.ProvideUsersMap()
 	raw = strings.TrimSpace(raw)
@@ -406,131 +398,13 @@ func extractThreadTS(rawurl string) (string, error) {
 	return u.Query().Get("thread_ts"), nil
 }
 
-func parseFlexibleDate(dateStr string) (time.Time, string, error) {
-	dateStr = strings.TrimSpace(dateStr)
-	standardFormats := []string{
-		"2006-01-02",      // YYYY-MM-DD
-		"2006/01/02",      // YYYY/MM/DD
-		"01-02-2006",      // MM-DD-YYYY
-		"01/02/2006",      // MM/DD/YYYY
-		"02-01-2006",      // DD-MM-YYYY
-		"02/01/2006",      // DD/MM/YYYY
-		"Jan 2, 2006",     // Jan 2, 2006
-		"January 2, 2006", // January 2, 2006
-		"2 Jan 2006",      // 2 Jan 2006
-		"2 January 2006",  // 2 January 2006
-	}
-	for _, fmtStr := range standardFormats {
-		if t, err := time.Parse(fmtStr, dateStr); err == nil {
-			return t, t.Format("2006-01-02"), nil
-		}
-	}
-
-	monthMap := map[string]int{
-		"january": 1, "jan": 1,
-		"february": 2, "feb": 2,
-		"march": 3, "mar": 3,
-		"april": 4, "apr": 4,
-		"may":  5,
-		"june": 6, "jun": 6,
-		"july": 7, "jul": 7,
-		"august": 8, "aug": 8,
-		"september": 9, "sep": 9, "sept": 9,
-		"october": 10, "oct": 10,
-		"november": 11, "nov": 11,
-		"december": 12, "dec": 12,
-	}
-
-	// Month-Year patterns
-	monthYear := regexp.MustCompile(`^(\d{4})\s+([A-Za-z]+)$|^([A-Za-z]+)\s+(\d{4})$`)
-	if m := monthYear.FindStringSubmatch(dateStr); m != nil {
-		var year int
-		var monStr string
-		if m[1] != "" && m[2] != "" {
-			year, _ = strconv.Atoi(m[1])
-			monStr = strings.ToLower(m[2])
-		} else {
-			year, _ = strconv.Atoi(m[4])
-			monStr = strings.ToLower(m[3])
-		}
-		if mon, ok := monthMap[monStr]; ok {
-			t := time.Date(year, time.Month(mon), 1, 0, 0, 0, 0, time.UTC)
-			return t, t.Format("2006-01-02"), nil
-		}
-	}
-
-	// Day-Month-Year and Month-Day-Year patterns
-	dmy1 := regexp.MustCompile(`^(\d{1,2})[-\s]+([A-Za-z]+)[-\s]+(\d{4})$`)
-	if m := dmy1.FindStringSubmatch(dateStr); m != nil {
-		day, _ := strconv.Atoi(m[1])
-		year, _ := strconv.Atoi(m[3])
-		monStr := strings.ToLower(m[2])
-		if mon, ok := monthMap[monStr]; ok {
-			t := time.Date(year, time.Month(mon), day, 0, 0, 0, 0, time.UTC)
-			if t.Day() == day {
-				return t, t.Format("2006-01-02"), nil
-			}
-		}
-	}
-	mdy := regexp.MustCompile(`^([A-Za-z]+)[-\s]+(\d{1,2})[-\s]+(\d{4})$`)
-	if m := mdy.FindStringSubmatch(dateStr); m != nil {
-		monStr := strings.ToLower(m[1])
-		day, _ := strconv.Atoi(m[2])
-		year, _ := strconv.Atoi(m[3])
-		if mon, ok := monthMap[monStr]; ok {
-			t := time.Date(year, time.Month(mon), day, 0, 0, 0, 0, time.UTC)
-			if t.Day() == day {
-				return t, t.Format("2006-01-02"), nil
-			}
-		}
-	}
-	ymd := regexp.MustCompile(`^(\d{4})[-\s]+([A-Za-z]+)[-\s]+(\d{1,2})$`)
-	if m := ymd.FindStringSubmatch(dateStr); m != nil {
-		year, _ := strconv.Atoi(m[1])
-		monStr := strings.ToLower(m[2])
-		day, _ := strconv.Atoi(m[3])
-		if mon, ok := monthMap[monStr]; ok {
-			t := time.Date(year, time.Month(mon), day, 0, 0, 0, 0, time.UTC)
-			if t.Day() == day {
-				return t, t.Format("2006-01-02"), nil
-			}
-		}
-	}
-
-	lower := strings.ToLower(dateStr)
-	now := time.Now().UTC()
-	switch lower {
-	case "today":
-		t := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-		return t, t.Format("2006-01-02"), nil
-	case "yesterday":
-		t := now.AddDate(0, 0, -1)
-		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-		return t, t.Format("2006-01-02"), nil
-	case "tomorrow":
-		t := now.AddDate(0, 0, 1)
-		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-		return t, t.Format("2006-01-02"), nil
-	}
-
-	daysAgo := regexp.MustCompile(`^(\d+)\s+days?\s+ago$`)
-	if m := daysAgo.FindStringSubmatch(lower); m != nil {
-		days, _ := strconv.Atoi(m[1])
-		t := now.AddDate(0, 0, -days)
-		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
-		return t, t.Format("2006-01-02"), nil
-	}
-
-	return time.Time{}, "", fmt.Errorf("unable to parse date: %s", dateStr)
-}
-
 func buildDateFilters(before, after, on, during string) (map[string]string, error) {
 	out := make(map[string]string)
 	if on != "" {
 		if during != "" || before != "" || after != "" {
 			return nil, fmt.Errorf("'on' cannot be combined with other date filters")
 		}
-		_, normalized, err := parseFlexibleDate(on)
+		_, normalized, err := text.ParseFlexibleDate(on)
 		if err != nil {
 			return nil, fmt.Errorf("invalid 'on' date: %v", err)
 		}
@@ -541,7 +415,7 @@ func buildDateFilters(before, after, on, during string) (map[string]string, erro
 		if before != "" || after != "" {
 			return nil, fmt.Errorf("'during' cannot be combined with 'before' or 'after'")
 		}
-		_, normalized, err := parseFlexibleDate(during)
+		_, normalized, err := text.ParseFlexibleDate(during)
 		if err != nil {
 			return nil, fmt.Errorf("invalid 'during' date: %v", err)
 		}
@@ -549,22 +423,22 @@ func buildDateFilters(before, after, on, during string) (map[string]string, erro
 		return out, nil
 	}
 	if after != "" {
-		_, normalized, err := parseFlexibleDate(after)
+		_, normalized, err := text.ParseFlexibleDate(after)
 		if err != nil {
 			return nil, fmt.Errorf("invalid 'after' date: %v", err)
 		}
 		out["after"] = normalized
 	}
 	if before != "" {
-		_, normalized, err := parseFlexibleDate(before)
+		_, normalized, err := text.ParseFlexibleDate(before)
 		if err != nil {
 			return nil, fmt.Errorf("invalid 'before' date: %v", err)
 		}
 		out["before"] = normalized
 	}
 	if after != "" && before != "" {
-		a, _, _ := parseFlexibleDate(after)
-		b, _, _ := parseFlexibleDate(before)
+		a, _, _ := text.ParseFlexibleDate(after)
+		b, _, _ := text.ParseFlexibleDate(before)
 		if a.After(b) {
 			return nil, fmt.Errorf("'after' date is after 'before' date")
 		}
@@ -572,25 +446,6 @@ func buildDateFilters(before, after, on, during string) (map[string]string, erro
 	return out, nil
 }
 
-func isFilterKey(key string) bool {
-	_, ok := validFilterKeys[strings.ToLower(key)]
-	return ok
-}
-
-func splitQuery(q string) (freeText []string, filters map[string][]string) {
-	filters = make(map[string][]string)
-	for _, tok := range strings.Fields(q) {
-		parts := strings.SplitN(tok, ":", 2)
-		if len(parts) == 2 && isFilterKey(parts[0]) {
-			key := strings.ToLower(parts[0])
-			filters[key] = append(filters[key], parts[1])
-		} else {
-			freeText = append(freeText, tok)
-		}
-	}
-	return
-}
-
 func addFilter(filters map[string][]string, key, val string) {
 	for _, existing := range filters[key] {
 		if existing == val {
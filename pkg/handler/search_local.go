@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+type localSearchParams struct {
+	query provider.MessageQuery
+	limit int
+	page  int
+	sort  string
+}
+
+// SearchLocalMessagesHandler searches the local message archive with the filters of search_messages.
+// It works with bot tokens and costs no Slack API calls, but only finds messages the server has seen.
+func (sh *SearchHandler) SearchLocalMessagesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sh.logger.Debug("SearchLocalMessagesHandler called", zap.Any("params", request.Params))
+
+	archive := sh.apiProvider.MessageArchive()
+	if archive == nil {
+		return mcp.NewToolResultError("The local message archive is disabled. Set SLACK_MCP_MESSAGE_ARCHIVE=true to record messages, or SLACK_MCP_SYNC_CHANNELS to sync channels into it."), nil
+	}
+
+	params, err := sh.parseParamsToolSearchLocal(ctx, request)
+	if err != nil {
+		sh.logger.Error("Failed to parse local search params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse search parameters", err), nil
+	}
+
+	fields := request.GetString("fields", "msgID,userUser,realName,channelID,text,time")
+	requestedFields := sh.parseFields(fields)
+	if fields == "all" {
+		delete(requestedFields, "reactions")
+	}
+	if requestedFields["files"] || requestedFields["filesFull"] || requestedFields["reactions"] {
+		return mcp.NewToolResultError("files, filesFull and reactions are not kept in the local message archive. Use get_channel_messages or get_thread_messages to retrieve them."), nil
+	}
+
+	matches, err := archive.Search(params.query)
+	if err != nil {
+		sh.logger.Error("Local message search failed", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to search the local message archive", err), nil
+	}
+	if params.sort == "oldest_first" {
+		for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+			matches[i], matches[j] = matches[j], matches[i]
+		}
+	}
+	sh.logger.Debug("Local search completed", zap.Int("matches", len(matches)))
+
+	total := len(matches)
+	pageCount := (total + params.limit - 1) / params.limit
+	first := (params.page - 1) * params.limit
+	last := first + params.limit
+	if first > total {
+		first = total
+	}
+	if last > total {
+		last = total
+	}
+	messages := sh.convertArchivedMessages(matches[first:last])
+
+	csvBytes, err := sh.marshalSearchMessagesWithFields(messages, requestedFields)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format search results", err), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# Total messages: %d\n", total))
+	result.WriteString(fmt.Sprintf("# Total pages: %d\n", pageCount))
+	result.WriteString(fmt.Sprintf("# Current page: %d\n", params.page))
+	result.WriteString(fmt.Sprintf("# Items per page: %d\n", params.limit))
+	result.WriteString(fmt.Sprintf("# Returned in this page: %d\n", len(messages)))
+	if len(messages) > 0 {
+		result.WriteString(fmt.Sprintf("# Item range: %d-%d\n", first+1, last))
+	}
+	if last < total {
+		nextCursor := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("page:%d", params.page+1)))
+		result.WriteString(fmt.Sprintf("# Next cursor: %s\n", nextCursor))
+	} else {
+		result.WriteString("# Next cursor: (none - last page)\n")
+	}
+	result.Write(csvBytes)
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+func (sh *SearchHandler) convertArchivedMessages(archived []provider.ArchivedMessage) []SearchMessage {
+	usersMap := sh.apiProvider.ProvideUsersMap()
+	channelsMaps := sh.apiProvider.ProvideChannelsMaps()
+	workspaceURL := sh.apiProvider.WorkspaceURL()
+
+	messages := make([]SearchMessage, 0, len(archived))
+	for _, msg := range archived {
+		userID := msg.User
+		userName, realName, ok := getUserInfo(msg.User, usersMap.Users)
+		if !ok {
+			if msg.Username != "" {
+				userName, realName = msg.Username, msg.Username
+			} else if botUser, found := getBotInfo(msg.User, sh.apiProvider); found {
+				userID = botUser.ID
+				userName = botUser.Name
+				realName = botUser.RealName
+			}
+		}
+
+		channel := msg.Channel
+		if c, ok := channelsMaps.Channels[msg.Channel]; ok {
+			channel = c.Name
+		}
+
+		timestamp, err := text.TimestampToIsoRFC3339(msg.Ts)
+		if err != nil {
+			sh.logger.Error("Failed to convert timestamp to RFC3339", zap.Error(err))
+			continue
+		}
+
+		messages = append(messages, SearchMessage{
+			MsgID:     msg.Ts,
+			UserID:    userID,
+			UserName:  userName,
+			RealName:  realName,
+			Text:      text.ProcessText(msg.Text),
+			Channel:   channel,
+			ThreadTs:  msg.ThreadTs,
+			Time:      timestamp,
+			Permalink: text.MessagePermalink(workspaceURL, msg.Channel, msg.Ts, msg.ThreadTs),
+		})
+	}
+	return messages
+}
+
+// parseParamsToolSearchLocal reads the search_messages parameters and query filters into a query of the
+// local archive. Names are resolved through the caches; with: is not supported, since the archive does
+// not know who took part in a thread or DM.
+func (sh *SearchHandler) parseParamsToolSearchLocal(ctx context.Context, req mcp.CallToolRequest) (*localSearchParams, error) {
+	rawQuery := strings.TrimSpace(req.GetString("search_query", ""))
+	freeText, filters := provider.SplitSearchQuery(rawQuery)
+
+	if req.GetBool("filter_threads_only", false) {
+		addFilter(filters, "is", "thread")
+	}
+	if chName := req.GetString("filter_in_channel", ""); chName != "" {
+		addFilter(filters, "in", chName)
+	} else if im := req.GetString("filter_in_im_or_mpim", ""); im != "" {
+		addFilter(filters, "in", im)
+	}
+	if from := req.GetString("filter_users_from", ""); from != "" {
+		addFilter(filters, "from", from)
+	}
+	dateMap, err := buildDateFilters(
+		req.GetString("filter_date_before", ""),
+		req.GetString("filter_date_after", ""),
+		req.GetString("filter_date_on", ""),
+		req.GetString("filter_date_during", ""),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for key, val := range dateMap {
+		addFilter(filters, key, val)
+	}
+
+	query, err := provider.NewMessageQuery(freeText, filters, sh.localChannelID, func(raw string) (string, error) {
+		return sh.localUserID(ctx, raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := sh.parsePageCursor(req.GetString("cursor", ""))
+	if err != nil {
+		return nil, err
+	}
+	limit := req.GetInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		return nil, fmt.Errorf("limit must be between 1 and 100, got %d", limit)
+	}
+	sort := req.GetString("sort", "newest_first")
+	if sort != "newest_first" && sort != "oldest_first" {
+		return nil, fmt.Errorf("invalid sort %q, expected newest_first or oldest_first", sort)
+	}
+
+	sh.logger.Debug("Local search parameters built",
+		zap.Any("query", query),
+		zap.Int("limit", limit),
+		zap.Int("page", page),
+		zap.String("sort", sort),
+	)
+	return &localSearchParams{query: query, limit: limit, page: page, sort: sort}, nil
+}
+
+// localChannelID resolves #channel, @user DM names, <#C...> mentions and IDs to a conversation ID
+func (sh *SearchHandler) localChannelID(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "<#") && strings.HasSuffix(raw, ">") {
+		id, _, _ := strings.Cut(raw[2:len(raw)-1], "|")
+		return id, nil
+	}
+	if slackIDRe.MatchString(raw) && strings.ContainsAny(raw[:1], "CGD") {
+		return raw, nil
+	}
+
+	name := raw
+	if !strings.HasPrefix(name, "#") && !strings.HasPrefix(name, "@") {
+		name = "#" + name
+	}
+	if id, ok := sh.apiProvider.ProvideChannelsMaps().ChannelsInv[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("channel %q not found", raw)
+}
+
+// localUserID resolves me, @handle, <@U...> mentions and IDs to a user ID
+func (sh *SearchHandler) localUserID(ctx context.Context, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "me" {
+		authResp, err := sh.apiProvider.Slack().AuthTestContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+		return authResp.UserID, nil
+	}
+	users := sh.apiProvider.ProvideUsersMap()
+	return resolveUserID(raw, users.Users, users.UsersInv)
+}
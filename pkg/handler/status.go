@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxStatusTextLength is the limit Slack applies to status_text
+const maxStatusTextLength = 100
+
+// Status is the row returned by set_my_status and clear_my_status
+type Status struct {
+	Text       string `csv:"status_text"`
+	Emoji      string `csv:"status_emoji"`
+	Expiration string `csv:"status_expiration"`
+}
+
+// DNDInfo is one row of the Do Not Disturb report
+type DNDInfo struct {
+	UserID        string `csv:"user_id"`
+	UserName      string `csv:"user_name"`
+	DNDEnabled    bool   `csv:"dnd_enabled"`
+	NextDNDStart  string `csv:"next_dnd_start"`
+	NextDNDEnd    string `csv:"next_dnd_end"`
+	SnoozeEnabled bool   `csv:"snooze_enabled"`
+	SnoozeEnd     string `csv:"snooze_end"`
+}
+
+// SetMyStatusHandler sets the custom status of the authenticated user
+func (uh *UsersHandler) SetMyStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("SetMyStatusHandler called", zap.Any("params", request.Params))
+
+	text := strings.TrimSpace(request.GetString("text", ""))
+	emoji := normalizeStatusEmoji(request.GetString("emoji", ""))
+	if text == "" && emoji == "" {
+		return mcp.NewToolResultError("text or emoji must be provided, use clear_my_status to remove the status"), nil
+	}
+	if n := len([]rune(text)); n > maxStatusTextLength {
+		return mcp.NewToolResultError(fmt.Sprintf("text is %d characters long, Slack allows at most %d", n, maxStatusTextLength)), nil
+	}
+
+	var expiration time.Time
+	if raw := request.GetString("expiration", ""); raw != "" {
+		var err error
+		expiration, err = parseFutureTimeOrDuration(raw, time.Now())
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid expiration", err), nil
+		}
+	}
+
+	var expirationUnix int64
+	if !expiration.IsZero() {
+		expirationUnix = expiration.Unix()
+	}
+	if err := uh.apiProvider.Slack().SetUserCustomStatusContext(ctx, text, emoji, expirationUnix); err != nil {
+		uh.logger.Error("Failed to set status", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to set status", err), nil
+	}
+
+	return uh.statusResult("Status updated", Status{
+		Text:       text,
+		Emoji:      emoji,
+		Expiration: formatJSONTime(slack.JSONTime(expirationUnix)),
+	})
+}
+
+// ClearMyStatusHandler removes the custom status of the authenticated user
+func (uh *UsersHandler) ClearMyStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("ClearMyStatusHandler called", zap.Any("params", request.Params))
+
+	if err := uh.apiProvider.Slack().UnsetUserCustomStatusContext(ctx); err != nil {
+		uh.logger.Error("Failed to clear status", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to clear status", err), nil
+	}
+
+	return uh.statusResult("Status cleared", Status{})
+}
+
+// SetMyPresenceHandler sets the presence of the authenticated user to auto or away
+func (uh *UsersHandler) SetMyPresenceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("SetMyPresenceHandler called", zap.Any("params", request.Params))
+
+	presence := strings.ToLower(strings.TrimSpace(request.GetString("presence", "")))
+	if presence != "auto" && presence != "away" {
+		return mcp.NewToolResultError("presence must be either 'auto' or 'away'"), nil
+	}
+
+	if err := uh.apiProvider.Slack().SetUserPresenceContext(ctx, presence); err != nil {
+		uh.logger.Error("Failed to set presence", zap.String("presence", presence), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to set presence", err), nil
+	}
+
+	type PresenceResult struct {
+		Presence string `csv:"presence"`
+	}
+	csvBytes, err := gocsv.MarshalBytes([]PresenceResult{{Presence: presence}})
+	if err != nil {
+		uh.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+
+	return mcp.NewToolResultText("# Presence updated\n" + string(csvBytes)), nil
+}
+
+// SnoozeDNDHandler turns on Do Not Disturb for the authenticated user for a duration or until a time
+func (uh *UsersHandler) SnoozeDNDHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("SnoozeDNDHandler called", zap.Any("params", request.Params))
+
+	raw := strings.TrimSpace(request.GetString("duration", ""))
+	if raw == "" {
+		return mcp.NewToolResultError("duration must be provided"), nil
+	}
+	minutes, err := parseSnoozeMinutes(raw, time.Now())
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid duration", err), nil
+	}
+
+	status, err := uh.apiProvider.Slack().SetSnoozeContext(ctx, minutes)
+	if err != nil {
+		uh.logger.Error("Failed to snooze notifications", zap.Int("minutes", minutes), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to snooze notifications", err), nil
+	}
+
+	uh.logger.Info("Notifications snoozed", zap.Int("minutes", minutes))
+
+	return uh.selfDNDResult(ctx, fmt.Sprintf("Notifications snoozed for %d minutes", minutes), status)
+}
+
+// EndDNDHandler ends the current snooze and any scheduled Do Not Disturb session of the authenticated user
+func (uh *UsersHandler) EndDNDHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("EndDNDHandler called", zap.Any("params", request.Params))
+
+	if _, err := uh.apiProvider.Slack().EndSnoozeContext(ctx); err != nil && !strings.Contains(err.Error(), "snooze_not_active") {
+		uh.logger.Error("Failed to end snooze", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to end snooze", err), nil
+	}
+	if err := uh.apiProvider.Slack().EndDNDContext(ctx); err != nil {
+		uh.logger.Error("Failed to end Do Not Disturb", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to end Do Not Disturb", err), nil
+	}
+
+	return uh.selfDNDResult(ctx, "Do Not Disturb ended", nil)
+}
+
+// GetDNDInfoHandler reports the Do Not Disturb state of the authenticated user or of a list of users
+func (uh *UsersHandler) GetDNDInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("GetDNDInfoHandler called", zap.Any("params", request.Params))
+
+	entries := parseCommaSeparatedList(request.GetString("users", ""))
+	if len(entries) == 0 {
+		return uh.selfDNDResult(ctx, "Do Not Disturb status", nil)
+	}
+
+	usersMap := uh.apiProvider.ProvideUsersMap()
+	userIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		id, err := resolveUserID(entry, usersMap.Users, usersMap.UsersInv)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to resolve user", err), nil
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	statuses, err := uh.apiProvider.Slack().GetDNDTeamInfoContext(ctx, userIDs)
+	if err != nil {
+		uh.logger.Error("Failed to get Do Not Disturb info", zap.Strings("users", userIDs), zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to get Do Not Disturb info", err), nil
+	}
+
+	rows := make([]DNDInfo, 0, len(userIDs))
+	for _, id := range userIDs {
+		status, ok := statuses[id]
+		if !ok {
+			continue
+		}
+		rows = append(rows, uh.toDNDInfo(id, status))
+	}
+
+	return uh.dndReport(fmt.Sprintf("Do Not Disturb status for %d users", len(rows)), rows)
+}
+
+// selfDNDResult renders the DND state of the authenticated user, fetching it when status is nil
+func (uh *UsersHandler) selfDNDResult(ctx context.Context, header string, status *slack.DNDStatus) (*mcp.CallToolResult, error) {
+	if status == nil {
+		var err error
+		status, err = uh.apiProvider.Slack().GetDNDInfoContext(ctx, nil)
+		if err != nil {
+			uh.logger.Error("Failed to get Do Not Disturb info", zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to get Do Not Disturb info", err), nil
+		}
+	}
+
+	var userID string
+	if authResponse, err := uh.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		userID = authResponse.UserID
+	}
+
+	return uh.dndReport(header, []DNDInfo{uh.toDNDInfo(userID, *status)})
+}
+
+func (uh *UsersHandler) toDNDInfo(userID string, status slack.DNDStatus) DNDInfo {
+	userName, _, _ := getUserInfo(userID, uh.apiProvider.ProvideUsersMap().Users)
+	info := DNDInfo{
+		UserID:        userID,
+		UserName:      userName,
+		DNDEnabled:    status.Enabled,
+		NextDNDStart:  formatJSONTime(slack.JSONTime(int64(status.NextStartTimestamp))),
+		NextDNDEnd:    formatJSONTime(slack.JSONTime(int64(status.NextEndTimestamp))),
+		SnoozeEnabled: status.SnoozeEnabled,
+	}
+	if status.SnoozeEnabled {
+		info.SnoozeEnd = formatJSONTime(slack.JSONTime(int64(status.SnoozeEndTime)))
+	}
+	return info
+}
+
+func (uh *UsersHandler) dndReport(header string, rows []DNDInfo) (*mcp.CallToolResult, error) {
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		uh.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+	return mcp.NewToolResultText("# " + header + "\n" + string(csvBytes)), nil
+}
+
+func (uh *UsersHandler) statusResult(header string, status Status) (*mcp.CallToolResult, error) {
+	csvBytes, err := gocsv.MarshalBytes([]Status{status})
+	if err != nil {
+		uh.logger.Error("Failed to marshal result to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format result as CSV", err), nil
+	}
+	return mcp.NewToolResultText("# " + header + "\n" + string(csvBytes)), nil
+}
+
+// normalizeStatusEmoji wraps an emoji name in colons, so "rotating_light" and ":rotating_light:" are equivalent
+func normalizeStatusEmoji(emoji string) string {
+	emoji = strings.Trim(strings.TrimSpace(emoji), ":")
+	if emoji == "" {
+		return ""
+	}
+	return ":" + emoji + ":"
+}
+
+// parseSnoozeMinutes turns a plain number of minutes, an offset ("2h") or a point in time ("5pm") into
+// whole minutes from now, rounded up.
+func parseSnoozeMinutes(raw string, now time.Time) (int, error) {
+	if n, err := strconv.Atoi(raw); err == nil {
+		if n <= 0 {
+			return 0, fmt.Errorf("duration must be positive, got %d minutes", n)
+		}
+		return n, nil
+	}
+	until, err := parseFutureTimeOrDuration(raw, now)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Ceil(until.Sub(now).Minutes())), nil
+}
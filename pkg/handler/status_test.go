@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitNormalizeStatusEmoji(t *testing.T) {
+	assert.Equal(t, ":rotating_light:", normalizeStatusEmoji("rotating_light"))
+	assert.Equal(t, ":rotating_light:", normalizeStatusEmoji(":rotating_light:"))
+	assert.Equal(t, ":fire:", normalizeStatusEmoji(" :fire "))
+	assert.Equal(t, "", normalizeStatusEmoji(""))
+	assert.Equal(t, "", normalizeStatusEmoji("::"))
+}
+
+func TestUnitParseSnoozeMinutes(t *testing.T) {
+	now := time.Date(2025, 7, 16, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "60", want: 60},
+		{input: "2h", want: 120},
+		{input: "in 90 minutes", want: 90},
+		{input: "90s", want: 2},
+		{input: "5pm", want: 150},
+		{input: "0", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "later", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSnoozeMinutes(tt.input, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitResolveUserID(t *testing.T) {
+	usersMap := map[string]slack.User{
+		"U01AAAAAA1": {ID: "U01AAAAAA1", Name: "alice"},
+		"U01BBBBBB2": {ID: "U01BBBBBB2", Name: "bob.smith", Profile: slack.UserProfile{DisplayName: "Bob"}},
+	}
+	usersInv := map[string]string{"alice": "U01AAAAAA1", "bob.smith": "U01BBBBBB2"}
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "U01AAAAAA1", want: "U01AAAAAA1"},
+		{input: "W999EXTERNAL", want: "W999EXTERNAL"},
+		{input: "<@U01BBBBBB2>", want: "U01BBBBBB2"},
+		{input: "@alice", want: "U01AAAAAA1"},
+		{input: "@bob", want: "U01BBBBBB2"},
+		{input: "@nobody", wantErr: true},
+		{input: "alice", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := resolveUserID(tt.input, usersMap, usersInv)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitResolveDisplayName(t *testing.T) {
+	usersMap := map[string]slack.User{
+		"U01AAAAAA1": {ID: "U01AAAAAA1", Name: "jane", RealName: "Jane Doe", Profile: slack.UserProfile{DisplayName: "Jane D"}},
+		"U01BBBBBB2": {ID: "U01BBBBBB2", Name: "jd", RealName: "John Doe", Profile: slack.UserProfile{DisplayName: "JD"}},
+		"U01CCCCCC3": {ID: "U01CCCCCC3", Name: "jd2", RealName: "Jack Dee", Profile: slack.UserProfile{DisplayName: "jd"}},
+	}
+
+	id, err := resolveDisplayName("jane doe", usersMap)
+	require.NoError(t, err)
+	assert.Equal(t, "U01AAAAAA1", id)
+	id, err = resolveDisplayName("Jane D", usersMap)
+	require.NoError(t, err)
+	assert.Equal(t, "U01AAAAAA1", id)
+
+	_, err = resolveDisplayName("JD", usersMap)
+	assert.ErrorContains(t, err, "several users")
+	_, err = resolveDisplayName("Nobody", usersMap)
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// SubscriptionsHandler keeps the resource subscriptions of MCP sessions and polls Slack for new messages
+// in the subscribed channels and threads. Only slack://channel/{id}/history and
+// slack://channel/{id}/thread/{ts} can be subscribed to; the other resources change too rarely to watch.
+type SubscriptionsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+
+	mu       sync.Mutex
+	sessions map[string]map[string]struct{} // resource URI -> subscribed session IDs
+	latest   map[string]string              // resource URI -> latest message ts seen
+}
+
+// subscriptionTarget is what a subscribed resource URI points at
+type subscriptionTarget struct {
+	channel  string // channel ID or #name/@name as given in the URI
+	threadTs string // empty for channel history
+}
+
+func NewSubscriptionsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *SubscriptionsHandler {
+	return &SubscriptionsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+		sessions:    make(map[string]map[string]struct{}),
+		latest:      make(map[string]string),
+	}
+}
+
+// Subscribe adds a session to the watchers of a resource
+func (h *SubscriptionsHandler) Subscribe(sessionID, uri string) error {
+	if _, err := parseSubscriptionURI(uri); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions[uri] == nil {
+		h.sessions[uri] = make(map[string]struct{})
+	}
+	h.sessions[uri][sessionID] = struct{}{}
+	h.logger.Debug("Resource subscribed", zap.String("uri", uri), zap.String("session", sessionID))
+	return nil
+}
+
+// Unsubscribe removes a session from the watchers of a resource. Resources nobody watches any more
+// are no longer polled.
+func (h *SubscriptionsHandler) Unsubscribe(sessionID, uri string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sessionID, uri)
+	h.logger.Debug("Resource unsubscribed", zap.String("uri", uri), zap.String("session", sessionID))
+}
+
+// RemoveSession drops every subscription of a session that went away
+func (h *SubscriptionsHandler) RemoveSession(sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for uri := range h.sessions {
+		h.removeLocked(sessionID, uri)
+	}
+}
+
+func (h *SubscriptionsHandler) removeLocked(sessionID, uri string) {
+	delete(h.sessions[uri], sessionID)
+	if len(h.sessions[uri]) == 0 {
+		delete(h.sessions, uri)
+		delete(h.latest, uri)
+	}
+}
+
+// Subscribed returns the subscribed resource URIs, sorted
+func (h *SubscriptionsHandler) Subscribed() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	uris := make([]string, 0, len(h.sessions))
+	for uri := range h.sessions {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// Run polls every interval until ctx is done, calling notify for each session subscribed to a resource
+// that changed. Rounds without subscriptions make no Slack calls.
+func (h *SubscriptionsHandler) Run(ctx context.Context, interval time.Duration, notify func(sessionID, uri string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Poll(ctx, notify)
+		}
+	}
+}
+
+// Poll checks every subscribed resource once. Channel histories are checked with a single client.counts
+// call when the token allows it, and with conversations.history otherwise; threads with
+// conversations.replies. Every call waits on the provider's rate limiter.
+func (h *SubscriptionsHandler) Poll(ctx context.Context, notify func(sessionID, uri string)) {
+	uris := h.Subscribed()
+	if len(uris) == 0 {
+		return
+	}
+
+	var counts map[string]string
+	for _, uri := range uris {
+		target, err := parseSubscriptionURI(uri)
+		if err != nil {
+			continue
+		}
+		channelID, err := h.resolveChannel(target.channel)
+		if err != nil {
+			h.logger.Warn("Skipping subscribed resource", zap.String("uri", uri), zap.Error(err))
+			continue
+		}
+
+		var latest string
+		if target.threadTs != "" {
+			latest, err = h.latestReply(ctx, channelID, target.threadTs)
+		} else {
+			if counts == nil {
+				counts = h.latestByChannel(ctx)
+			}
+			var ok bool
+			if latest, ok = counts[channelID]; !ok {
+				latest, err = h.latestMessage(ctx, channelID)
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			h.logger.Warn("Failed to poll subscribed resource", zap.String("uri", uri), zap.Error(err))
+			continue
+		}
+
+		for _, sessionID := range h.record(uri, latest) {
+			notify(sessionID, uri)
+		}
+	}
+}
+
+// record stores the latest message ts of a resource and returns the sessions to notify when it changed.
+// The first ts recorded for a resource is its baseline and notifies nobody.
+func (h *SubscriptionsHandler) record(uri, latest string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.sessions[uri]; !ok || latest == "" {
+		return nil
+	}
+	previous, seen := h.latest[uri]
+	h.latest[uri] = latest
+	if !seen || previous == latest {
+		return nil
+	}
+
+	sessionIDs := make([]string, 0, len(h.sessions[uri]))
+	for sessionID := range h.sessions[uri] {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// HandleEvent notifies the sessions subscribed to the history or thread a new message was posted in,
+// without waiting for the next poll. Edits and deletions are not new messages and are ignored.
+func (h *SubscriptionsHandler) HandleEvent(event provider.Event, notify func(sessionID, uri string)) {
+	if event.Type != "message" || event.Subtype == "message_changed" || event.Subtype == "message_deleted" || event.Ts == "" {
+		return
+	}
+
+	for _, uri := range h.Subscribed() {
+		target, err := parseSubscriptionURI(uri)
+		if err != nil {
+			continue
+		}
+		if target.threadTs != "" && target.threadTs != event.ThreadTs {
+			continue
+		}
+		// Replies only reach the channel history when they are also sent to the channel
+		if target.threadTs == "" && event.ThreadTs != "" && event.ThreadTs != event.Ts && event.Subtype != "thread_broadcast" {
+			continue
+		}
+		if channelID, err := h.resolveChannel(target.channel); err != nil || channelID != event.Channel {
+			continue
+		}
+
+		for _, sessionID := range h.recordEvent(uri, event.Ts) {
+			notify(sessionID, uri)
+		}
+	}
+}
+
+// recordEvent stores the ts of a message received as an event and returns the sessions to notify when
+// it is newer than the latest one seen. Unlike record, no baseline is needed: the message is known to be
+// new.
+func (h *SubscriptionsHandler) recordEvent(uri, ts string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.sessions[uri]; !ok {
+		return nil
+	}
+	if previous, seen := h.latest[uri]; seen && compareTs(ts, previous) <= 0 {
+		return nil
+	}
+	h.latest[uri] = ts
+
+	sessionIDs := make([]string, 0, len(h.sessions[uri]))
+	for sessionID := range h.sessions[uri] {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// compareTs orders two Slack message timestamps ("1700000000.000100")
+func compareTs(a, b string) int {
+	aSec, aMicro, _ := strings.Cut(a, ".")
+	bSec, bMicro, _ := strings.Cut(b, ".")
+	if len(aSec) != len(bSec) {
+		return len(aSec) - len(bSec)
+	}
+	return strings.Compare(aSec+"."+aMicro, bSec+"."+bMicro)
+}
+
+func (h *SubscriptionsHandler) resolveChannel(channel string) (string, error) {
+	if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
+		return channel, nil
+	}
+	channelsMaps := h.apiProvider.ProvideChannelsMaps()
+	id, ok := channelsMaps.ChannelsInv[channel]
+	if !ok {
+		return "", fmt.Errorf("channel %q not found", channel)
+	}
+	return channelsMaps.Channels[id].ID, nil
+}
+
+// latestByChannel returns the latest message ts of every channel the user is in, from client.counts.
+// OAuth tokens cannot call it; the result is then empty and callers fall back to conversations.history.
+func (h *SubscriptionsHandler) latestByChannel(ctx context.Context) map[string]string {
+	latest := make(map[string]string)
+	if h.apiProvider.IsOAuth() {
+		return latest
+	}
+	if err := h.apiProvider.WaitRateLimit(ctx); err != nil {
+		return latest
+	}
+	counts, err := h.apiProvider.Slack().ClientCounts(ctx)
+	if err != nil {
+		h.logger.Warn("ClientCounts failed, polling conversations.history instead", zap.Error(err))
+		return latest
+	}
+	for _, snapshots := range [][]edge.ChannelSnapshot{counts.Channels, counts.MPIMs, counts.IMs} {
+		for _, snap := range snapshots {
+			latest[snap.ID] = snap.Latest.SlackString()
+		}
+	}
+	return latest
+}
+
+func (h *SubscriptionsHandler) latestMessage(ctx context.Context, channelID string) (string, error) {
+	if err := h.apiProvider.WaitRateLimit(ctx); err != nil {
+		return "", err
+	}
+	history, err := h.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Limit:     1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(history.Messages) == 0 {
+		return "", nil
+	}
+	return history.Messages[0].Timestamp, nil
+}
+
+func (h *SubscriptionsHandler) latestReply(ctx context.Context, channelID, threadTs string) (string, error) {
+	if err := h.apiProvider.WaitRateLimit(ctx); err != nil {
+		return "", err
+	}
+	messages, _, _, err := h.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: threadTs,
+		Limit:     1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "", nil
+	}
+	// The parent message comes first and carries the ts of the latest reply
+	if messages[0].LatestReply != "" {
+		return messages[0].LatestReply, nil
+	}
+	return messages[0].Timestamp, nil
+}
+
+// parseSubscriptionURI accepts slack://channel/{id}/history and slack://channel/{id}/thread/{ts}
+func parseSubscriptionURI(uri string) (subscriptionTarget, error) {
+	if rest, ok := strings.CutPrefix(uri, "slack://channel/"); ok {
+		parts := strings.Split(rest, "/")
+		for i, part := range parts {
+			if unescaped, err := url.PathUnescape(part); err == nil {
+				parts[i] = unescaped
+			}
+		}
+		switch {
+		case len(parts) == 2 && parts[0] != "" && parts[1] == "history":
+			return subscriptionTarget{channel: parts[0]}, nil
+		case len(parts) == 3 && parts[0] != "" && parts[1] == "thread" && parts[2] != "":
+			return subscriptionTarget{channel: parts[0], threadTs: parts[2]}, nil
+		}
+	}
+	return subscriptionTarget{}, fmt.Errorf("resource %q does not support subscriptions; subscribe to slack://channel/{id}/history or slack://channel/{id}/thread/{ts}", uri)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitParseSubscriptionURI(t *testing.T) {
+	target, err := parseSubscriptionURI("slack://channel/C01AAAAAAAA/history")
+	require.NoError(t, err)
+	assert.Equal(t, subscriptionTarget{channel: "C01AAAAAAAA"}, target)
+
+	target, err = parseSubscriptionURI("slack://channel/%23incidents/thread/1700000000.000100")
+	require.NoError(t, err)
+	assert.Equal(t, subscriptionTarget{channel: "#incidents", threadTs: "1700000000.000100"}, target)
+
+	for _, uri := range []string{
+		"slack://user/U01AAAAAAAA",
+		"slack://channel/C01AAAAAAAA",
+		"slack://channel//history",
+		"slack://channel/C01AAAAAAAA/thread/",
+		"slack://acme/channels",
+	} {
+		_, err := parseSubscriptionURI(uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestUnitSubscriptionsRecord(t *testing.T) {
+	h := NewSubscriptionsHandler(nil, zap.NewNop())
+	const uri = "slack://channel/C01AAAAAAAA/history"
+
+	require.NoError(t, h.Subscribe("session-b", uri))
+	require.NoError(t, h.Subscribe("session-a", uri))
+	assert.Error(t, h.Subscribe("session-a", "slack://file/F01AAAAAAAA"))
+	assert.Equal(t, []string{uri}, h.Subscribed())
+
+	// The first poll only records a baseline
+	assert.Empty(t, h.record(uri, "1700000000.000100"))
+	assert.Empty(t, h.record(uri, "1700000000.000100"))
+	assert.Equal(t, []string{"session-a", "session-b"}, h.record(uri, "1700000060.000200"))
+
+	h.Unsubscribe("session-b", uri)
+	assert.Equal(t, []string{"session-a"}, h.record(uri, "1700000120.000300"))
+
+	h.RemoveSession("session-a")
+	assert.Empty(t, h.Subscribed())
+	assert.Empty(t, h.record(uri, "1700000180.000400"))
+}
+
+func TestUnitSubscriptionsHandleEvent(t *testing.T) {
+	h := NewSubscriptionsHandler(nil, zap.NewNop())
+	const (
+		history = "slack://channel/C01AAAAAAAA/history"
+		thread  = "slack://channel/C01AAAAAAAA/thread/1700000000.000100"
+	)
+	require.NoError(t, h.Subscribe("session-a", history))
+	require.NoError(t, h.Subscribe("session-b", thread))
+
+	var notified []string
+	notify := func(sessionID, uri string) { notified = append(notified, sessionID+" "+uri) }
+	deliver := func(event provider.Event) []string {
+		notified = nil
+		event.Type = "message"
+		h.HandleEvent(event, notify)
+		return notified
+	}
+
+	assert.Equal(t, []string{"session-a " + history},
+		deliver(provider.Event{Channel: "C01AAAAAAAA", Ts: "1700000000.000100"}))
+	assert.Equal(t, []string{"session-b " + thread},
+		deliver(provider.Event{Channel: "C01AAAAAAAA", Ts: "1700000010.000100", ThreadTs: "1700000000.000100"}),
+		"a reply only updates the thread")
+	assert.ElementsMatch(t, []string{"session-a " + history, "session-b " + thread},
+		deliver(provider.Event{Channel: "C01AAAAAAAA", Ts: "1700000020.000100", ThreadTs: "1700000000.000100", Subtype: "thread_broadcast"}))
+
+	assert.Empty(t, deliver(provider.Event{Channel: "C02BBBBBBBB", Ts: "1700000030.000100"}), "other channel")
+	assert.Empty(t, deliver(provider.Event{Channel: "C01AAAAAAAA", Ts: "1700000000.000100", Subtype: "message_changed"}), "edit")
+	assert.Empty(t, deliver(provider.Event{Channel: "C01AAAAAAAA", Ts: "1700000020.000100"}), "already seen")
+
+	// A poll that finds the message an event already delivered notifies nobody
+	assert.Empty(t, h.record(history, "1700000020.000100"))
+}
+
+func TestUnitCompareTs(t *testing.T) {
+	assert.Negative(t, compareTs("1700000000.000100", "1700000000.000200"))
+	assert.Positive(t, compareTs("1700000001.000100", "1700000000.999999"))
+	assert.Positive(t, compareTs("10000000000.000100", "9999999999.000100"))
+	assert.Zero(t, compareTs("1700000000.000100", "1700000000.000100"))
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// SyncedChannel is a channel synced into the message archive as returned by sync_status
+type SyncedChannel struct {
+	ChannelID  string `csv:"ChannelID"`
+	Name       string `csv:"Name"`
+	Backfilled bool   `csv:"Backfilled"`
+	Oldest     string `csv:"Oldest"`
+	Latest     string `csv:"Latest"`
+	Messages   int    `csv:"Messages"`
+	Synced     string `csv:"Synced"`
+	Error      string `csv:"Error"`
+}
+
+type SyncHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewSyncHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *SyncHandler {
+	return &SyncHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// SyncStatusHandler reports how far the channels of SLACK_MCP_SYNC_CHANNELS have been synced into the
+// message archive
+func (sh *SyncHandler) SyncStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sh.logger.Debug("SyncStatusHandler called", zap.Any("params", request.Params))
+
+	syncer := sh.apiProvider.Syncer()
+	if syncer == nil {
+		return mcp.NewToolResultError("Channel sync is disabled. Set SLACK_MCP_SYNC_CHANNELS to the channels to sync, e.g. '#inc-*'."), nil
+	}
+	status, err := syncer.Status()
+	if err != nil {
+		sh.logger.Error("Failed to read sync status", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to read sync status", err), nil
+	}
+
+	channels := make([]SyncedChannel, 0, len(status.Channels))
+	for _, state := range status.Channels {
+		channels = append(channels, SyncedChannel{
+			ChannelID:  state.Channel,
+			Name:       state.Name,
+			Backfilled: state.Backfilled,
+			Oldest:     syncTime(state.Oldest),
+			Latest:     syncTime(state.Latest),
+			Messages:   state.Messages,
+			Synced:     formatSyncTime(state.Synced),
+			Error:      state.Error,
+		})
+	}
+	csvBytes, err := gocsv.MarshalBytes(&channels)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format sync status", err), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# Patterns: %s\n", strings.Join(status.Patterns, ", ")))
+	result.WriteString(fmt.Sprintf("# Running: %t (every %s)\n", status.Running, status.Interval))
+	if status.Current != "" {
+		result.WriteString(fmt.Sprintf("# Syncing now: %s\n", status.Current))
+	}
+	if !status.LastRound.IsZero() {
+		result.WriteString(fmt.Sprintf("# Last round: %s\n", formatSyncTime(status.LastRound)))
+	}
+	if !status.NextRound.IsZero() {
+		result.WriteString(fmt.Sprintf("# Next round: %s\n", formatSyncTime(status.NextRound)))
+	}
+	result.Write(csvBytes)
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// syncTime formats the timestamp of a synced message, empty before the first one
+func syncTime(ts string) string {
+	if ts == "" {
+		return ""
+	}
+	t, err := text.TimestampToIsoRFC3339(ts)
+	if err != nil {
+		return ts
+	}
+	return t
+}
+
+func formatSyncTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+)
+
+// defaultTimeOfDay is used when a future time expression names a day but no clock time ("tomorrow").
+const defaultTimeOfDay = 9 * time.Hour
+
+var (
+	relativeUnitRe = regexp.MustCompile(`^(\d+|an?)\s*([a-z]+)$`)
+	clockRe        = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// absoluteTimeLayouts are tried in order before falling back to natural-language parsing.
+// Layouts without a zone are interpreted in the location of "now".
+var absoluteTimeLayouts = []string{
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+}
+
+// parseFutureTime parses a point in time that must lie after now. It accepts:
+//   - Unix timestamps ("1767225600")
+//   - RFC3339 and "YYYY-MM-DD HH:MM" forms
+//   - relative offsets ("in 2h", "in 90m", "in 1h30m", "in 2 hours", "in 3 days", "in a week")
+//   - a day with an optional time of day ("tomorrow 9am", "today at 17:30", "friday 2pm",
+//     "2025-07-15 9:30am", "9am"); the day part accepts everything text.ParseFlexibleDate does
+//
+// A day without a time defaults to 09:00; a bare time that has already passed today rolls over to tomorrow.
+func parseFutureTime(input string, now time.Time) (time.Time, error) {
+	raw := strings.TrimSpace(input)
+	if raw == "" {
+		return time.Time{}, errors.New("time expression is empty")
+	}
+	loc := now.Location()
+
+	if unix, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return checkFuture(time.Unix(unix, 0).In(loc), now, input)
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return checkFuture(t.In(loc), now, input)
+	}
+	for _, layout := range absoluteTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return checkFuture(t, now, input)
+		}
+	}
+
+	s := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		d, err := parseRelativeDuration(rest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse %q: %w", input, err)
+		}
+		return checkFuture(now.Add(d), now, input)
+	}
+
+	dayPart, clock, hasClock := splitTimeOfDay(s)
+	if !hasClock {
+		clock = defaultTimeOfDay
+	}
+
+	var day time.Time
+	switch {
+	case dayPart == "":
+		if !hasClock {
+			return time.Time{}, fmt.Errorf("unable to parse time expression %q", input)
+		}
+		day = startOfDay(now)
+		if !day.Add(clock).After(now) {
+			day = day.AddDate(0, 0, 1)
+		}
+	case dayPart == "today":
+		day = startOfDay(now)
+	case dayPart == "tomorrow":
+		day = startOfDay(now).AddDate(0, 0, 1)
+	default:
+		if wd, ok := parseWeekday(strings.TrimPrefix(dayPart, "next ")); ok {
+			// Always the next occurrence, never today, so "monday 9am" on a Monday means next week
+			offset := (int(wd) - int(now.Weekday()) + 7) % 7
+			if offset == 0 {
+				offset = 7
+			}
+			day = startOfDay(now).AddDate(0, 0, offset)
+			break
+		}
+		parsed, _, err := text.ParseFlexibleDate(dayPart)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse time expression %q", input)
+		}
+		day = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, loc)
+	}
+
+	return checkFuture(day.Add(clock), now, input)
+}
+
+// parseFutureTimeOrDuration is parseFutureTime that also accepts a bare offset without "in" ("2h", "90 minutes",
+// "a day"), as used for expirations.
+func parseFutureTimeOrDuration(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if !isDigits(s) {
+		if d, err := parseRelativeDuration(s); err == nil {
+			return now.Add(d), nil
+		}
+	}
+	return parseFutureTime(input, now)
+}
+
+// parseRelativeDuration parses offsets such as "2h", "1h30m", "2 hours", "1 day 3 hours" or "a week".
+func parseRelativeDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(strings.ReplaceAll(s, " ", "")); err == nil {
+		if d <= 0 {
+			return 0, errors.New("offset must be positive")
+		}
+		return d, nil
+	}
+
+	// Split "1 day 3 hours" / "2d 4h" / "1 hour and 30 minutes" into number+unit chunks
+	s = strings.ReplaceAll(s, ",", " ")
+	s = strings.ReplaceAll(s, " and ", " ")
+	tokens := strings.Fields(s)
+	var chunks []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if (isDigits(tok) || tok == "a" || tok == "an") && i+1 < len(tokens) {
+			tok += " " + tokens[i+1]
+			i++
+		}
+		chunks = append(chunks, tok)
+	}
+	if len(chunks) == 0 {
+		return 0, errors.New("missing offset")
+	}
+
+	var total time.Duration
+	for _, chunk := range chunks {
+		m := relativeUnitRe.FindStringSubmatch(chunk)
+		if m == nil {
+			return 0, fmt.Errorf("invalid offset %q", chunk)
+		}
+		n := 1
+		if m[1] != "a" && m[1] != "an" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		unit, ok := relativeUnit(m[2])
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", m[2])
+		}
+		total += time.Duration(n) * unit
+	}
+	if total <= 0 {
+		return 0, errors.New("offset must be positive")
+	}
+	return total, nil
+}
+
+func relativeUnit(u string) (time.Duration, bool) {
+	switch u {
+	case "s", "sec", "secs", "second", "seconds":
+		return time.Second, true
+	case "m", "min", "mins", "minute", "minutes":
+		return time.Minute, true
+	case "h", "hr", "hrs", "hour", "hours":
+		return time.Hour, true
+	case "d", "day", "days":
+		return 24 * time.Hour, true
+	case "w", "wk", "wks", "week", "weeks":
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// splitTimeOfDay strips a trailing clock time ("9am", "9:30 pm", "17:00", "noon") and an optional "at"
+// connector from s, returning the remaining day part and the offset from midnight.
+func splitTimeOfDay(s string) (string, time.Duration, bool) {
+	tokens := strings.Fields(s)
+	for take := 2; take >= 1; take-- {
+		if len(tokens) < take {
+			continue
+		}
+		clock, ok := parseClock(strings.Join(tokens[len(tokens)-take:], " "))
+		if !ok {
+			continue
+		}
+		rest := tokens[:len(tokens)-take]
+		if len(rest) > 0 && rest[len(rest)-1] == "at" {
+			rest = rest[:len(rest)-1]
+		}
+		return strings.Join(rest, " "), clock, true
+	}
+	return s, 0, false
+}
+
+// parseClock parses "9am", "9:30 pm", "17:00", "noon" and "midnight" into an offset from midnight.
+// A bare number without am/pm is not treated as a clock time so it cannot swallow a day of month.
+func parseClock(s string) (time.Duration, bool) {
+	switch s {
+	case "noon":
+		return 12 * time.Hour, true
+	case "midnight":
+		return 0, true
+	}
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, false
+		}
+	}
+	if minute > 59 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func checkFuture(t, now time.Time, input string) (time.Time, error) {
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("time %q (%s) is not in the future", input, t.Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitParseFutureTime(t *testing.T) {
+	loc := time.FixedZone("UTC-7", -7*3600)
+	// Wednesday
+	now := time.Date(2025, 7, 16, 14, 30, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "unix timestamp", input: "1752710400", want: time.Unix(1752710400, 0)},
+		{name: "rfc3339", input: "2025-07-20T10:00:00Z", want: time.Date(2025, 7, 20, 10, 0, 0, 0, time.UTC)},
+		{name: "date and clock in local zone", input: "2025-07-20 10:15", want: time.Date(2025, 7, 20, 10, 15, 0, 0, loc)},
+		{name: "in compact duration", input: "in 2h", want: now.Add(2 * time.Hour)},
+		{name: "in mixed compact duration", input: "in 1h30m", want: now.Add(90 * time.Minute)},
+		{name: "in days compact", input: "in 2d", want: now.Add(48 * time.Hour)},
+		{name: "in words", input: "in 2 hours", want: now.Add(2 * time.Hour)},
+		{name: "in multiple units", input: "in 1 day and 3 hours", want: now.Add(27 * time.Hour)},
+		{name: "in a week", input: "in a week", want: now.Add(7 * 24 * time.Hour)},
+		{name: "in an hour", input: "In An Hour", want: now.Add(time.Hour)},
+		{name: "tomorrow 9am", input: "tomorrow 9am", want: time.Date(2025, 7, 17, 9, 0, 0, 0, loc)},
+		{name: "tomorrow at 9:30 pm", input: "tomorrow at 9:30 pm", want: time.Date(2025, 7, 17, 21, 30, 0, 0, loc)},
+		{name: "tomorrow without time defaults to 9am", input: "tomorrow", want: time.Date(2025, 7, 17, 9, 0, 0, 0, loc)},
+		{name: "today 24h clock", input: "today 17:00", want: time.Date(2025, 7, 16, 17, 0, 0, 0, loc)},
+		{name: "bare time later today", input: "4pm", want: time.Date(2025, 7, 16, 16, 0, 0, 0, loc)},
+		{name: "bare time already passed rolls over", input: "9am", want: time.Date(2025, 7, 17, 9, 0, 0, 0, loc)},
+		{name: "noon", input: "tomorrow noon", want: time.Date(2025, 7, 17, 12, 0, 0, 0, loc)},
+		{name: "weekday", input: "friday 2pm", want: time.Date(2025, 7, 18, 14, 0, 0, 0, loc)},
+		{name: "same weekday means next week", input: "wednesday 10am", want: time.Date(2025, 7, 23, 10, 0, 0, 0, loc)},
+		{name: "next weekday abbreviation", input: "next mon 8:15am", want: time.Date(2025, 7, 21, 8, 15, 0, 0, loc)},
+		{name: "flexible date with time", input: "July 20 2025 9:30am", want: time.Date(2025, 7, 20, 9, 30, 0, 0, loc)},
+		{name: "flexible date without time", input: "2025-08-01", want: time.Date(2025, 8, 1, 9, 0, 0, 0, loc)},
+
+		{name: "empty", input: "", wantErr: true},
+		{name: "past absolute", input: "2025-07-01 10:00", wantErr: true},
+		{name: "today time already passed", input: "today 9am", wantErr: true},
+		{name: "zero offset", input: "in 0m", wantErr: true},
+		{name: "unknown unit", input: "in 3 fortnights", wantErr: true},
+		{name: "invalid clock", input: "tomorrow 13pm", wantErr: true},
+		{name: "gibberish", input: "whenever", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFutureTime(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFutureTime(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFutureTime(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseFutureTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitParseFutureTimeOrDuration(t *testing.T) {
+	now := time.Date(2025, 7, 16, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "bare compact duration", input: "2h", want: now.Add(2 * time.Hour)},
+		{name: "bare words", input: "90 minutes", want: now.Add(90 * time.Minute)},
+		{name: "a day", input: "a day", want: now.Add(24 * time.Hour)},
+		{name: "in prefix still works", input: "in 30m", want: now.Add(30 * time.Minute)},
+		{name: "unix timestamp is not a duration", input: "1752710400", want: time.Unix(1752710400, 0)},
+		{name: "day and time", input: "tomorrow 9am", want: time.Date(2025, 7, 17, 9, 0, 0, 0, time.UTC)},
+
+		{name: "negative duration", input: "-2h", wantErr: true},
+		{name: "gibberish", input: "soonish", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFutureTimeOrDuration(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFutureTimeOrDuration(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFutureTimeOrDuration(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseFutureTimeOrDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -677,6 +677,16 @@ func (uh *UsersHandler) GetUserInfoHandler(ctx context.Context, request mcp.Call
 		}
 	}
 
+	// Handle email resolution, through the cache store's email index
+	if strings.Contains(userID, "@") {
+		user, ok := uh.apiProvider.UserByEmail(userID)
+		if !ok {
+			uh.logger.Error("User not found", zap.String("email", userID))
+			return mcp.NewToolResultError(fmt.Sprintf("no user with email %s found", userID)), nil
+		}
+		userID = user.ID
+	}
+
 	// Get fields parameter
 	fieldsParam := request.GetString("fields", "id,name,real_name,display_name,email,title,status_text,is_admin,is_bot")
 
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWaitTimeout = 5 * time.Minute
+	maxWaitTimeout     = 30 * time.Minute
+
+	// Without events every check is a Slack call, made at most this often
+	waitPollInterval = 10 * time.Second
+	// With events, Slack is still checked now and then in case an event is lost
+	waitEventsCheckInterval = time.Minute
+
+	waitProgressInterval = 10 * time.Second
+)
+
+// waitTarget is the conversation wait_for_reply watches and the messages that count as a reply
+type waitTarget struct {
+	channel  string
+	threadTs string              // empty to wait for a message in the channel itself
+	users    map[string]struct{} // empty for anyone
+	oldest   string              // only messages posted after this ts count
+}
+
+// WaitForReplyHandler blocks until a message matching the target is posted, then returns it in the CSV
+// shape of get_thread_messages. It wakes up on events when Socket Mode or the Events API deliver them
+// and polls Slack otherwise; every Slack call waits on the provider's rate limiter.
+func (ch *ConversationsHandler) WaitForReplyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("WaitForReplyHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolConversations(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse wait_for_reply params", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to parse conversation parameters", err), nil
+	}
+	timeout, err := parseWaitTimeout(request.GetString("timeout", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid timeout", err), nil
+	}
+
+	target := waitTarget{
+		channel:  params.channel,
+		threadTs: request.GetString("thread_ts", ""),
+		users:    make(map[string]struct{}),
+		oldest:   request.GetString("oldest", ""),
+	}
+	if target.oldest == "" {
+		now := time.Now()
+		target.oldest = fmt.Sprintf("%d.%06d", now.Unix(), now.Nanosecond()/1000)
+	}
+	usersMap := ch.apiProvider.ProvideUsersMap()
+	for _, entry := range parseCommaSeparatedList(request.GetString("users", "")) {
+		id, err := resolveUserID(entry, usersMap.Users, usersMap.UsersInv)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to resolve user", err), nil
+		}
+		target.users[id] = struct{}{}
+	}
+
+	fields := request.GetString("fields", "msgID,userUser,realName,text,time")
+	requestedFields := parseMessageFields(fields)
+
+	started := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	progress := ch.progressReporter(ctx, request)
+	progressTicker := time.NewTicker(waitProgressInterval)
+	defer progressTicker.Stop()
+
+	events := ch.apiProvider.Events()
+	var (
+		added <-chan struct{}
+		seq   uint64
+	)
+	if events != nil {
+		added = events.Added()
+		seq = events.LastSeq()
+	}
+
+	for {
+		// The first check finds replies posted between the question and this call
+		replies, err := ch.fetchReplies(waitCtx, target)
+		if err != nil && waitCtx.Err() == nil {
+			ch.logger.Error("Failed to check for replies", zap.String("channel", target.channel), zap.Error(err))
+			return mcp.NewToolResultErrorFromErr("Failed to fetch replies", err), nil
+		}
+		if len(replies) > 0 {
+			messages := ch.convertMessagesFromHistoryWithFields(replies, target.channel, false, requestedFields)
+			csvBytes, err := marshalMessagesWithFields(messages, requestedFields, false)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Failed to format messages as CSV", err), nil
+			}
+			return mcp.NewToolResultText(string(csvBytes)), nil
+		}
+
+		interval := waitPollInterval
+		if events != nil && events.Live() {
+			interval = waitEventsCheckInterval
+		}
+		check := time.NewTimer(interval)
+
+	wait:
+		for {
+			select {
+			case <-waitCtx.Done():
+				check.Stop()
+				if ctx.Err() != nil {
+					return mcp.NewToolResultError("wait_for_reply was cancelled"), nil
+				}
+				csvBytes, err := marshalMessagesWithFields(nil, requestedFields, false)
+				if err != nil {
+					return mcp.NewToolResultErrorFromErr("Failed to format messages as CSV", err), nil
+				}
+				return mcp.NewToolResultText(fmt.Sprintf("# No reply within %s\n%s", timeout, csvBytes)), nil
+			case <-progressTicker.C:
+				progress(time.Since(started), timeout)
+			case <-added:
+				added = events.Added()
+				matched := false
+				for _, event := range events.Since(seq) {
+					seq = event.Seq
+					matched = matched || target.matchesEvent(event)
+				}
+				if matched {
+					break wait
+				}
+			case <-check.C:
+				break wait
+			}
+		}
+		check.Stop()
+	}
+}
+
+// fetchReplies returns the messages of the target posted after target.oldest, oldest first
+func (ch *ConversationsHandler) fetchReplies(ctx context.Context, target waitTarget) ([]slack.Message, error) {
+	if err := ch.apiProvider.WaitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	var messages []slack.Message
+	if target.threadTs != "" {
+		replies, _, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+			ChannelID: target.channel,
+			Timestamp: target.threadTs,
+			Oldest:    target.oldest,
+			Limit:     100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = replies
+	} else {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: target.channel,
+			Oldest:    target.oldest,
+			Limit:     100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = history.Messages
+	}
+	return target.filter(messages, ch.apiProvider), nil
+}
+
+// filter keeps the replies among messages, sorted oldest first. conversations.replies always includes
+// the parent message, which is never a reply.
+func (t waitTarget) filter(messages []slack.Message, apiProvider *provider.ApiProvider) []slack.Message {
+	var replies []slack.Message
+	for _, msg := range messages {
+		if compareTs(msg.Timestamp, t.oldest) <= 0 || (t.threadTs != "" && msg.Timestamp == t.threadTs) {
+			continue
+		}
+		if len(t.users) > 0 {
+			user := msg.User
+			if user == "" && msg.BotID != "" && apiProvider != nil {
+				if botUser, ok := apiProvider.ResolveBotIDToUser(msg.BotID); ok {
+					user = botUser.ID
+				}
+			}
+			if _, ok := t.users[user]; !ok {
+				continue
+			}
+		}
+		replies = append(replies, msg)
+	}
+	sort.Slice(replies, func(i, j int) bool { return compareTs(replies[i].Timestamp, replies[j].Timestamp) < 0 })
+	return replies
+}
+
+// matchesEvent reports whether an event may be a reply. Events don't resolve bot users, so a bot reply
+// is found by the next check instead.
+func (t waitTarget) matchesEvent(event provider.Event) bool {
+	if event.Type != "message" || event.Channel != t.channel || event.Subtype == "message_changed" || event.Subtype == "message_deleted" {
+		return false
+	}
+	if compareTs(event.Ts, t.oldest) <= 0 {
+		return false
+	}
+	if t.threadTs != "" {
+		if event.ThreadTs != t.threadTs {
+			return false
+		}
+	} else if event.ThreadTs != "" && event.ThreadTs != event.Ts && event.Subtype != "thread_broadcast" {
+		return false
+	}
+	if len(t.users) > 0 {
+		if _, ok := t.users[event.User]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// progressReporter returns a function sending notifications/progress for the request, or doing nothing
+// when the client did not ask for progress
+func (ch *ConversationsHandler) progressReporter(ctx context.Context, request mcp.CallToolRequest) func(elapsed, total time.Duration) {
+	var token mcp.ProgressToken
+	if request.Params.Meta != nil {
+		token = request.Params.Meta.ProgressToken
+	}
+	mcpServer := server.ServerFromContext(ctx)
+	if token == nil || mcpServer == nil {
+		return func(time.Duration, time.Duration) {}
+	}
+
+	return func(elapsed, total time.Duration) {
+		err := mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      elapsed.Seconds(),
+			"total":         total.Seconds(),
+			"message":       fmt.Sprintf("Waiting for a reply (%s of %s)", elapsed.Round(time.Second), total),
+		})
+		if err != nil {
+			ch.logger.Debug("Failed to send progress notification", zap.Error(err))
+		}
+	}
+}
+
+// parseWaitTimeout accepts durations such as "90s" or "5m", or a number of seconds. Empty means the
+// default of 5 minutes; at most 30 minutes can be waited.
+func parseWaitTimeout(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultWaitTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return 0, fmt.Errorf("timeout %q must be a duration such as 5m or a number of seconds", raw)
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d <= 0 {
+		return 0, errors.New("timeout must be positive")
+	}
+	if d > maxWaitTimeout {
+		return 0, fmt.Errorf("timeout must be at most %s", maxWaitTimeout)
+	}
+	return d, nil
+}
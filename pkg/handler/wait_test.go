@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseWaitTimeout(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"":    defaultWaitTimeout,
+		"90s": 90 * time.Second,
+		"5m":  5 * time.Minute,
+		"120": 2 * time.Minute,
+		"30m": 30 * time.Minute,
+	} {
+		got, err := parseWaitTimeout(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	for _, raw := range []string{"soon", "0", "-5m", "31m"} {
+		_, err := parseWaitTimeout(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestUnitWaitTargetMatchesEvent(t *testing.T) {
+	thread := waitTarget{channel: "C01AAAAAAAA", threadTs: "1700000000.000100", oldest: "1700000005.000100", users: map[string]struct{}{"U02BBBBBBBB": {}}}
+	channel := waitTarget{channel: "C01AAAAAAAA", oldest: "1700000005.000100"}
+
+	reply := provider.Event{Type: "message", Channel: "C01AAAAAAAA", User: "U02BBBBBBBB", Ts: "1700000010.000100", ThreadTs: "1700000000.000100"}
+	assert.True(t, thread.matchesEvent(reply))
+	assert.False(t, channel.matchesEvent(reply), "a thread reply is not a channel message")
+
+	topLevel := provider.Event{Type: "message", Channel: "C01AAAAAAAA", User: "U03CCCCCCCC", Ts: "1700000010.000200"}
+	assert.True(t, channel.matchesEvent(topLevel))
+	assert.False(t, thread.matchesEvent(topLevel))
+
+	other := reply
+	other.User = "U03CCCCCCCC"
+	assert.False(t, thread.matchesEvent(other), "not one of the users waited on")
+
+	early := reply
+	early.Ts = "1700000001.000100"
+	assert.False(t, thread.matchesEvent(early), "posted before oldest")
+
+	edit := reply
+	edit.Subtype = "message_changed"
+	assert.False(t, thread.matchesEvent(edit))
+
+	reaction := reply
+	reaction.Type = "reaction_added"
+	assert.False(t, thread.matchesEvent(reaction))
+
+	broadcast := reply
+	broadcast.Subtype = "thread_broadcast"
+	assert.True(t, channel.matchesEvent(broadcast))
+}
+
+func TestUnitWaitTargetFilter(t *testing.T) {
+	target := waitTarget{channel: "C01AAAAAAAA", threadTs: "1700000000.000100", oldest: "1700000005.000100", users: map[string]struct{}{}}
+	messages := []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1700000000.000100", User: "U01AAAAAAAA", Text: "Can someone approve the deploy?"}},
+		{Msg: slack.Msg{Timestamp: "1700000003.000100", User: "U02BBBBBBBB", Text: "looking"}},
+		{Msg: slack.Msg{Timestamp: "1700000020.000100", User: "U03CCCCCCCC", Text: "approved too"}},
+		{Msg: slack.Msg{Timestamp: "1700000010.000100", User: "U02BBBBBBBB", Text: "approved"}},
+	}
+
+	replies := target.filter(messages, nil)
+	require.Len(t, replies, 2)
+	assert.Equal(t, "approved", replies[0].Text, "oldest first")
+	assert.Equal(t, "approved too", replies[1].Text)
+
+	target.users["U03CCCCCCCC"] = struct{}{}
+	replies = target.filter(messages, nil)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "U03CCCCCCCC", replies[0].User)
+}
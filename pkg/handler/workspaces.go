@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const WorkspacesResourceURI = "slack://workspaces"
+
+// Workspace is a Slack workspace the server is signed in to. Tools take its name, team ID or domain in
+// their workspace argument.
+type Workspace struct {
+	Name         string `csv:"name"`
+	TeamID       string `csv:"team_id"`
+	TeamName     string `csv:"team_name"`
+	WorkspaceURL string `csv:"workspace_url"`
+	UserName     string `csv:"user_name"`
+	EnterpriseID string `csv:"enterprise_id"`
+	Default      bool   `csv:"default"`
+}
+
+type WorkspacesHandler struct {
+	apiProvider *provider.ApiProvider
+	workspaces  func() []Workspace
+	logger      *zap.Logger
+}
+
+// NewWorkspacesHandler lists the workspaces returned by workspaces; apiProvider is the default one
+func NewWorkspacesHandler(apiProvider *provider.ApiProvider, workspaces func() []Workspace, logger *zap.Logger) *WorkspacesHandler {
+	return &WorkspacesHandler{
+		apiProvider: apiProvider,
+		workspaces:  workspaces,
+		logger:      logger,
+	}
+}
+
+func (wh *WorkspacesHandler) ListWorkspacesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	wh.logger.Debug("ListWorkspacesHandler called")
+
+	workspaces := wh.workspaces()
+	csvBytes, err := gocsv.MarshalBytes(&workspaces)
+	if err != nil {
+		wh.logger.Error("Failed to marshal workspaces to CSV", zap.Error(err))
+		return mcp.NewToolResultErrorFromErr("Failed to format workspaces as CSV", err), nil
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+func (wh *WorkspacesHandler) WorkspacesResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	wh.logger.Debug("WorkspacesResource called", zap.Any("params", request.Params))
+
+	// mark3labs/mcp-go does not support middlewares for resources.
+	if authenticated, err := auth.IsAuthenticated(ctx, wh.apiProvider.ServerTransport(), wh.logger); !authenticated {
+		wh.logger.Error("Authentication failed for workspaces resource", zap.Error(err))
+		return nil, err
+	}
+
+	workspaces := wh.workspaces()
+	csvBytes, err := gocsv.MarshalBytes(&workspaces)
+	if err != nil {
+		wh.logger.Error("Failed to marshal workspaces to CSV", zap.Error(err))
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      WorkspacesResourceURI,
+			MIMEType: "text/csv",
+			Text:     string(csvBytes),
+		},
+	}, nil
+}
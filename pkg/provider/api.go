@@ -2,11 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -30,6 +28,7 @@ const channelsNotReadyMsg = "channels cache is not ready yet, sync process is st
 const emojisNotReadyMsg = "emojis cache is not ready yet, sync process is still running... please wait"
 const defaultUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36"
 const defaultCacheTTL = 1 * time.Hour
+const defaultCacheReconcileInterval = 24 * time.Hour
 const defaultMinRefreshInterval = 30 * time.Second
 
 var AllChanTypes = []string{"mpim", "im", "public_channel", "private_channel"}
@@ -85,6 +84,33 @@ func getCacheTTL() time.Duration {
 	return defaultCacheTTL
 }
 
+// getCacheReconcileInterval returns how often a refresh re-downloads the whole users and channels lists
+// instead of merging changes, from SLACK_MCP_CACHE_RECONCILE_INTERVAL or default (24 hours).
+// Supports formats: "24h", "86400" (seconds), "0" (always re-download)
+// Negative values are rejected and fall back to default.
+func getCacheReconcileInterval() time.Duration {
+	intervalStr := os.Getenv("SLACK_MCP_CACHE_RECONCILE_INTERVAL")
+	if intervalStr == "" {
+		return defaultCacheReconcileInterval
+	}
+
+	if d, err := time.ParseDuration(intervalStr); err == nil {
+		if d < 0 {
+			return defaultCacheReconcileInterval
+		}
+		return d
+	}
+
+	if secs, err := strconv.ParseInt(intervalStr, 10, 64); err == nil {
+		if secs < 0 {
+			return defaultCacheReconcileInterval
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	return defaultCacheReconcileInterval
+}
+
 // getMinRefreshInterval returns the minimum interval between forced refreshes from
 // SLACK_MCP_MIN_REFRESH_INTERVAL env var or default (30s).
 // Supports formats: "30s", "1m", "60" (seconds), "0" (disable rate limiting)
@@ -221,6 +247,7 @@ type SlackAPI interface {
 	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error)
 	GetUsersInfo(users ...string) (*[]slack.User, error)
 	PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error)
+	PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error)
 	MarkConversationContext(ctx context.Context, channel, ts string) error
 	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
 	RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error
@@ -229,6 +256,7 @@ type SlackAPI interface {
 	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
 	GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, nextCursor string, err error)
 	SearchContext(ctx context.Context, query string, params slack.SearchParameters) (*slack.SearchMessages, *slack.SearchFiles, error)
+	GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error)
 
 	// Used to get file information
 	GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error)
@@ -262,6 +290,19 @@ type SlackAPI interface {
 	DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error)
 	UpdateMessageContext(ctx context.Context, channel, timestamp string, options ...slack.MsgOption) (string, string, string, error)
 
+	// Scheduled messages
+	ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error)
+	GetScheduledMessagesContext(ctx context.Context, params *slack.GetScheduledMessagesParameters) ([]slack.ScheduledMessage, string, error)
+	DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error)
+
+	// Pins and bookmarks
+	AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error
+	RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error
+	ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error)
+	AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error)
+	ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error)
+	RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error
+
 	// Channel members
 	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error)
 
@@ -269,6 +310,22 @@ type SlackAPI interface {
 	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
 	GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error)
 
+	// Status, presence and Do Not Disturb of the authenticated user
+	SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error
+	UnsetUserCustomStatusContext(ctx context.Context) error
+	SetUserPresenceContext(ctx context.Context, presence string) error
+	SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error)
+	EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error)
+	EndDNDContext(ctx context.Context) error
+	GetDNDInfoContext(ctx context.Context, user *string) (*slack.DNDStatus, error)
+	GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error)
+
+	// Reminders
+	AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error)
+	ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error)
+	CompleteReminderContext(ctx context.Context, id string) error
+	DeleteReminderContext(ctx context.Context, id string) error
+
 	// Bot information
 	GetBotInfoContext(ctx context.Context, parameters slack.GetBotInfoParameters) (*slack.Bot, error)
 
@@ -277,6 +334,14 @@ type SlackAPI interface {
 	ArchiveConversationContext(ctx context.Context, channelID string) error
 	SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error)
 	SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error)
+	RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error)
+	UnArchiveConversationContext(ctx context.Context, channelID string) error
+
+	// Channel membership
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
+	KickUserFromConversationContext(ctx context.Context, channelID string, user string) error
+	JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error)
+	LeaveConversationContext(ctx context.Context, channelID string) (bool, error)
 
 	// User groups API methods
 	GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) ([]slack.UserGroup, error)
@@ -297,7 +362,7 @@ type MCPSlackClient struct {
 	isEnterprise   bool
 	isOAuth        bool
 	isBotToken     bool
-	edgeFailed     bool     // set when edge API fails; subsequent calls skip straight to standard API
+	edgeFailed     bool // set when edge API fails; subsequent calls skip straight to standard API
 	teamEndpoint   string
 	workspaceTeams []string // Team IDs (e.g. T08U80K08H4) for workspaces the user belongs to (from enterprise_user.teams)
 }
@@ -309,29 +374,35 @@ type ApiProvider struct {
 
 	rateLimiter        *rate.Limiter
 	cacheTTL           time.Duration
+	reconcileInterval  time.Duration
 	minRefreshInterval time.Duration
+	store              CacheStore
+	archive            *MessageArchive // nil unless SLACK_MCP_MESSAGE_ARCHIVE or SLACK_MCP_SYNC_CHANNELS is set
+	syncer             *Syncer         // nil unless SLACK_MCP_SYNC_CHANNELS is set
 
 	// Users cache: atomic pointer to immutable snapshot (no copy on read)
 	usersSnapshot          atomic.Pointer[UsersCache]
-	usersCachePath         string
 	usersReady             bool
 	lastForcedUsersRefresh time.Time
 	usersMu                sync.RWMutex // protects usersReady, lastForcedUsersRefresh
 
 	// Channels cache: atomic pointer to immutable snapshot (no copy on read)
 	channelsSnapshot          atomic.Pointer[ChannelsCache]
-	channelsCachePath         string
 	channelsReady             bool
 	lastForcedChannelsRefresh time.Time
 	channelsMu                sync.RWMutex // protects channelsReady, lastForcedChannelsRefresh
 
 	emojis      map[string]Emoji
-	emojisCache string
 	emojisReady bool
 
 	// Bot resolution: bot_id -> user mapping
 	botIDToUser map[string]slack.User // B091T8Q8ETT -> User{ID: "U091T8Q8Q8Z", Name: "linear"}
 	appIDToUser map[string]slack.User // AEMQ3Q4F4 -> User{ID: "U091T8Q8Q8Z", Name: "linear"}
+
+	// Events received over Socket Mode or the Events API, most recent last
+	events           *EventBuffer
+	eventListeners   []func(Event)
+	eventListenersMu sync.RWMutex
 }
 
 func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlackClient, error) {
@@ -610,6 +681,10 @@ func (c *MCPSlackClient) PostMessageContext(ctx context.Context, channelID strin
 	return c.slackClient.PostMessageContext(ctx, channelID, options...)
 }
 
+func (c *MCPSlackClient) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error) {
+	return c.slackClient.PostEphemeralContext(ctx, channelID, userID, options...)
+}
+
 func (c *MCPSlackClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
 	// Route by token type
 	if c.isOAuth {
@@ -636,6 +711,51 @@ func (c *MCPSlackClient) UpdateMessageContext(ctx context.Context, channel, time
 	return c.slackClient.UpdateMessageContext(ctx, channel, timestamp, options...)
 }
 
+func (c *MCPSlackClient) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	// chat.scheduleMessage is only available via standard API
+	return c.slackClient.ScheduleMessageContext(ctx, channelID, postAt, options...)
+}
+
+func (c *MCPSlackClient) GetScheduledMessagesContext(ctx context.Context, params *slack.GetScheduledMessagesParameters) ([]slack.ScheduledMessage, string, error) {
+	// chat.scheduledMessages.list is only available via standard API
+	return c.slackClient.GetScheduledMessagesContext(ctx, params)
+}
+
+func (c *MCPSlackClient) DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error) {
+	// chat.deleteScheduledMessage is only available via standard API
+	return c.slackClient.DeleteScheduledMessageContext(ctx, params)
+}
+
+func (c *MCPSlackClient) AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	// pins.add is only available via standard API
+	return c.slackClient.AddPinContext(ctx, channel, item)
+}
+
+func (c *MCPSlackClient) RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	// pins.remove is only available via standard API
+	return c.slackClient.RemovePinContext(ctx, channel, item)
+}
+
+func (c *MCPSlackClient) ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error) {
+	// pins.list is only available via standard API
+	return c.slackClient.ListPinsContext(ctx, channel)
+}
+
+func (c *MCPSlackClient) AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error) {
+	// bookmarks.add is only available via standard API
+	return c.slackClient.AddBookmarkContext(ctx, channelID, params)
+}
+
+func (c *MCPSlackClient) ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error) {
+	// bookmarks.list is only available via standard API
+	return c.slackClient.ListBookmarksContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error {
+	// bookmarks.remove is only available via standard API
+	return c.slackClient.RemoveBookmarkContext(ctx, channelID, bookmarkID)
+}
+
 func (c *MCPSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
 	// In Enterprise Grid with browser tokens, we need to use the Edge client
 	if c.isEnterprise && !c.isOAuth {
@@ -665,6 +785,11 @@ func (c *MCPSlackClient) GetConversationInfoContext(ctx context.Context, input *
 	return c.slackClient.GetConversationInfoContext(ctx, input)
 }
 
+func (c *MCPSlackClient) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	// chat.getPermalink is only available via standard API
+	return c.slackClient.GetPermalinkContext(ctx, params)
+}
+
 func (c *MCPSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	// In Enterprise Grid with browser tokens, we might need to use cached data
 	// For now, try the standard API first
@@ -676,6 +801,66 @@ func (c *MCPSlackClient) GetUserPresenceContext(ctx context.Context, user string
 	return c.slackClient.GetUserPresenceContext(ctx, user)
 }
 
+func (c *MCPSlackClient) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	// users.profile.set is only available via standard API
+	return c.slackClient.SetUserCustomStatusContext(ctx, statusText, statusEmoji, statusExpiration)
+}
+
+func (c *MCPSlackClient) UnsetUserCustomStatusContext(ctx context.Context) error {
+	// Clears status text, emoji and expiration in one users.profile.set call
+	return c.slackClient.UnsetUserCustomStatusContext(ctx)
+}
+
+func (c *MCPSlackClient) SetUserPresenceContext(ctx context.Context, presence string) error {
+	// users.setPresence is only available via standard API
+	return c.slackClient.SetUserPresenceContext(ctx, presence)
+}
+
+func (c *MCPSlackClient) SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error) {
+	// dnd.setSnooze is only available via standard API
+	return c.slackClient.SetSnoozeContext(ctx, minutes)
+}
+
+func (c *MCPSlackClient) EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error) {
+	// dnd.endSnooze is only available via standard API
+	return c.slackClient.EndSnoozeContext(ctx)
+}
+
+func (c *MCPSlackClient) EndDNDContext(ctx context.Context) error {
+	// dnd.endDnd ends the current scheduled Do Not Disturb session
+	return c.slackClient.EndDNDContext(ctx)
+}
+
+func (c *MCPSlackClient) GetDNDInfoContext(ctx context.Context, user *string) (*slack.DNDStatus, error) {
+	// dnd.info is only available via standard API
+	return c.slackClient.GetDNDInfoContext(ctx, user)
+}
+
+func (c *MCPSlackClient) GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error) {
+	// dnd.teamInfo is only available via standard API
+	return c.slackClient.GetDNDTeamInfoContext(ctx, users)
+}
+
+func (c *MCPSlackClient) AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error) {
+	// reminders.add is only available via standard API
+	return c.slackClient.AddUserReminderContext(ctx, userID, text, time)
+}
+
+func (c *MCPSlackClient) ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error) {
+	// reminders.list is only available via standard API
+	return c.slackClient.ListRemindersContext(ctx)
+}
+
+func (c *MCPSlackClient) CompleteReminderContext(ctx context.Context, id string) error {
+	// slack-go does not wrap reminders.complete, the edge client posts it for every token type
+	return c.edgeClient.CompleteReminderContext(ctx, id)
+}
+
+func (c *MCPSlackClient) DeleteReminderContext(ctx context.Context, id string) error {
+	// reminders.delete is only available via standard API
+	return c.slackClient.DeleteReminderContext(ctx, id)
+}
+
 func (c *MCPSlackClient) GetBotInfoContext(ctx context.Context, parameters slack.GetBotInfoParameters) (*slack.Bot, error) {
 	// bots.info is available via standard API
 	return c.slackClient.GetBotInfoContext(ctx, parameters)
@@ -744,6 +929,36 @@ func (c *MCPSlackClient) SetPurposeOfConversationContext(ctx context.Context, ch
 	return c.slackClient.SetPurposeOfConversationContext(ctx, channelID, purpose)
 }
 
+func (c *MCPSlackClient) RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error) {
+	// RenameConversation renames a channel
+	return c.slackClient.RenameConversationContext(ctx, channelID, channelName)
+}
+
+func (c *MCPSlackClient) UnArchiveConversationContext(ctx context.Context, channelID string) error {
+	// UnArchiveConversation restores an archived channel
+	return c.slackClient.UnArchiveConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	// InviteUsersToConversation adds users to a channel
+	return c.slackClient.InviteUsersToConversationContext(ctx, channelID, users...)
+}
+
+func (c *MCPSlackClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	// KickUserFromConversation removes a user from a channel
+	return c.slackClient.KickUserFromConversationContext(ctx, channelID, user)
+}
+
+func (c *MCPSlackClient) JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error) {
+	// JoinConversation joins the calling user to a channel
+	return c.slackClient.JoinConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	// LeaveConversation removes the calling user from a channel
+	return c.slackClient.LeaveConversationContext(ctx, channelID)
+}
+
 func (c *MCPSlackClient) ClientUserBoot(ctx context.Context) (*edge.ClientUserBootResponse, error) {
 	return c.edgeClient.ClientUserBoot(ctx)
 }
@@ -843,6 +1058,11 @@ func New(transport string, logger *zap.Logger) *ApiProvider {
 	xoxcToken := os.Getenv("SLACK_MCP_XOXC_TOKEN")
 	xoxdToken := os.Getenv("SLACK_MCP_XOXD_TOKEN")
 
+	// An offline archive replaces Slack entirely, tokens are not needed
+	if archivePath := os.Getenv("SLACK_MCP_OFFLINE_ARCHIVE"); archivePath != "" {
+		return newOffline(transport, archivePath, logger)
+	}
+
 	// Warn if both user and bot tokens are set
 	if xoxpToken != "" && xoxbToken != "" {
 		logger.Warn(
@@ -917,6 +1137,16 @@ func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		emojisCache = getCachePathWithTeamID(teamID, "emojis_cache.json")
 	}
 
+	cacheDB := os.Getenv("SLACK_MCP_CACHE_DB")
+	if cacheDB == "" {
+		cacheDB = getCachePathWithTeamID(teamID, "cache.db")
+	}
+
+	archiveDB := os.Getenv("SLACK_MCP_MESSAGE_ARCHIVE_DB")
+	if archiveDB == "" {
+		archiveDB = getCachePathWithTeamID(teamID, "messages.db")
+	}
+
 	if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
 		logger.Info("Demo credentials are set, skip.")
 	} else {
@@ -926,34 +1156,10 @@ func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		}
 	}
 
-	ap := &ApiProvider{
-		transport: transport,
-		client:    client,
-		logger:    logger,
-
-		rateLimiter:        limiter.Tier2.Limiter(),
-		cacheTTL:           getCacheTTL(),
-		minRefreshInterval: getMinRefreshInterval(),
-
-		usersCachePath:    usersCache,
-		channelsCachePath: channelsCache,
-
-		emojis:      make(map[string]Emoji),
-		emojisCache: emojisCache,
-
-		botIDToUser: make(map[string]slack.User),
-		appIDToUser: make(map[string]slack.User),
-	}
-	// Initialize with empty snapshots
-	ap.usersSnapshot.Store(&UsersCache{
-		Users:    make(map[string]slack.User),
-		UsersInv: make(map[string]string),
-	})
-	ap.channelsSnapshot.Store(&ChannelsCache{
-		Channels:    make(map[string]Channel),
-		ChannelsInv: make(map[string]string),
-	})
-	return ap
+	return newApiProvider(transport, client, logger,
+		openCacheStore(cacheDB, usersCache, channelsCache, emojisCache, logger),
+		openMessageArchive(archiveDB, logger),
+	)
 }
 
 func newWithXOXB(transport string, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
@@ -988,6 +1194,16 @@ func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		emojisCache = getCachePathWithTeamID(teamID, "emojis_cache.json")
 	}
 
+	cacheDB := os.Getenv("SLACK_MCP_CACHE_DB")
+	if cacheDB == "" {
+		cacheDB = getCachePathWithTeamID(teamID, "cache.db")
+	}
+
+	archiveDB := os.Getenv("SLACK_MCP_MESSAGE_ARCHIVE_DB")
+	if archiveDB == "" {
+		archiveDB = getCachePathWithTeamID(teamID, "messages.db")
+	}
+
 	if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
 		logger.Info("Demo credentials are set, skip.")
 	} else {
@@ -997,6 +1213,61 @@ func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		}
 	}
 
+	return newApiProvider(transport, client, logger,
+		openCacheStore(cacheDB, usersCache, channelsCache, emojisCache, logger),
+		openMessageArchive(archiveDB, logger),
+	)
+}
+
+// newOffline serves the tools from the Slack export or slackdump archive at path. Nothing is cached or
+// archived: the archive already holds everything locally.
+func newOffline(transport, path string, logger *zap.Logger) *ApiProvider {
+	client, err := OpenOfflineClient(context.Background(), path)
+	if err != nil {
+		logger.Fatal("Failed to open offline archive", zap.String("context", "console"), zap.Error(err))
+	}
+	auth, _ := client.AuthTest()
+	logger.Info("Serving from an offline archive",
+		zap.String("context", "console"),
+		zap.String("archive", path),
+		zap.String("team", auth.Team),
+		zap.Int("channels", len(client.channels)),
+		zap.Int("users", len(client.users)),
+	)
+	return newApiProvider(transport, client, logger, nil, nil)
+}
+
+// NewForTenant creates a provider for one client of a multi-tenant server, from the Slack token (and the
+// d cookie of xoxc tokens) the client sent. Unlike New it returns errors instead of exiting. Caches live
+// in a directory per team and user, since the channels cache lists the user's private conversations. The
+// auth.test response the client was authenticated with is returned along with the provider.
+func NewForTenant(transport, token, cookie string, logger *zap.Logger) (*ApiProvider, *slack.AuthTestResponse, error) {
+	authProvider, err := auth.NewValueAuth(token, cookie)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := NewMCPSlackClient(authProvider, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	authResp := client.AuthResponse()
+
+	dir := filepath.Join(getCacheDir(), "tenants", authResp.TeamID, authResp.UserID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return newApiProvider(transport, client, logger, openCacheStore(
+		filepath.Join(dir, "cache.db"),
+		filepath.Join(dir, "users_cache.json"),
+		filepath.Join(dir, "channels_cache_v2.json"),
+		filepath.Join(dir, "emojis_cache.json"),
+		logger,
+	), openMessageArchive(filepath.Join(dir, "messages.db"), logger)), authResp, nil
+}
+
+// newApiProvider builds a provider around a connected or offline client, with empty caches backed by store. archive
+// may be nil.
+func newApiProvider(transport string, client SlackAPI, logger *zap.Logger, store CacheStore, archive *MessageArchive) *ApiProvider {
 	ap := &ApiProvider{
 		transport: transport,
 		client:    client,
@@ -1004,16 +1275,21 @@ func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 
 		rateLimiter:        limiter.Tier2.Limiter(),
 		cacheTTL:           getCacheTTL(),
+		reconcileInterval:  getCacheReconcileInterval(),
 		minRefreshInterval: getMinRefreshInterval(),
 
-		usersCachePath:    usersCache,
-		channelsCachePath: channelsCache,
+		store:   store,
+		archive: archive,
 
-		emojis:      make(map[string]Emoji),
-		emojisCache: emojisCache,
+		emojis: make(map[string]Emoji),
 
 		botIDToUser: make(map[string]slack.User),
 		appIDToUser: make(map[string]slack.User),
+
+		events: NewEventBuffer(getEventBufferSize()),
+	}
+	if patterns, _ := SyncPatterns(); archive != nil && len(patterns) > 0 {
+		ap.syncer = newSyncer(ap, archive, patterns)
 	}
 	// Initialize with empty snapshots
 	ap.usersSnapshot.Store(&UsersCache{
@@ -1055,6 +1331,10 @@ func (ap *ApiProvider) ForceRefreshUsers(ctx context.Context) error {
 	return ap.refreshUsersInternal(ctx, true)
 }
 
+// refreshUsersInternal serves the users cache from the store while it is within the TTL. Past it, the
+// cached users stay in use while a delta refresh merges the changes, or a full refresh replaces them once
+// the last one is older than SLACK_MCP_CACHE_RECONCILE_INTERVAL. force skips the TTL and takes the delta
+// path whenever there are users to merge into.
 func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) error {
 	ap.usersMu.Lock()
 	defer ap.usersMu.Unlock()
@@ -1062,56 +1342,57 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 	var (
 		list        []slack.User
 		optionLimit = slack.GetUsersOptionLimit(1000)
+		state       CacheState
 	)
 
-	// Check if we should use cache (not forced, cache exists, and within TTL)
-	if !force {
-		if data, err := os.ReadFile(ap.usersCachePath); err == nil {
-			var cachedUsers []slack.User
-			if err := json.Unmarshal(data, &cachedUsers); err != nil {
-				ap.logger.Warn("Failed to unmarshal users cache, will refetch",
-					zap.String("cache_file", ap.usersCachePath),
-					zap.Error(err))
-			} else if len(cachedUsers) == 0 {
-				ap.logger.Warn("Users cache is empty or null, will refetch",
-					zap.String("cache_file", ap.usersCachePath))
-			} else {
-				// Check cache TTL using file modification time
-				cacheValid := true
-				if ap.cacheTTL > 0 {
-					if fileInfo, err := os.Stat(ap.usersCachePath); err == nil {
-						cacheAge := time.Since(fileInfo.ModTime())
-						if cacheAge > ap.cacheTTL {
-							ap.logger.Info("Users cache expired, will refetch",
-								zap.Duration("cache_age", cacheAge),
-								zap.Duration("ttl", ap.cacheTTL),
-								zap.String("cache_file", ap.usersCachePath))
-							cacheValid = false
-						}
-					}
-				}
+	if ap.store != nil {
+		var (
+			cachedUsers []slack.User
+			cachedState CacheState
+			err         error
+		)
+		current := ap.usersSnapshot.Load()
+		inMemory := current != nil && len(current.Users) > 0
+		if inMemory {
+			// The cached users were loaded already, only their age is needed
+			cachedState, err = ap.store.UsersState()
+		} else {
+			cachedUsers, cachedState, err = ap.store.Users()
+		}
+		if err != nil {
+			ap.logger.Warn("Failed to read users cache, will refetch",
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		} else if !inMemory && len(cachedUsers) == 0 {
+			ap.logger.Warn("Users cache is empty or null, will refetch",
+				zap.String("cache_file", ap.store.Location()))
+		} else {
+			state = cachedState
+			if !inMemory {
+				ap.mergeUsers(cachedUsers)
+				ap.logger.Info("Loaded users from cache",
+					zap.Int("count", len(cachedUsers)),
+					zap.String("cache_file", ap.store.Location()))
+				ap.usersReady = true
+			}
 
-				if cacheValid {
-					// Build new snapshot from cache
-					newSnapshot := &UsersCache{
-						Users:    make(map[string]slack.User, len(cachedUsers)),
-						UsersInv: make(map[string]string, len(cachedUsers)),
-					}
-					for _, u := range cachedUsers {
-						newSnapshot.Users[u.ID] = u
-						newSnapshot.UsersInv[u.Name] = u.ID
-					}
-					ap.usersSnapshot.Store(newSnapshot)
-					ap.logger.Info("Loaded users from cache",
-						zap.Int("count", len(cachedUsers)),
-						zap.String("cache_file", ap.usersCachePath))
-					ap.usersReady = true
-					return nil
-				}
+			cacheAge := time.Since(state.Refreshed)
+			if !force && (ap.cacheTTL == 0 || cacheAge <= ap.cacheTTL) {
+				return nil
+			}
+			if !force {
+				ap.logger.Info("Users cache expired, refreshing",
+					zap.Duration("cache_age", cacheAge),
+					zap.Duration("ttl", ap.cacheTTL),
+					zap.String("cache_file", ap.store.Location()))
 			}
 		}
 	}
 
+	if current := ap.usersSnapshot.Load(); current != nil && len(current.Users) > 0 && (force || !ap.reconcileDue(state)) {
+		return ap.deltaRefreshUsers(ctx)
+	}
+
 	// Fetch fresh data from Slack API
 	users, err := ap.client.GetUsersContext(ctx,
 		optionLimit,
@@ -1160,17 +1441,15 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 		ap.usersSnapshot.Store(finalSnapshot)
 	}
 
-	if data, err := json.MarshalIndent(list, "", "  "); err != nil {
-		ap.logger.Error("Failed to marshal users for cache", zap.Error(err))
-	} else {
-		if err := os.WriteFile(ap.usersCachePath, data, 0644); err != nil {
-			ap.logger.Error("Failed to write cache file",
-				zap.String("cache_file", ap.usersCachePath),
+	if ap.store != nil {
+		if err := ap.store.ReplaceUsers(list); err != nil {
+			ap.logger.Error("Failed to write users cache",
+				zap.String("cache_file", ap.store.Location()),
 				zap.Error(err))
 		} else {
 			ap.logger.Info("Wrote users to cache",
 				zap.Int("count", len(list)),
-				zap.String("cache_file", ap.usersCachePath))
+				zap.String("cache_file", ap.store.Location()))
 		}
 	}
 
@@ -1183,27 +1462,26 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 
 func (ap *ApiProvider) RefreshEmojis(ctx context.Context) error {
 	// Try loading from cache first
-	if data, err := ioutil.ReadFile(ap.emojisCache); err == nil {
-		var cachedEmojis []Emoji
-		if err := json.Unmarshal(data, &cachedEmojis); err != nil {
-			ap.logger.Warn("Failed to unmarshal emojis cache, will refetch",
-				zap.String("cache_file", ap.emojisCache),
+	if ap.store != nil {
+		if cachedEmojis, _, err := ap.store.Emojis(); err != nil {
+			ap.logger.Warn("Failed to read emojis cache, will refetch",
+				zap.String("cache_file", ap.store.Location()),
 				zap.Error(err))
-		} else {
+		} else if len(cachedEmojis) > 0 {
 			for _, e := range cachedEmojis {
 				ap.emojis[e.Name] = e
 			}
 			ap.logger.Info("Loaded emojis from cache",
 				zap.Int("count", len(cachedEmojis)),
-				zap.String("cache_file", ap.emojisCache))
+				zap.String("cache_file", ap.store.Location()))
 			ap.emojisReady = true
 			return nil
 		}
 	}
 
-	// Handle demo mode or nil client
-	if ap.client == nil {
-		ap.logger.Info("Client is nil (demo mode), using default emojis only")
+	// Handle demo mode or nil client; offline archives carry no custom emojis
+	if _, offline := ap.client.(*OfflineClient); ap.client == nil || offline {
+		ap.logger.Info("Client is nil (demo mode) or offline, using default emojis only")
 		// Just add the common unicode emojis and mark as ready
 		ap.addCommonUnicodeEmojis()
 		ap.emojisReady = true
@@ -1256,17 +1534,15 @@ func (ap *ApiProvider) RefreshEmojis(ctx context.Context) error {
 	}
 
 	// Save to cache
-	if data, err := json.MarshalIndent(emojiList, "", "  "); err != nil {
-		ap.logger.Error("Failed to marshal emojis for cache", zap.Error(err))
-	} else {
-		if err := ioutil.WriteFile(ap.emojisCache, data, 0644); err != nil {
-			ap.logger.Error("Failed to write cache file",
-				zap.String("cache_file", ap.emojisCache),
+	if ap.store != nil {
+		if err := ap.store.ReplaceEmojis(emojiList); err != nil {
+			ap.logger.Error("Failed to write emojis cache",
+				zap.String("cache_file", ap.store.Location()),
 				zap.Error(err))
 		} else {
 			ap.logger.Info("Wrote emojis to cache",
 				zap.Int("count", len(emojiList)),
-				zap.String("cache_file", ap.emojisCache))
+				zap.String("cache_file", ap.store.Location()))
 		}
 	}
 
@@ -1317,14 +1593,16 @@ func (ap *ApiProvider) addCommonUnicodeEmojis() {
 }
 
 func (ap *ApiProvider) RefreshChannels(ctx context.Context) error {
-	return ap.refreshChannelsInternal(ctx, false)
+	return ap.refreshChannelsInternal(ctx, false, "")
 }
 
 // ForceRefreshChannels bypasses the cache and fetches fresh channel data from Slack API.
-// Use this when a channel lookup fails to attempt recovery with fresh data.
+// Use this when a channel lookup fails to attempt recovery with fresh data. missing is the
+// #channel or @user that was not found, if any: when merging the user's conversations doesn't
+// turn it up, the whole channel list is fetched, which finds public channels the user hasn't joined.
 // Rate limited by SLACK_MCP_MIN_REFRESH_INTERVAL (default 30s) to prevent API abuse.
 // Returns ErrRefreshRateLimited if refresh is skipped due to rate limiting.
-func (ap *ApiProvider) ForceRefreshChannels(ctx context.Context) error {
+func (ap *ApiProvider) ForceRefreshChannels(ctx context.Context, missing string) error {
 	if ap.minRefreshInterval > 0 {
 		// Use single lock scope for check-and-update to prevent TOCTOU race
 		ap.channelsMu.Lock()
@@ -1342,92 +1620,98 @@ func (ap *ApiProvider) ForceRefreshChannels(ctx context.Context) error {
 	}
 
 	ap.logger.Info("Force refreshing channels cache")
-	return ap.refreshChannelsInternal(ctx, true)
+	return ap.refreshChannelsInternal(ctx, true, missing)
 }
 
-func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool) error {
+// refreshChannelsInternal works like refreshUsersInternal. The delta refresh of a forced refresh only lists
+// the conversations the user is a member of, and is followed by a full one when missing is still not found.
+func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool, missing string) error {
 	ap.channelsMu.Lock()
 	defer ap.channelsMu.Unlock()
 
-	// Check if we should use cache (not forced, cache exists, and within TTL)
-	if !force {
-		if data, err := os.ReadFile(ap.channelsCachePath); err == nil {
-			var cachedChannels []Channel
-			if err := json.Unmarshal(data, &cachedChannels); err != nil {
-				ap.logger.Warn("Failed to unmarshal channels cache, will refetch",
-					zap.String("cache_file", ap.channelsCachePath),
-					zap.Error(err))
-			} else if len(cachedChannels) == 0 {
-				ap.logger.Warn("Channels cache is empty or null, will refetch",
-					zap.String("cache_file", ap.channelsCachePath))
-			} else {
-				// Check cache TTL using file modification time
-				cacheValid := true
-				if ap.cacheTTL > 0 {
-					if fileInfo, err := os.Stat(ap.channelsCachePath); err == nil {
-						cacheAge := time.Since(fileInfo.ModTime())
-						if cacheAge > ap.cacheTTL {
-							ap.logger.Info("Channels cache expired, will refetch",
-								zap.Duration("cache_age", cacheAge),
-								zap.Duration("ttl", ap.cacheTTL),
-								zap.String("cache_file", ap.channelsCachePath))
-							cacheValid = false
-						}
+	var state CacheState
+	if ap.store != nil {
+		var (
+			cachedChannels []Channel
+			cachedState    CacheState
+			err            error
+		)
+		current := ap.channelsSnapshot.Load()
+		inMemory := current != nil && len(current.Channels) > 0
+		if inMemory {
+			cachedState, err = ap.store.ChannelsState()
+		} else {
+			cachedChannels, cachedState, err = ap.store.Channels()
+		}
+		if err != nil {
+			ap.logger.Warn("Failed to read channels cache, will refetch",
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		} else if !inMemory && len(cachedChannels) == 0 {
+			ap.logger.Warn("Channels cache is empty or null, will refetch",
+				zap.String("cache_file", ap.store.Location()))
+		} else {
+			state = cachedState
+			if !inMemory {
+				// Re-map channels with current users cache to ensure DM names are populated
+				usersMap := ap.ProvideUsersMap().Users
+				for i, c := range cachedChannels {
+					// For IM channels, re-generate the name and purpose using current users cache
+					if c.IsIM {
+						cachedChannels[i] = mapChannel(
+							c.ID, "", "", c.Topic, c.Purpose,
+							c.User, c.Members, c.MemberCount,
+							c.IsIM, c.IsMpIM, c.IsPrivate, c.IsExtShared,
+							usersMap,
+						)
 					}
 				}
+				ap.mergeChannels(cachedChannels)
+				ap.logger.Info("Loaded channels from cache and re-mapped DM names",
+					zap.Int("count", len(cachedChannels)),
+					zap.String("cache_file", ap.store.Location()))
+				ap.channelsReady = true
+			}
 
-				if cacheValid {
-					// Re-map channels with current users cache to ensure DM names are populated
-					usersMap := ap.ProvideUsersMap().Users
-					newSnapshot := &ChannelsCache{
-						Channels:    make(map[string]Channel, len(cachedChannels)),
-						ChannelsInv: make(map[string]string, len(cachedChannels)),
-					}
-					for _, c := range cachedChannels {
-						// For IM channels, re-generate the name and purpose using current users cache
-						if c.IsIM {
-							// Re-map the channel to get updated user name if available
-							remappedChannel := mapChannel(
-								c.ID, "", "", c.Topic, c.Purpose,
-								c.User, c.Members, c.MemberCount,
-								c.IsIM, c.IsMpIM, c.IsPrivate, c.IsExtShared,
-								usersMap,
-							)
-							newSnapshot.Channels[c.ID] = remappedChannel
-							newSnapshot.ChannelsInv[remappedChannel.Name] = c.ID
-						} else {
-							newSnapshot.Channels[c.ID] = c
-							newSnapshot.ChannelsInv[c.Name] = c.ID
-						}
-					}
-					ap.channelsSnapshot.Store(newSnapshot)
-					ap.logger.Info("Loaded channels from cache and re-mapped DM names",
-						zap.Int("count", len(cachedChannels)),
-						zap.String("cache_file", ap.channelsCachePath))
-					ap.channelsReady = true
-					return nil
-				}
+			cacheAge := time.Since(state.Refreshed)
+			if !force && (ap.cacheTTL == 0 || cacheAge <= ap.cacheTTL) {
+				return nil
+			}
+			if !force {
+				ap.logger.Info("Channels cache expired, refreshing",
+					zap.Duration("cache_age", cacheAge),
+					zap.Duration("ttl", ap.cacheTTL),
+					zap.String("cache_file", ap.store.Location()))
 			}
 		}
 	}
 
+	if current := ap.channelsSnapshot.Load(); current != nil && len(current.Channels) > 0 && (force || !ap.reconcileDue(state)) {
+		if err := ap.deltaRefreshChannels(ctx); err != nil {
+			return err
+		}
+		if _, found := ap.channelsSnapshot.Load().ChannelsInv[missing]; missing == "" || found {
+			return nil
+		}
+		ap.logger.Info("Channel not among the user's conversations, fetching all channels",
+			zap.String("channel", missing))
+	}
+
 	// Fetch fresh data from Slack API
 	channels := ap.GetChannels(ctx, AllChanTypes)
 
-	if len(channels) == 0 {
-		ap.logger.Warn("No channels fetched from Slack API, not writing empty cache",
-			zap.String("cache_file", ap.channelsCachePath))
-	} else if data, err := json.MarshalIndent(channels, "", "  "); err != nil {
-		ap.logger.Error("Failed to marshal channels for cache", zap.Error(err))
-	} else {
-		if err := os.WriteFile(ap.channelsCachePath, data, 0644); err != nil {
-			ap.logger.Error("Failed to write cache file",
-				zap.String("cache_file", ap.channelsCachePath),
+	if ap.store != nil {
+		if len(channels) == 0 {
+			ap.logger.Warn("No channels fetched from Slack API, not writing empty cache",
+				zap.String("cache_file", ap.store.Location()))
+		} else if err := ap.store.ReplaceChannels(channels); err != nil {
+			ap.logger.Error("Failed to write channels cache",
+				zap.String("cache_file", ap.store.Location()),
 				zap.Error(err))
 		} else {
 			ap.logger.Info("Wrote channels to cache",
 				zap.Int("count", len(channels)),
-				zap.String("cache_file", ap.channelsCachePath))
+				zap.String("cache_file", ap.store.Location()))
 		}
 	}
 
@@ -1579,6 +1863,78 @@ func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) [
 	return res
 }
 
+// UpdateChannel applies a single changed channel (rename, new topic or purpose, unarchive)
+// to the channels snapshot and the cache store, so name lookups see the change
+// immediately without paginating conversations.list again.
+func (ap *ApiProvider) UpdateChannel(channel slack.Channel) Channel {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	var usersMap map[string]slack.User
+	if users := ap.usersSnapshot.Load(); users != nil {
+		usersMap = users.Users
+	}
+
+	updated := mapChannel(
+		channel.ID,
+		channel.Name,
+		channel.NameNormalized,
+		channel.Topic.Value,
+		channel.Purpose.Value,
+		channel.User,
+		channel.Members,
+		channel.NumMembers,
+		channel.IsIM,
+		channel.IsMpIM,
+		channel.IsPrivate,
+		channel.IsExtShared,
+		usersMap,
+	)
+	updated.IsArchived = channel.IsArchived
+
+	return ap.modifyChannel(channel.ID, func(prev Channel, exists bool) (Channel, bool) {
+		if exists {
+			// Write responses don't always carry membership, keep what we already know
+			if updated.MemberCount == 0 {
+				updated.MemberCount = prev.MemberCount
+			}
+			if len(updated.Members) == 0 {
+				updated.Members = prev.Members
+			}
+		}
+		return updated, true
+	})
+}
+
+// modifyChannel replaces one channel of the snapshot with what modify returns, moves its name index on
+// rename and writes it to the cache store. modify returning false leaves the snapshot alone. The caller holds
+// channelsMu.
+func (ap *ApiProvider) modifyChannel(channelID string, modify func(prev Channel, exists bool) (Channel, bool)) Channel {
+	current := ap.channelsSnapshot.Load()
+	var prev Channel
+	var exists bool
+	if current != nil {
+		prev, exists = current.Channels[channelID]
+	}
+	updated, ok := modify(prev, exists)
+	if !ok {
+		return prev
+	}
+
+	ap.mergeChannels([]Channel{updated})
+
+	if ap.store != nil {
+		if err := ap.store.PutChannel(updated); err != nil {
+			ap.logger.Error("Failed to write channel to cache",
+				zap.String("channel", updated.ID),
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		}
+	}
+
+	return updated
+}
+
 func (ap *ApiProvider) ProvideUsersMap() *UsersCache {
 	// Atomic load - no lock needed, snapshot is immutable
 	return ap.usersSnapshot.Load()
@@ -1589,6 +1945,42 @@ func (ap *ApiProvider) ProvideChannelsMaps() *ChannelsCache {
 	return ap.channelsSnapshot.Load()
 }
 
+// UserByEmail returns the cached user with the given email address, compared case-insensitively
+func (ap *ApiProvider) UserByEmail(email string) (slack.User, bool) {
+	if ap.store != nil {
+		user, ok, err := ap.store.UserByEmail(email)
+		if err != nil {
+			ap.logger.Warn("Failed to look up user by email", zap.Error(err))
+		} else if ok {
+			return user, true
+		}
+	}
+	// Users the store has not seen yet, such as during the first refresh
+	if users := ap.usersSnapshot.Load(); users != nil {
+		for _, u := range users.Users {
+			if u.Profile.Email != "" && strings.EqualFold(u.Profile.Email, email) {
+				return u, true
+			}
+		}
+	}
+	return slack.User{}, false
+}
+
+// Close releases the cache store, the message archive and an offline archive
+func (ap *ApiProvider) Close() error {
+	var errs []error
+	if offline, ok := ap.client.(*OfflineClient); ok {
+		errs = append(errs, offline.Close())
+	}
+	if ap.store != nil {
+		errs = append(errs, ap.store.Close())
+	}
+	if ap.archive != nil {
+		errs = append(errs, ap.archive.Close())
+	}
+	return errors.Join(errs...)
+}
+
 func (ap *ApiProvider) ProvideEmojiMap() *EmojiCache {
 	return &EmojiCache{
 		Emojis: ap.emojis,
@@ -1623,6 +2015,15 @@ func (ap *ApiProvider) Slack() SlackAPI {
 	return ap.client
 }
 
+// WaitRateLimit blocks until the shared rate limiter used for cache refreshes allows another call.
+// Background pollers wait on it so they never compete with the caches for the Tier 2 budget.
+func (ap *ApiProvider) WaitRateLimit(ctx context.Context) error {
+	if ap.rateLimiter == nil {
+		return nil
+	}
+	return ap.rateLimiter.Wait(ctx)
+}
+
 func (ap *ApiProvider) IsBotToken() bool {
 	client, ok := ap.client.(*MCPSlackClient)
 	return ok && client != nil && client.IsBotToken()
@@ -1633,6 +2034,25 @@ func (ap *ApiProvider) IsOAuth() bool {
 	return ok && client != nil && client.IsOAuth()
 }
 
+// IsOffline reports whether the provider serves an offline archive instead of Slack
+func (ap *ApiProvider) IsOffline() bool {
+	_, ok := ap.client.(*OfflineClient)
+	return ok
+}
+
+// WorkspaceURL returns the workspace URL reported by auth.test (e.g. https://team.slack.com/),
+// or an empty string when it is not known
+func (ap *ApiProvider) WorkspaceURL() string {
+	if offline, ok := ap.client.(*OfflineClient); ok {
+		return offline.auth.URL
+	}
+	client, ok := ap.client.(*MCPSlackClient)
+	if !ok || client == nil || client.AuthResponse() == nil {
+		return ""
+	}
+	return client.AuthResponse().URL
+}
+
 // SlackBot returns the bot client for bot-identity posting
 // Returns nil if bot token is not configured
 func (ap *ApiProvider) SlackBot() *slack.Client {
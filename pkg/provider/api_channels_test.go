@@ -1,9 +1,15 @@
 package provider
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 // TestChannelTypeFiltering verifies the channel type classification logic used
@@ -101,3 +107,58 @@ func TestAllChanTypesConstant(t *testing.T) {
 	assert.Contains(t, AllChanTypes, "im")
 	assert.Contains(t, AllChanTypes, "mpim")
 }
+
+// TestUpdateChannel verifies that a single channel change replaces the snapshot entry,
+// moves the name index on rename and is persisted to the cache store.
+func TestUpdateChannel(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "channels.json")
+	store := &jsonStore{channelsPath: cachePath}
+	ap := &ApiProvider{logger: zap.NewNop(), store: store}
+	channels := map[string]Channel{
+		"C1": {ID: "C1", Name: "#old-name", Topic: "old topic", MemberCount: 12},
+		"C2": {ID: "C2", Name: "#other"},
+	}
+	require.NoError(t, store.ReplaceChannels([]Channel{channels["C1"], channels["C2"]}))
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels:    channels,
+		ChannelsInv: map[string]string{"#old-name": "C1", "#other": "C2"},
+	})
+	before := ap.ProvideChannelsMaps()
+
+	renamed := slack.Channel{}
+	renamed.ID = "C1"
+	renamed.Name = "new-name"
+	renamed.NameNormalized = "new-name"
+	renamed.Topic.Value = "new topic"
+
+	got := ap.UpdateChannel(renamed)
+	assert.Equal(t, "#new-name", got.Name)
+	assert.Equal(t, 12, got.MemberCount, "member count should be kept when the response has none")
+
+	snapshot := ap.ProvideChannelsMaps()
+	assert.Equal(t, "C1", snapshot.ChannelsInv["#new-name"])
+	assert.NotContains(t, snapshot.ChannelsInv, "#old-name")
+	assert.Equal(t, "new topic", snapshot.Channels["C1"].Topic)
+	assert.Equal(t, "C2", snapshot.ChannelsInv["#other"])
+
+	// The previous snapshot is immutable
+	assert.Equal(t, "C1", before.ChannelsInv["#old-name"])
+
+	data, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	var cached []Channel
+	require.NoError(t, json.Unmarshal(data, &cached))
+	assert.Len(t, cached, 2)
+	renamedInFile, ok, err := store.ChannelByName("#new-name")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "new topic", renamedInFile.Topic)
+
+	t.Run("unknown channel is added", func(t *testing.T) {
+		added := slack.Channel{}
+		added.ID = "C3"
+		added.Name = "restored"
+		ap.UpdateChannel(added)
+		assert.Equal(t, "C3", ap.ProvideChannelsMaps().ChannelsInv["#restored"])
+	})
+}
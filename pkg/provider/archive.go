@@ -0,0 +1,442 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+var (
+	messagesBucket     = []byte("messages")      // channel/ts -> ArchivedMessage
+	messageTermsBucket = []byte("message_terms") // term \x00 channel/ts -> empty, see archiveTerms
+	syncBucket         = []byte("sync")          // channel -> SyncState
+
+	archiveBuckets = [][]byte{messagesBucket, messageTermsBucket, syncBucket}
+)
+
+// maxTermLength keeps long tokens such as URLs and base64 blobs from bloating the term index
+const maxTermLength = 64
+
+// ArchivedMessage is a message as kept in the local message archive. User is the author's user ID, or
+// the bot ID of bot messages without one.
+type ArchivedMessage struct {
+	Channel  string `json:"channel"`
+	Ts       string `json:"ts"`
+	ThreadTs string `json:"thread_ts,omitempty"`
+	User     string `json:"user,omitempty"`
+	Username string `json:"username,omitempty"` // display name of bot messages
+	Text     string `json:"text"`
+	// Message is the message as history and thread reads return it, for serving those reads from the
+	// archive. Search matches don't carry it.
+	Message json.RawMessage `json:"message,omitempty"`
+}
+
+// Time returns when the message was posted
+func (m ArchivedMessage) Time() time.Time {
+	return tsTime(m.Ts)
+}
+
+// message decodes the message as Slack returned it. ok is false for copies without it.
+func (m ArchivedMessage) message() (msg slack.Message, ok bool) {
+	if len(m.Message) == 0 {
+		return msg, false
+	}
+	return msg, json.Unmarshal(m.Message, &msg) == nil
+}
+
+// MessageQuery selects messages of the archive. Empty fields match every message.
+type MessageQuery struct {
+	// Text holds words that must all appear in a message, in any order and case. A word ending in *
+	// matches the words starting with the rest.
+	Text        string
+	Channel     string
+	User        string
+	ThreadsOnly bool
+	After       time.Time // inclusive
+	Before      time.Time // exclusive
+}
+
+// MessageArchive keeps the messages the server has seen, from history and thread reads, searches and
+// events, in a bbolt database with an inverted index of their words. It lets bot tokens, which cannot
+// call search.messages, search what was read before, without a round trip to Slack.
+type MessageArchive struct {
+	db *boltStore
+}
+
+// openMessageArchive opens the archive at path when SLACK_MCP_MESSAGE_ARCHIVE is enabled or channels are
+// synced into it, and returns nil otherwise or when the database cannot be opened
+func openMessageArchive(path string, logger *zap.Logger) *MessageArchive {
+	patterns, _ := SyncPatterns()
+	if enabled, _ := strconv.ParseBool(os.Getenv("SLACK_MCP_MESSAGE_ARCHIVE")); !enabled && len(patterns) == 0 {
+		return nil
+	}
+	archive, err := OpenMessageArchive(path)
+	if err != nil {
+		logger.Warn("Failed to open message archive, messages will not be archived",
+			zap.String("archive_file", path),
+			zap.Error(err))
+		return nil
+	}
+	logger.Info("Archiving messages", zap.String("archive_file", path))
+	return archive
+}
+
+// OpenMessageArchive opens or creates the message archive at path
+func OpenMessageArchive(path string) (*MessageArchive, error) {
+	db, err := openBoltDB(path, archiveBuckets)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageArchive{db: db}, nil
+}
+
+func (a *MessageArchive) Close() error {
+	return a.db.Close()
+}
+
+func (a *MessageArchive) Location() string {
+	return a.db.Location()
+}
+
+func archiveKey(channel, ts string) []byte {
+	return []byte(channel + "/" + ts)
+}
+
+func termKey(term string, key []byte) []byte {
+	return append([]byte(term+"\x00"), key...)
+}
+
+// Put adds messages to the archive, replacing earlier copies and their index entries. A copy without
+// Message, such as a search match, does not replace one with it.
+func (a *MessageArchive) Put(messages ...ArchivedMessage) error {
+	_, err := a.put(messages)
+	return err
+}
+
+// put works like Put and returns how many of the messages the archive did not hold yet
+func (a *MessageArchive) put(messages []ArchivedMessage) (added int, err error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+	err = a.db.db.Update(func(tx *bolt.Tx) error {
+		added = 0
+		records, terms := tx.Bucket(messagesBucket), tx.Bucket(messageTermsBucket)
+		for _, m := range messages {
+			if m.Channel == "" || m.Ts == "" {
+				continue
+			}
+			key := archiveKey(m.Channel, m.Ts)
+			if prev := records.Get(key); prev == nil {
+				added++
+			} else if len(m.Message) == 0 && hasMessage(prev) {
+				continue
+			}
+			if err := unindexMessage(records, terms, key); err != nil {
+				return err
+			}
+			data, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if err := records.Put(key, data); err != nil {
+				return err
+			}
+			for _, term := range archiveTerms(m.Text) {
+				if err := terms.Put(termKey(term, key), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return added, err
+}
+
+// Delete removes a message from the archive
+func (a *MessageArchive) Delete(channel, ts string) error {
+	return a.db.db.Update(func(tx *bolt.Tx) error {
+		records, terms := tx.Bucket(messagesBucket), tx.Bucket(messageTermsBucket)
+		key := archiveKey(channel, ts)
+		if err := unindexMessage(records, terms, key); err != nil {
+			return err
+		}
+		return records.Delete(key)
+	})
+}
+
+// hasMessage reports whether an archived record keeps the message as Slack returned it
+func hasMessage(raw []byte) bool {
+	var prev ArchivedMessage
+	return json.Unmarshal(raw, &prev) == nil && len(prev.Message) > 0
+}
+
+// unindexMessage removes the index entries of the archived copy of a message, if any
+func unindexMessage(records, terms *bolt.Bucket, key []byte) error {
+	raw := records.Get(key)
+	if raw == nil {
+		return nil
+	}
+	var prev ArchivedMessage
+	if err := json.Unmarshal(raw, &prev); err != nil {
+		return nil // leaves stale terms, which only cost a lookup
+	}
+	for _, term := range archiveTerms(prev.Text) {
+		if err := terms.Delete(termKey(term, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncState returns the sync progress of a channel, the zero state for channels never synced
+func (a *MessageArchive) SyncState(channel string) (SyncState, error) {
+	state := SyncState{Channel: channel}
+	err := a.db.db.View(func(tx *bolt.Tx) error {
+		if raw := tx.Bucket(syncBucket).Get([]byte(channel)); raw != nil {
+			return json.Unmarshal(raw, &state)
+		}
+		return nil
+	})
+	return state, err
+}
+
+// PutSyncState records the sync progress of a channel
+func (a *MessageArchive) PutSyncState(state SyncState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return a.db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(syncBucket).Put([]byte(state.Channel), data)
+	})
+}
+
+// Search returns the archived messages matching q, newest first
+func (a *MessageArchive) Search(q MessageQuery) ([]ArchivedMessage, error) {
+	var matches []ArchivedMessage
+	err := a.db.db.View(func(tx *bolt.Tx) error {
+		records := tx.Bucket(messagesBucket)
+		keys, indexed := matchTerms(tx.Bucket(messageTermsBucket), q.Text)
+
+		match := func(raw []byte) error {
+			var m ArchivedMessage
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			if q.matches(m) {
+				matches = append(matches, m)
+			}
+			return nil
+		}
+
+		if indexed {
+			for key := range keys {
+				if raw := records.Get([]byte(key)); raw != nil {
+					if err := match(raw); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+		// Without words to look up, scan the channel's messages or all of them
+		var prefix []byte
+		if q.Channel != "" {
+			prefix = archiveKey(q.Channel, "")
+		}
+		c := records.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if err := match(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Time().After(matches[j].Time())
+	})
+	return matches, err
+}
+
+func (q MessageQuery) matches(m ArchivedMessage) bool {
+	if q.Channel != "" && m.Channel != q.Channel {
+		return false
+	}
+	if q.User != "" && m.User != q.User {
+		return false
+	}
+	if q.ThreadsOnly && m.ThreadTs == "" {
+		return false
+	}
+	t := m.Time()
+	if !q.After.IsZero() && t.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && !t.Before(q.Before) {
+		return false
+	}
+	return true
+}
+
+// matchTerms returns the keys of the messages containing every word of query. indexed is false when the
+// query has no words, and every message matches.
+func matchTerms(terms *bolt.Bucket, query string) (keys map[string]struct{}, indexed bool) {
+	for _, word := range strings.Fields(query) {
+		words := archiveTerms(word)
+		for i, term := range words {
+			found := make(map[string]struct{})
+			seek := []byte(term + "\x00")
+			if i == len(words)-1 && strings.HasSuffix(word, "*") {
+				seek = []byte(term)
+			}
+			c := terms.Cursor()
+			for k, _ := c.Seek(seek); k != nil && bytes.HasPrefix(k, seek); k, _ = c.Next() {
+				if sep := bytes.IndexByte(k, 0); sep >= 0 {
+					found[string(k[sep+1:])] = struct{}{}
+				}
+			}
+
+			if !indexed {
+				keys, indexed = found, true
+				continue
+			}
+			for key := range keys {
+				if _, ok := found[key]; !ok {
+					delete(keys, key)
+				}
+			}
+		}
+	}
+	return keys, indexed
+}
+
+// archiveTerms splits text into the distinct lower-cased words the archive is indexed by
+func archiveTerms(s string) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) > maxTermLength {
+			continue
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// tsTime converts a Slack message timestamp such as 1700000000.123456 to a time
+func tsTime(ts string) time.Time {
+	sec, frac, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	var nsec int64
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		nsec, _ = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	}
+	return time.Unix(s, nsec)
+}
+
+// ArchivedFromMessage converts a message of conversations.history or conversations.replies, keeping the
+// text of its attachments and blocks searchable
+func ArchivedFromMessage(channelID string, msg slack.Message) ArchivedMessage {
+	user := msg.User
+	if user == "" {
+		user = msg.BotID
+	}
+	raw, _ := json.Marshal(msg)
+	return ArchivedMessage{
+		Channel:  channelID,
+		Ts:       msg.Timestamp,
+		ThreadTs: msg.ThreadTimestamp,
+		User:     user,
+		Username: msg.Username,
+		Text:     msg.Text + text.AttachmentsTo2CSV(msg.Text, msg.Attachments) + text.BlocksToText(msg.Blocks),
+		Message:  raw,
+	}
+}
+
+// ArchivedFromSearch converts a search.messages match. Matches only name their thread in the permalink.
+func ArchivedFromSearch(match slack.SearchMessage) ArchivedMessage {
+	var threadTs string
+	if u, err := url.Parse(match.Permalink); err == nil {
+		threadTs = u.Query().Get("thread_ts")
+	}
+	user := match.User
+	if user == "" {
+		// Bot matches carry the bot ID in Username
+		user = match.Username
+	}
+	return ArchivedMessage{
+		Channel:  match.Channel.ID,
+		Ts:       match.Timestamp,
+		ThreadTs: threadTs,
+		User:     user,
+		Text:     match.Text + text.AttachmentsTo2CSV(match.Text, match.Attachments) + text.BlocksToText(match.Blocks),
+	}
+}
+
+// MessageArchive returns the local message archive, or nil unless SLACK_MCP_MESSAGE_ARCHIVE is enabled
+func (ap *ApiProvider) MessageArchive() *MessageArchive {
+	return ap.archive
+}
+
+// ArchiveMessages records messages in the message archive, if there is one. Failures are logged; the
+// archive is a cache and reads must not fail over it.
+func (ap *ApiProvider) ArchiveMessages(messages ...ArchivedMessage) {
+	if ap.archive == nil {
+		return
+	}
+	if err := ap.archive.Put(messages...); err != nil {
+		ap.logger.Warn("Failed to archive messages",
+			zap.String("archive_file", ap.archive.Location()),
+			zap.Int("count", len(messages)),
+			zap.Error(err))
+	}
+}
+
+// ArchiveHistory records the messages of a history or replies read of a channel
+func (ap *ApiProvider) ArchiveHistory(channelID string, messages []slack.Message) {
+	if ap.archive == nil {
+		return
+	}
+	archived := make([]ArchivedMessage, 0, len(messages))
+	for _, msg := range messages {
+		archived = append(archived, ArchivedFromMessage(channelID, msg))
+	}
+	ap.ArchiveMessages(archived...)
+}
+
+// ArchiveSearchMatches records the matches of a search.messages call
+func (ap *ApiProvider) ArchiveSearchMatches(matches []slack.SearchMessage) {
+	if ap.archive == nil {
+		return
+	}
+	archived := make([]ArchivedMessage, 0, len(matches))
+	for _, match := range matches {
+		archived = append(archived, ArchivedFromSearch(match))
+	}
+	ap.ArchiveMessages(archived...)
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func archivedTs(matches []ArchivedMessage) []string {
+	ts := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ts = append(ts, m.Ts)
+	}
+	return ts
+}
+
+func TestMessageArchive(t *testing.T) {
+	archive, err := OpenMessageArchive(filepath.Join(t.TempDir(), "messages.db"))
+	require.NoError(t, err)
+	defer archive.Close()
+
+	require.NoError(t, archive.Put(
+		ArchivedMessage{Channel: "C1", Ts: "1700000000.000100", User: "U1", Text: "Deploying the API to production"},
+		ArchivedMessage{Channel: "C1", Ts: "1700000100.000100", User: "U2", Text: "deploy failed: <https://ci.example.com|CI>", ThreadTs: "1700000000.000100"},
+		ArchivedMessage{Channel: "C2", Ts: "1700090000.000100", User: "U1", Text: "Lunch?"},
+	))
+
+	search := func(q MessageQuery) []string {
+		matches, err := archive.Search(q)
+		require.NoError(t, err)
+		return archivedTs(matches)
+	}
+
+	assert.Equal(t, []string{"1700000000.000100"}, search(MessageQuery{Text: "api PRODUCTION"}), "all words, any case")
+	assert.Empty(t, search(MessageQuery{Text: "api lunch"}))
+	assert.Equal(t, []string{"1700000100.000100", "1700000000.000100"}, search(MessageQuery{Text: "deploy*"}), "prefix, newest first")
+	assert.Equal(t, []string{"1700000100.000100"}, search(MessageQuery{Text: "deploy"}))
+	assert.Equal(t, []string{"1700000100.000100"}, search(MessageQuery{Text: "deploy*", ThreadsOnly: true}))
+	assert.Equal(t, []string{"1700000000.000100"}, search(MessageQuery{Text: "deploy*", User: "U1"}))
+	assert.Equal(t, []string{"1700000100.000100", "1700000000.000100"}, search(MessageQuery{Channel: "C1"}), "no words lists the channel")
+	assert.Equal(t, []string{"1700090000.000100"}, search(MessageQuery{After: time.Unix(1700000100, 200000)}))
+	assert.Equal(t, []string{"1700000000.000100"}, search(MessageQuery{Channel: "C1", Before: time.Unix(1700000100, 0)}))
+
+	t.Run("edits are reindexed", func(t *testing.T) {
+		require.NoError(t, archive.Put(ArchivedMessage{Channel: "C2", Ts: "1700090000.000100", User: "U1", Text: "Dinner?"}))
+		assert.Empty(t, search(MessageQuery{Text: "lunch"}))
+		assert.Equal(t, []string{"1700090000.000100"}, search(MessageQuery{Text: "dinner"}))
+	})
+
+	t.Run("search matches keep the message read from history", func(t *testing.T) {
+		msg := slack.Message{}
+		msg.Timestamp, msg.User, msg.Text, msg.ReplyCount = "1700000200.000100", "U1", "rollback done", 3
+		require.NoError(t, archive.Put(ArchivedFromMessage("C1", msg)))
+		require.NoError(t, archive.Put(ArchivedMessage{Channel: "C1", Ts: msg.Timestamp, User: "U1", Text: "rollback done"}))
+
+		matches, err := archive.Search(MessageQuery{Text: "rollback"})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		got, ok := matches[0].message()
+		require.True(t, ok)
+		assert.Equal(t, 3, got.ReplyCount)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, archive.Delete("C2", "1700090000.000100"))
+		assert.Empty(t, search(MessageQuery{Text: "dinner"}))
+		assert.Empty(t, search(MessageQuery{Channel: "C2"}))
+	})
+}
+
+func TestArchiveTerms(t *testing.T) {
+	assert.Equal(t, []string{"it", "s", "a", "déjà", "vu", "https", "ci", "example", "com"}, archiveTerms("It's a déjà-vu: <https://ci.example.com|CI>"))
+}
+
+func TestArchiveMessageEvents(t *testing.T) {
+	t.Setenv("SLACK_MCP_MESSAGE_ARCHIVE", "true")
+	archive := openMessageArchive(filepath.Join(t.TempDir(), "messages.db"), zap.NewNop())
+	require.NotNil(t, archive)
+	defer archive.Close()
+	ap := &ApiProvider{logger: zap.NewNop(), archive: archive}
+
+	deliver := func(ev *slackevents.MessageEvent) {
+		ap.ApplyEvent(slackevents.EventsAPIEvent{
+			Type:       slackevents.CallbackEvent,
+			InnerEvent: slackevents.EventsAPIInnerEvent{Type: "message", Data: ev},
+		})
+	}
+	search := func(text string) []string {
+		matches, err := archive.Search(MessageQuery{Text: text})
+		require.NoError(t, err)
+		return archivedTs(matches)
+	}
+
+	deliver(&slackevents.MessageEvent{Channel: "C1", User: "U1", TimeStamp: "1700000000.000100", Text: "rollback started"})
+	assert.Equal(t, []string{"1700000000.000100"}, search("rollback"))
+
+	deliver(&slackevents.MessageEvent{Channel: "C1", SubType: "message_changed", Message: &slack.Msg{User: "U1", Timestamp: "1700000000.000100", Text: "rollback done"}})
+	assert.Equal(t, []string{"1700000000.000100"}, search("done"))
+	assert.Empty(t, search("started"))
+
+	deliver(&slackevents.MessageEvent{Channel: "C1", SubType: "message_deleted", DeletedTimeStamp: "1700000000.000100"})
+	assert.Empty(t, search("rollback"))
+}
@@ -0,0 +1,395 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	usersBucket          = []byte("users")
+	usersByNameBucket    = []byte("users_by_name")
+	usersByEmailBucket   = []byte("users_by_email")
+	channelsBucket       = []byte("channels")
+	channelsByNameBucket = []byte("channels_by_name")
+	emojisBucket         = []byte("emojis")
+	metaBucket           = []byte("meta") // see boltMetaKeys
+
+	cacheBuckets = [][]byte{usersBucket, usersByNameBucket, usersByEmailBucket, channelsBucket, channelsByNameBucket, emojisBucket, metaBucket}
+)
+
+// boltMetaKeys returns the keys of the meta bucket holding when a collection was last refreshed and reconciled
+func boltMetaKeys(bucket []byte) (refreshed, reconciled []byte) {
+	return []byte(string(bucket) + "_refreshed"), bucket
+}
+
+// boltIndex maps a secondary key of the records in a bucket to their IDs. Records with an empty key are
+// not indexed.
+type boltIndex[T any] struct {
+	bucket []byte
+	key    func(T) string
+}
+
+var userIndexes = []boltIndex[slack.User]{
+	{usersByNameBucket, func(u slack.User) string { return u.Name }},
+	{usersByEmailBucket, func(u slack.User) string { return strings.ToLower(u.Profile.Email) }},
+}
+
+var channelIndexes = []boltIndex[Channel]{
+	{channelsByNameBucket, func(c Channel) string { return c.Name }},
+}
+
+// boltStore keeps the caches in a bbolt database, one record per user, channel and emoji, with indexes
+// for lookups by name and email. Writes touch only the records that changed.
+type boltStore struct {
+	db   *bolt.DB
+	path string
+	refs int // guarded by boltStoresMu
+}
+
+// Open databases by path. A database can only be opened once at a time, and providers of the same team,
+// such as a tenant replacing another after a token rotation, share it.
+var (
+	boltStoresMu sync.Mutex
+	boltStores   = make(map[string]*boltStore)
+)
+
+func openBoltStore(path string) (*boltStore, error) {
+	return openBoltDB(path, cacheBuckets)
+}
+
+// openBoltDB opens the database at path, or shares it when it is already open, creating the given buckets
+func openBoltDB(path string, buckets [][]byte) (*boltStore, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	boltStoresMu.Lock()
+	defer boltStoresMu.Unlock()
+	if s, ok := boltStores[path]; ok {
+		s.refs++
+		return s, nil
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &boltStore{db: db, path: path, refs: 1}
+	boltStores[path] = s
+	return s, nil
+}
+
+func (s *boltStore) Close() error {
+	boltStoresMu.Lock()
+	defer boltStoresMu.Unlock()
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+	delete(boltStores, s.path)
+	return s.db.Close()
+}
+
+func (s *boltStore) Location() string {
+	return s.path
+}
+
+// boltStateTx reads the state of a bucket from the meta bucket
+func boltStateTx(tx *bolt.Tx, bucket []byte) (CacheState, error) {
+	var state CacheState
+	refreshedKey, reconciledKey := boltMetaKeys(bucket)
+	for key, t := range map[string]*time.Time{string(refreshedKey): &state.Refreshed, string(reconciledKey): &state.Reconciled} {
+		if raw := tx.Bucket(metaBucket).Get([]byte(key)); raw != nil {
+			if err := t.UnmarshalText(raw); err != nil {
+				return CacheState{}, err
+			}
+		}
+	}
+	return state, nil
+}
+
+// boltState returns the state of a bucket without reading its records
+func boltState(db *bolt.DB, bucket []byte) (state CacheState, err error) {
+	err = db.View(func(tx *bolt.Tx) error {
+		state, err = boltStateTx(tx, bucket)
+		return err
+	})
+	return state, err
+}
+
+// boltAll returns every record of a bucket and its state
+func boltAll[T any](db *bolt.DB, bucket []byte) ([]T, CacheState, error) {
+	var (
+		items []T
+		state CacheState
+	)
+	err := db.View(func(tx *bolt.Tx) error {
+		var err error
+		if state, err = boltStateTx(tx, bucket); err != nil {
+			return err
+		}
+		return tx.Bucket(bucket).ForEach(func(_, v []byte) error {
+			var item T
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	return items, state, err
+}
+
+func boltSetTime(tx *bolt.Tx, key []byte, t time.Time) error {
+	if t.IsZero() {
+		return tx.Bucket(metaBucket).Delete(key)
+	}
+	raw, err := t.MarshalText()
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(metaBucket).Put(key, raw)
+}
+
+// boltReplace makes the records of a bucket items, writing the ones that changed and deleting the ones
+// that are gone, and records the state
+func boltReplace[T any](db *bolt.DB, bucket []byte, indexes []boltIndex[T], id func(T) string, items []T, state CacheState) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		keep := make(map[string]bool, len(items))
+		for _, item := range items {
+			keep[id(item)] = true
+			if err := boltPutTx(tx, bucket, indexes, id, item); err != nil {
+				return err
+			}
+		}
+		var gone [][]byte
+		err := tx.Bucket(bucket).ForEach(func(k, _ []byte) error {
+			if !keep[string(k)] {
+				gone = append(gone, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range gone {
+			if err := boltDeleteTx(tx, bucket, indexes, key); err != nil {
+				return err
+			}
+		}
+		refreshedKey, reconciledKey := boltMetaKeys(bucket)
+		if err := boltSetTime(tx, refreshedKey, state.Refreshed); err != nil {
+			return err
+		}
+		return boltSetTime(tx, reconciledKey, state.Reconciled)
+	})
+}
+
+// boltMerge writes the records of a delta refresh and records when
+func boltMerge[T any](db *bolt.DB, bucket []byte, indexes []boltIndex[T], id func(T) string, items []T) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, item := range items {
+			if err := boltPutTx(tx, bucket, indexes, id, item); err != nil {
+				return err
+			}
+		}
+		refreshedKey, _ := boltMetaKeys(bucket)
+		return boltSetTime(tx, refreshedKey, time.Now())
+	})
+}
+
+// boltUnindexTx removes the index entries of the record stored under key that still point to it
+func boltUnindexTx[T any](tx *bolt.Tx, indexes []boltIndex[T], key, raw []byte) error {
+	var prev T
+	if err := json.Unmarshal(raw, &prev); err != nil {
+		return nil
+	}
+	for _, index := range indexes {
+		prevKey := []byte(index.key(prev))
+		b := tx.Bucket(index.bucket)
+		if len(prevKey) > 0 && bytes.Equal(b.Get(prevKey), key) {
+			if err := b.Delete(prevKey); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// boltPutTx writes one record unless it is unchanged, moving its index entries from the values of the
+// record it replaces
+func boltPutTx[T any](tx *bolt.Tx, bucket []byte, indexes []boltIndex[T], id func(T) string, item T) error {
+	key := []byte(id(item))
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	records := tx.Bucket(bucket)
+	if raw := records.Get(key); raw != nil {
+		if bytes.Equal(raw, data) {
+			return nil
+		}
+		if err := boltUnindexTx(tx, indexes, key, raw); err != nil {
+			return err
+		}
+	}
+
+	if err := records.Put(key, data); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if indexKey := index.key(item); indexKey != "" {
+			if err := tx.Bucket(index.bucket).Put([]byte(indexKey), key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// boltDeleteTx deletes one record and its index entries
+func boltDeleteTx[T any](tx *bolt.Tx, bucket []byte, indexes []boltIndex[T], key []byte) error {
+	records := tx.Bucket(bucket)
+	if raw := records.Get(key); raw != nil {
+		if err := boltUnindexTx(tx, indexes, key, raw); err != nil {
+			return err
+		}
+	}
+	return records.Delete(key)
+}
+
+func boltPut[T any](db *bolt.DB, bucket []byte, indexes []boltIndex[T], id func(T) string, item T) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		return boltPutTx(tx, bucket, indexes, id, item)
+	})
+}
+
+// boltGet returns the record with the given key, looked up through index unless it is nil
+func boltGet[T any](db *bolt.DB, bucket []byte, index *boltIndex[T], key string) (T, bool, error) {
+	var (
+		item  T
+		found bool
+	)
+	err := db.View(func(tx *bolt.Tx) error {
+		id := []byte(key)
+		if index != nil {
+			if id = tx.Bucket(index.bucket).Get(id); id == nil {
+				return nil
+			}
+		}
+		raw := tx.Bucket(bucket).Get(id)
+		if raw == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(raw, &item)
+	})
+	return item, found, err
+}
+
+func userID(u slack.User) string { return u.ID }
+func channelID(c Channel) string { return c.ID }
+func emojiName(e Emoji) string   { return e.Name }
+
+func (s *boltStore) Users() ([]slack.User, CacheState, error) {
+	return boltAll[slack.User](s.db, usersBucket)
+}
+
+func (s *boltStore) UsersState() (CacheState, error) {
+	return boltState(s.db, usersBucket)
+}
+
+func (s *boltStore) ReplaceUsers(users []slack.User) error {
+	now := time.Now()
+	return s.replaceUsers(users, CacheState{Refreshed: now, Reconciled: now})
+}
+
+func (s *boltStore) replaceUsers(users []slack.User, state CacheState) error {
+	return boltReplace(s.db, usersBucket, userIndexes, userID, users, state)
+}
+
+func (s *boltStore) MergeUsers(users []slack.User) error {
+	return boltMerge(s.db, usersBucket, userIndexes, userID, users)
+}
+
+func (s *boltStore) PutUser(user slack.User) error {
+	return boltPut(s.db, usersBucket, userIndexes, userID, user)
+}
+
+func (s *boltStore) UserByID(id string) (slack.User, bool, error) {
+	return boltGet[slack.User](s.db, usersBucket, nil, id)
+}
+
+func (s *boltStore) UserByName(name string) (slack.User, bool, error) {
+	return boltGet(s.db, usersBucket, &userIndexes[0], name)
+}
+
+func (s *boltStore) UserByEmail(email string) (slack.User, bool, error) {
+	return boltGet(s.db, usersBucket, &userIndexes[1], strings.ToLower(email))
+}
+
+func (s *boltStore) Channels() ([]Channel, CacheState, error) {
+	return boltAll[Channel](s.db, channelsBucket)
+}
+
+func (s *boltStore) ChannelsState() (CacheState, error) {
+	return boltState(s.db, channelsBucket)
+}
+
+func (s *boltStore) ReplaceChannels(channels []Channel) error {
+	now := time.Now()
+	return s.replaceChannels(channels, CacheState{Refreshed: now, Reconciled: now})
+}
+
+func (s *boltStore) replaceChannels(channels []Channel, state CacheState) error {
+	return boltReplace(s.db, channelsBucket, channelIndexes, channelID, channels, state)
+}
+
+func (s *boltStore) MergeChannels(channels []Channel) error {
+	return boltMerge(s.db, channelsBucket, channelIndexes, channelID, channels)
+}
+
+func (s *boltStore) PutChannel(channel Channel) error {
+	return boltPut(s.db, channelsBucket, channelIndexes, channelID, channel)
+}
+
+func (s *boltStore) ChannelByID(id string) (Channel, bool, error) {
+	return boltGet[Channel](s.db, channelsBucket, nil, id)
+}
+
+func (s *boltStore) ChannelByName(name string) (Channel, bool, error) {
+	return boltGet(s.db, channelsBucket, &channelIndexes[0], name)
+}
+
+func (s *boltStore) Emojis() ([]Emoji, CacheState, error) {
+	return boltAll[Emoji](s.db, emojisBucket)
+}
+
+func (s *boltStore) ReplaceEmojis(emojis []Emoji) error {
+	now := time.Now()
+	return s.replaceEmojis(emojis, CacheState{Refreshed: now, Reconciled: now})
+}
+
+func (s *boltStore) replaceEmojis(emojis []Emoji, state CacheState) error {
+	return boltReplace(s.db, emojisBucket, nil, emojiName, emojis, state)
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// reconcileDue reports whether the next refresh of a cache should be a full one, which also drops the
+// users and channels deleted in Slack since
+func (ap *ApiProvider) reconcileDue(state CacheState) bool {
+	return ap.reconcileInterval == 0 || time.Since(state.Reconciled) > ap.reconcileInterval
+}
+
+// deltaRefreshUsers merges the users updated since they were cached. users.list cannot filter by the
+// updated timestamp, so every page is still read, but unchanged users are not rewritten and the Slack
+// Connect users of client.userBoot wait for the full refresh. The caller holds usersMu.
+func (ap *ApiProvider) deltaRefreshUsers(ctx context.Context) error {
+	users, err := ap.client.GetUsersContext(ctx, slack.GetUsersOptionLimit(1000))
+	if err != nil {
+		ap.logger.Error("Failed to fetch users", zap.Error(err))
+		return err
+	}
+
+	current := ap.usersSnapshot.Load()
+	var changed []slack.User
+	for _, u := range users {
+		if prev, ok := current.Users[u.ID]; !ok || u.Updated > prev.Updated {
+			changed = append(changed, u)
+		}
+	}
+	ap.mergeUsers(changed)
+
+	if ap.store != nil {
+		if err := ap.store.MergeUsers(changed); err != nil {
+			ap.logger.Error("Failed to write users cache",
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		}
+	}
+	ap.logger.Info("Merged changed users into cache",
+		zap.Int("fetched", len(users)),
+		zap.Int("changed", len(changed)))
+	ap.usersReady = true
+	return nil
+}
+
+// deltaRefreshChannels merges the conversations the user is a member of, from users.conversations. That
+// is a fraction of conversations.list in large workspaces; public channels the user has not joined wait
+// for the full refresh, or for a channel_created event. Cached conversations archived since are marked
+// archived, as a channel_archive event would. Conversations carry no updated timestamp in the client
+// library, so changes are found by comparing with the cached channel. The caller holds channelsMu.
+func (ap *ApiProvider) deltaRefreshChannels(ctx context.Context) error {
+	params := &slack.GetConversationsForUserParameters{
+		Types: AllChanTypes,
+		Limit: 999,
+	}
+
+	current := ap.channelsSnapshot.Load()
+	usersMap := ap.ProvideUsersMap().Users
+	var (
+		changed []Channel
+		fetched int
+	)
+	for {
+		if err := ap.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+		channels, nextCursor, err := ap.client.GetConversationsForUserContext(ctx, params)
+		if err != nil {
+			ap.logger.Error("Failed to fetch channels", zap.Error(err))
+			return err
+		}
+		fetched += len(channels)
+
+		for _, channel := range channels {
+			if _, ok := current.Channels[channel.ID]; channel.IsArchived && !ok {
+				// the full refresh leaves out archived conversations too
+				continue
+			}
+			ch := mapChannel(
+				channel.ID,
+				channel.Name,
+				channel.NameNormalized,
+				channel.Topic.Value,
+				channel.Purpose.Value,
+				channel.User,
+				channel.Members,
+				channel.NumMembers,
+				channel.IsIM,
+				channel.IsMpIM,
+				channel.IsPrivate,
+				channel.IsExtShared,
+				usersMap,
+			)
+			ch.IsArchived = channel.IsArchived
+			if prev, ok := current.Channels[ch.ID]; ok {
+				// users.conversations leaves out membership, keep what we already know
+				if ch.MemberCount == 0 {
+					ch.MemberCount = prev.MemberCount
+				}
+				if len(ch.Members) == 0 {
+					ch.Members = prev.Members
+				}
+				if reflect.DeepEqual(prev, ch) {
+					continue
+				}
+			}
+			changed = append(changed, ch)
+		}
+
+		if nextCursor == "" {
+			break
+		}
+		params.Cursor = nextCursor
+	}
+	ap.mergeChannels(changed)
+
+	if ap.store != nil {
+		if err := ap.store.MergeChannels(changed); err != nil {
+			ap.logger.Error("Failed to write channels cache",
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		}
+	}
+	ap.logger.Info("Merged changed channels into cache",
+		zap.Int("fetched", fetched),
+		zap.Int("changed", len(changed)))
+	ap.channelsReady = true
+	return nil
+}
+
+// mergeUsers replaces or adds users in a new users snapshot, moving the name index of renamed ones. The
+// caller holds usersMu.
+func (ap *ApiProvider) mergeUsers(users []slack.User) {
+	if len(users) == 0 {
+		return
+	}
+	current := ap.usersSnapshot.Load()
+	size := len(users)
+	if current != nil {
+		size += len(current.Users)
+	}
+	newSnapshot := &UsersCache{
+		Users:    make(map[string]slack.User, size),
+		UsersInv: make(map[string]string, size),
+	}
+	if current != nil {
+		for id, u := range current.Users {
+			newSnapshot.Users[id] = u
+		}
+		for name, id := range current.UsersInv {
+			newSnapshot.UsersInv[name] = id
+		}
+	}
+	for _, user := range users {
+		if prev, ok := newSnapshot.Users[user.ID]; ok && newSnapshot.UsersInv[prev.Name] == user.ID {
+			delete(newSnapshot.UsersInv, prev.Name)
+		}
+		newSnapshot.Users[user.ID] = user
+		newSnapshot.UsersInv[user.Name] = user.ID
+	}
+	ap.usersSnapshot.Store(newSnapshot)
+}
+
+// mergeChannels replaces or adds channels in a new channels snapshot, moving the name index of renamed
+// ones. The caller holds channelsMu.
+func (ap *ApiProvider) mergeChannels(channels []Channel) {
+	if len(channels) == 0 {
+		return
+	}
+	current := ap.channelsSnapshot.Load()
+	size := len(channels)
+	if current != nil {
+		size += len(current.Channels)
+	}
+	newSnapshot := &ChannelsCache{
+		Channels:    make(map[string]Channel, size),
+		ChannelsInv: make(map[string]string, size),
+	}
+	if current != nil {
+		for id, c := range current.Channels {
+			newSnapshot.Channels[id] = c
+		}
+		for name, id := range current.ChannelsInv {
+			newSnapshot.ChannelsInv[name] = id
+		}
+	}
+	for _, channel := range channels {
+		if prev, ok := newSnapshot.Channels[channel.ID]; ok && newSnapshot.ChannelsInv[prev.Name] == channel.ID {
+			delete(newSnapshot.ChannelsInv, prev.Name)
+		}
+		newSnapshot.Channels[channel.ID] = channel
+		newSnapshot.ChannelsInv[channel.Name] = channel.ID
+	}
+	ap.channelsSnapshot.Store(newSnapshot)
+}
+
+// RefreshPeriodically refreshes the users and channels caches every cache TTL until ctx is done, merging
+// changes or, once SLACK_MCP_CACHE_RECONCILE_INTERVAL has passed, downloading them whole. A TTL of 0 keeps
+// the caches forever and makes this return at once.
+func (ap *ApiProvider) RefreshPeriodically(ctx context.Context) {
+	// An offline archive never changes
+	if _, offline := ap.client.(*OfflineClient); ap.cacheTTL == 0 || offline {
+		return
+	}
+	ticker := time.NewTicker(ap.cacheTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := ap.RefreshUsers(ctx); err != nil && ctx.Err() == nil {
+			ap.logger.Error("Failed to refresh users cache", zap.Error(err))
+		}
+		if err := ap.RefreshChannels(ctx); err != nil && ctx.Err() == nil {
+			ap.logger.Error("Failed to refresh channels cache", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// deltaClient answers the list calls of the cache refreshes and counts them
+type deltaClient struct {
+	SlackAPI
+	users         []slack.User
+	channels      []slack.Channel // conversations.list
+	userChannels  []slack.Channel // users.conversations
+	listCalls     int
+	userListCalls int
+}
+
+func (c *deltaClient) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error) {
+	return c.users, nil
+}
+
+func (c *deltaClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	c.listCalls++
+	return c.channels, "", nil
+}
+
+func (c *deltaClient) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	c.userListCalls++
+	return c.userChannels, "", nil
+}
+
+func testChannel(id, name string) slack.Channel {
+	c := slack.Channel{}
+	c.ID = id
+	c.Name = name
+	return c
+}
+
+func newDeltaProvider(t *testing.T, client *deltaClient) *ApiProvider {
+	s, err := openBoltStore(filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	ap := &ApiProvider{
+		client:            client,
+		logger:            zap.NewNop(),
+		rateLimiter:       rate.NewLimiter(rate.Inf, 1),
+		cacheTTL:          time.Hour,
+		reconcileInterval: 24 * time.Hour,
+		store:             s,
+	}
+	ap.usersSnapshot.Store(&UsersCache{Users: map[string]slack.User{}, UsersInv: map[string]string{}})
+	ap.channelsSnapshot.Store(&ChannelsCache{Channels: map[string]Channel{}, ChannelsInv: map[string]string{}})
+	return ap
+}
+
+func TestDeltaRefreshChannels(t *testing.T) {
+	client := &deltaClient{channels: []slack.Channel{testChannel("C1", "general"), testChannel("C2", "random")}}
+	ap := newDeltaProvider(t, client)
+	ctx := context.Background()
+
+	require.NoError(t, ap.RefreshChannels(ctx))
+	assert.Equal(t, 1, client.listCalls, "the first refresh is a full one")
+	assert.Equal(t, "C2", ap.ProvideChannelsMaps().ChannelsInv["#random"])
+
+	t.Run("forced refresh only lists the user's conversations", func(t *testing.T) {
+		client.userChannels = []slack.Channel{testChannel("C1", "announcements"), testChannel("C3", "incidents")}
+		require.NoError(t, ap.ForceRefreshChannels(ctx, "#incidents"))
+		assert.Equal(t, 1, client.listCalls)
+		assert.Equal(t, 1, client.userListCalls)
+
+		snapshot := ap.ProvideChannelsMaps()
+		assert.Equal(t, "C1", snapshot.ChannelsInv["#announcements"])
+		assert.NotContains(t, snapshot.ChannelsInv, "#general")
+		assert.Equal(t, "C3", snapshot.ChannelsInv["#incidents"])
+		assert.Equal(t, "C2", snapshot.ChannelsInv["#random"], "channels the user is not in are kept")
+
+		c, ok, err := ap.store.ChannelByName("#incidents")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "C3", c.ID)
+	})
+
+	t.Run("fresh cache is not refreshed", func(t *testing.T) {
+		require.NoError(t, ap.RefreshChannels(ctx))
+		assert.Equal(t, 1, client.listCalls)
+		assert.Equal(t, 1, client.userListCalls)
+	})
+
+	t.Run("expired cache merges changes", func(t *testing.T) {
+		ap.cacheTTL = time.Nanosecond
+		require.NoError(t, ap.RefreshChannels(ctx))
+		assert.Equal(t, 1, client.listCalls)
+		assert.Equal(t, 2, client.userListCalls)
+	})
+
+	t.Run("reconcile drops deleted channels", func(t *testing.T) {
+		ap.reconcileInterval = time.Nanosecond
+		client.channels = []slack.Channel{testChannel("C1", "announcements")}
+		require.NoError(t, ap.RefreshChannels(ctx))
+		assert.Equal(t, 2, client.listCalls)
+		assert.NotContains(t, ap.ProvideChannelsMaps().Channels, "C2")
+	})
+}
+
+func TestForceRefreshChannelsMisses(t *testing.T) {
+	client := &deltaClient{channels: []slack.Channel{testChannel("C1", "general"), testChannel("C2", "random")}}
+	ap := newDeltaProvider(t, client)
+	ctx := context.Background()
+	require.NoError(t, ap.RefreshChannels(ctx))
+
+	t.Run("archived conversations are marked", func(t *testing.T) {
+		archived := testChannel("C2", "random")
+		archived.IsArchived = true
+		unknown := testChannel("C7", "old-project")
+		unknown.IsArchived = true
+		client.userChannels = []slack.Channel{testChannel("C1", "general"), archived, unknown}
+
+		require.NoError(t, ap.ForceRefreshChannels(ctx, ""))
+		assert.Equal(t, 1, client.listCalls, "no name was missed")
+		snapshot := ap.ProvideChannelsMaps()
+		assert.True(t, snapshot.Channels["C2"].IsArchived)
+		assert.NotContains(t, snapshot.Channels, "C7", "archived conversations are not added")
+		c, ok, err := ap.store.ChannelByID("C2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, c.IsArchived)
+	})
+
+	t.Run("a channel the user hasn't joined falls back to the full list", func(t *testing.T) {
+		client.channels = append(client.channels, testChannel("C4", "design"))
+		require.NoError(t, ap.ForceRefreshChannels(ctx, "#design"))
+		assert.Equal(t, 2, client.userListCalls)
+		assert.Equal(t, 2, client.listCalls)
+		assert.Equal(t, "C4", ap.ProvideChannelsMaps().ChannelsInv["#design"])
+	})
+
+	t.Run("a channel among the user's conversations does not", func(t *testing.T) {
+		client.userChannels = append(client.userChannels, testChannel("C5", "ops"))
+		require.NoError(t, ap.ForceRefreshChannels(ctx, "#ops"))
+		assert.Equal(t, 3, client.userListCalls)
+		assert.Equal(t, 2, client.listCalls)
+		assert.Equal(t, "C5", ap.ProvideChannelsMaps().ChannelsInv["#ops"])
+	})
+}
+
+func TestDeltaRefreshUsers(t *testing.T) {
+	alice := testUser("U1", "alice", "alice@example.com")
+	alice.Updated = 100
+	bob := testUser("U2", "bob", "")
+	bob.Updated = 100
+	client := &deltaClient{users: []slack.User{alice, bob}}
+	ap := newDeltaProvider(t, client)
+	ap.mergeUsers(client.users)
+	require.NoError(t, ap.store.ReplaceUsers(client.users))
+
+	renamed := testUser("U1", "alice.smith", "alice@example.com")
+	renamed.Updated = 200
+	stale := testUser("U2", "bob-from-an-old-page", "")
+	stale.Updated = 100
+	client.users = []slack.User{renamed, stale, testUser("U3", "carol", "")}
+
+	require.NoError(t, ap.ForceRefreshUsers(context.Background()))
+	snapshot := ap.ProvideUsersMap()
+	assert.Equal(t, "U1", snapshot.UsersInv["alice.smith"])
+	assert.NotContains(t, snapshot.UsersInv, "alice")
+	assert.Equal(t, "bob", snapshot.Users["U2"].Name, "users with the same updated time are left alone")
+	assert.Contains(t, snapshot.Users, "U3")
+
+	u, ok, err := ap.store.UserByName("alice.smith")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "U1", u.ID)
+}
+
+// recordsStore counts the calls that read every record of a cache
+type recordsStore struct {
+	CacheStore
+	reads int
+}
+
+func (s *recordsStore) Users() ([]slack.User, CacheState, error) {
+	s.reads++
+	return s.CacheStore.Users()
+}
+
+func (s *recordsStore) Channels() ([]Channel, CacheState, error) {
+	s.reads++
+	return s.CacheStore.Channels()
+}
+
+func TestRefreshReadsOnlyCacheState(t *testing.T) {
+	client := &deltaClient{
+		users:        []slack.User{testUser("U1", "alice", "")},
+		channels:     []slack.Channel{testChannel("C1", "general")},
+		userChannels: []slack.Channel{testChannel("C1", "general")},
+	}
+	ap := newDeltaProvider(t, client)
+	ctx := context.Background()
+	ap.mergeUsers(client.users)
+	require.NoError(t, ap.store.ReplaceUsers(client.users))
+	require.NoError(t, ap.RefreshChannels(ctx))
+
+	store := &recordsStore{CacheStore: ap.store}
+	ap.store = store
+	require.NoError(t, ap.RefreshUsers(ctx))
+	require.NoError(t, ap.RefreshChannels(ctx))
+	require.NoError(t, ap.ForceRefreshUsers(ctx))
+	require.NoError(t, ap.ForceRefreshChannels(ctx, ""))
+	assert.Zero(t, store.reads, "the caches are in memory already")
+	assert.Equal(t, 1, client.listCalls)
+	assert.Equal(t, 1, client.userListCalls)
+}
+
+func TestGetCacheReconcileInterval(t *testing.T) {
+	tests := []struct {
+		envValue string
+		expected time.Duration
+	}{
+		{"", defaultCacheReconcileInterval},
+		{"6h", 6 * time.Hour},
+		{"3600", time.Hour},
+		{"0", 0},
+		{"-1h", defaultCacheReconcileInterval},
+		{"invalid", defaultCacheReconcileInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.envValue, func(t *testing.T) {
+			t.Setenv("SLACK_MCP_CACHE_RECONCILE_INTERVAL", tt.envValue)
+			assert.Equal(t, tt.expected, getCacheReconcileInterval())
+		})
+	}
+}
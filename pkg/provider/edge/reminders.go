@@ -0,0 +1,44 @@
+package edge
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// remindersCompleteForm is the request to reminders.complete
+type remindersCompleteForm struct {
+	BaseRequest
+	Reminder string `json:"reminder"`
+	WebClientFields
+}
+
+type remindersCompleteResponse struct {
+	baseResponse
+}
+
+// CompleteReminderContext marks a reminder as complete. slack-go has no
+// wrapper for reminders.complete, so it is posted to the workspace API here.
+func (cl *Client) CompleteReminderContext(ctx context.Context, id string) error {
+	ctx, task := trace.NewTask(ctx, "CompleteReminderContext")
+	defer task.End()
+	trace.Logf(ctx, "params", "reminder=%v", id)
+
+	form := remindersCompleteForm{
+		BaseRequest: BaseRequest{
+			Token: cl.token,
+		},
+		Reminder:        id,
+		WebClientFields: webclientReason("completeReminder"),
+	}
+
+	resp, err := cl.PostForm(ctx, "reminders.complete", values(form, true))
+	if err != nil {
+		return err
+	}
+
+	var r remindersCompleteResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return err
+	}
+	return r.validate("reminders.complete")
+}
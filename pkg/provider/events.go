@@ -0,0 +1,407 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+const defaultEventBufferSize = 1000
+
+// Event is a Slack event as kept in the event buffer. Only the fields tools need are kept; Text is empty
+// for events without a message.
+type Event struct {
+	Seq      uint64    `json:"seq"`
+	Type     string    `json:"type"`
+	Subtype  string    `json:"subtype,omitempty"`
+	Team     string    `json:"team,omitempty"`
+	Channel  string    `json:"channel,omitempty"`
+	User     string    `json:"user,omitempty"`
+	Ts       string    `json:"ts,omitempty"`
+	ThreadTs string    `json:"thread_ts,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Reaction string    `json:"reaction,omitempty"`
+	Received time.Time `json:"received"`
+}
+
+// EventBuffer keeps the most recent events in memory. When full, the oldest event is dropped.
+type EventBuffer struct {
+	mu     sync.Mutex
+	events []Event
+	start  int // index of the oldest event
+	count  int
+	seq    uint64
+	added  chan struct{} // closed and replaced by Add
+	live   bool
+}
+
+func NewEventBuffer(size int) *EventBuffer {
+	if size <= 0 {
+		size = defaultEventBufferSize
+	}
+	return &EventBuffer{events: make([]Event, size), added: make(chan struct{})}
+}
+
+// getEventBufferSize returns SLACK_MCP_EVENT_BUFFER_SIZE or the default (1000 events)
+func getEventBufferSize() int {
+	if size, err := strconv.Atoi(os.Getenv("SLACK_MCP_EVENT_BUFFER_SIZE")); err == nil && size > 0 {
+		return size
+	}
+	return defaultEventBufferSize
+}
+
+// Add stores an event, numbering it after the previous one
+func (b *EventBuffer) Add(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.Seq = b.seq
+	if event.Received.IsZero() {
+		event.Received = time.Now()
+	}
+
+	if b.count < len(b.events) {
+		b.events[(b.start+b.count)%len(b.events)] = event
+		b.count++
+	} else {
+		b.events[b.start] = event
+		b.start = (b.start + 1) % len(b.events)
+	}
+	close(b.added)
+	b.added = make(chan struct{})
+	return event
+}
+
+// Added returns a channel that is closed when the next event is added. Take it before reading the
+// buffer, so an event added in between is not missed.
+func (b *EventBuffer) Added() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.added
+}
+
+// SetLive records whether events are being delivered, i.e. Socket Mode is connected or the Events API
+// endpoint is served
+func (b *EventBuffer) SetLive(live bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.live = live
+}
+
+// Live reports whether new events can be expected. Without them, callers have to poll Slack.
+func (b *EventBuffer) Live() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.live
+}
+
+// Since returns the buffered events numbered after seq, oldest first
+func (b *EventBuffer) Since(seq uint64) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var events []Event
+	for i := 0; i < b.count; i++ {
+		event := b.events[(b.start+i)%len(b.events)]
+		if event.Seq > seq {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Recent returns up to n of the latest events, oldest first
+func (b *EventBuffer) Recent(n int) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.count {
+		n = b.count
+	}
+	events := make([]Event, 0, n)
+	for i := b.count - n; i < b.count; i++ {
+		events = append(events, b.events[(b.start+i)%len(b.events)])
+	}
+	return events
+}
+
+// LastSeq returns the number of the latest event, 0 before the first one
+func (b *EventBuffer) LastSeq() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.seq
+}
+
+// Events returns the buffer of events received over Socket Mode or the Events API
+func (ap *ApiProvider) Events() *EventBuffer {
+	return ap.events
+}
+
+// TeamIDs returns the workspaces the provider's token acts in: its own team and, on Enterprise Grid, the
+// other workspaces of the organization the user belongs to. It is empty without a connected client.
+func (ap *ApiProvider) TeamIDs() []string {
+	client, ok := ap.client.(*MCPSlackClient)
+	if !ok || client == nil || client.AuthResponse() == nil {
+		return nil
+	}
+	return append([]string{client.AuthResponse().TeamID}, client.GetWorkspaceTeams()...)
+}
+
+// EventRouter passes each Events API event to the provider of the workspace it comes from. A Slack app
+// installed in several workspaces delivers all of their events over one Socket Mode connection or
+// Request URL. Events of workspaces no provider acts in are dropped.
+type EventRouter struct {
+	providers []*ApiProvider // the default workspace's first
+	logger    *zap.Logger
+}
+
+func NewEventRouter(logger *zap.Logger, providers ...*ApiProvider) *EventRouter {
+	return &EventRouter{providers: providers, logger: logger}
+}
+
+// Provider returns the provider events of teamID are applied to, or nil. A provider's own team takes
+// precedence over the Enterprise Grid workspaces another provider can also reach.
+func (r *EventRouter) Provider(teamID string) *ApiProvider {
+	if teamID == "" {
+		return nil
+	}
+	var reachable *ApiProvider
+	for _, ap := range r.providers {
+		for i, id := range ap.TeamIDs() {
+			if id != teamID {
+				continue
+			}
+			if i == 0 {
+				return ap
+			}
+			if reachable == nil {
+				reachable = ap
+			}
+		}
+	}
+	return reachable
+}
+
+// ApplyEvent applies an event to the provider of its workspace
+func (r *EventRouter) ApplyEvent(event slackevents.EventsAPIEvent) {
+	ap := r.Provider(event.TeamID)
+	if ap == nil {
+		r.logger.Debug("Dropping Slack event of an unknown workspace",
+			zap.String("team_id", event.TeamID),
+			zap.String("type", event.InnerEvent.Type),
+		)
+		return
+	}
+	ap.ApplyEvent(event)
+}
+
+// SetLive marks the event buffers of every provider as receiving events or not
+func (r *EventRouter) SetLive(live bool) {
+	for _, ap := range r.providers {
+		if events := ap.Events(); events != nil {
+			events.SetLive(live)
+		}
+	}
+}
+
+// OnEvent registers a function called with every event ApplyEvent records, after the caches are
+// updated. Listeners run on the goroutine delivering the event and must not block.
+func (ap *ApiProvider) OnEvent(listener func(Event)) {
+	ap.eventListenersMu.Lock()
+	defer ap.eventListenersMu.Unlock()
+	ap.eventListeners = append(ap.eventListeners, listener)
+}
+
+// ApplyEvent updates the caches from an Events API event and records it in the event buffer. The event
+// must come from the provider's workspace; EventRouter picks the provider for an event. Channel
+// creations, renames and joins are applied to the channels cache, user changes and new members to the
+// users cache. Events the caches and tools don't use are ignored.
+func (ap *ApiProvider) ApplyEvent(event slackevents.EventsAPIEvent) {
+	if event.Type != slackevents.CallbackEvent {
+		return
+	}
+
+	recorded := Event{Type: event.InnerEvent.Type, Team: event.TeamID}
+	switch ev := event.InnerEvent.Data.(type) {
+	case *slackevents.MessageEvent:
+		recorded.Subtype = ev.SubType
+		recorded.Channel = ev.Channel
+		recorded.User = ev.User
+		recorded.Ts = ev.TimeStamp
+		recorded.ThreadTs = ev.ThreadTimeStamp
+		recorded.Text = ev.Text
+		// Edits carry the new message separately
+		if ev.Message != nil && ev.SubType == "message_changed" {
+			recorded.User = ev.Message.User
+			recorded.Ts = ev.Message.Timestamp
+			recorded.ThreadTs = ev.Message.ThreadTimestamp
+			recorded.Text = ev.Message.Text
+		}
+		ap.archiveMessageEvent(ev)
+	case *slackevents.ReactionAddedEvent:
+		recorded.Channel = ev.Item.Channel
+		recorded.User = ev.User
+		recorded.Ts = ev.Item.Timestamp
+		recorded.Reaction = ev.Reaction
+	case *slackevents.ReactionRemovedEvent:
+		recorded.Channel = ev.Item.Channel
+		recorded.User = ev.User
+		recorded.Ts = ev.Item.Timestamp
+		recorded.Reaction = ev.Reaction
+	case *slackevents.ChannelCreatedEvent:
+		channel := slack.Channel{}
+		channel.ID = ev.Channel.ID
+		channel.Name = ev.Channel.Name
+		channel.NameNormalized = ev.Channel.Name
+		channel.Creator = ev.Channel.Creator
+		channel.Created = slack.JSONTime(ev.Channel.Created)
+		ap.UpdateChannel(channel)
+		recorded.Channel = ev.Channel.ID
+		recorded.User = ev.Channel.Creator
+	case *slackevents.ChannelRenameEvent:
+		ap.RenameChannel(ev.Channel.ID, ev.Channel.Name)
+		recorded.Channel = ev.Channel.ID
+	case *slackevents.MemberJoinedChannelEvent:
+		ap.AddChannelMember(ev.Channel, ev.User)
+		recorded.Channel = ev.Channel
+		recorded.User = ev.User
+	case *slackevents.UserChangeEvent:
+		// slackevents has its own user type; both follow the users.info JSON
+		var user slack.User
+		data, err := json.Marshal(ev.User)
+		if err == nil {
+			err = json.Unmarshal(data, &user)
+		}
+		if err != nil {
+			ap.logger.Warn("Failed to read user_change event", zap.Error(err))
+			return
+		}
+		ap.UpdateUser(user)
+		recorded.User = user.ID
+	case *slackevents.TeamJoinEvent:
+		if ev.User == nil {
+			return
+		}
+		ap.UpdateUser(*ev.User)
+		recorded.User = ev.User.ID
+	default:
+		return
+	}
+
+	if ap.events != nil {
+		recorded = ap.events.Add(recorded)
+	}
+	ap.eventListenersMu.RLock()
+	listeners := ap.eventListeners
+	ap.eventListenersMu.RUnlock()
+	for _, listener := range listeners {
+		listener(recorded)
+	}
+	ap.logger.Debug("Applied Slack event",
+		zap.String("type", recorded.Type),
+		zap.String("channel", recorded.Channel),
+		zap.String("user", recorded.User),
+	)
+}
+
+// archiveMessageEvent records new and edited messages in the message archive and drops deleted ones
+func (ap *ApiProvider) archiveMessageEvent(ev *slackevents.MessageEvent) {
+	if ap.archive == nil {
+		return
+	}
+	switch ev.SubType {
+	case "message_deleted":
+		if err := ap.archive.Delete(ev.Channel, ev.DeletedTimeStamp); err != nil {
+			ap.logger.Warn("Failed to delete archived message",
+				zap.String("channel", ev.Channel),
+				zap.String("ts", ev.DeletedTimeStamp),
+				zap.Error(err))
+		}
+		return
+	case "message_replied":
+		// Only the reply count of the parent changed
+		return
+	}
+
+	// The event unmarshaler fills Message for new messages too
+	msg := slack.Message{}
+	if ev.Message != nil {
+		msg.Msg = *ev.Message
+	}
+	if msg.Timestamp == "" {
+		msg.Timestamp = ev.TimeStamp
+		msg.ThreadTimestamp = ev.ThreadTimeStamp
+		msg.User = ev.User
+		msg.BotID = ev.BotID
+		msg.Username = ev.Username
+		msg.Text = ev.Text
+	}
+	ap.ArchiveMessages(ArchivedFromMessage(ev.Channel, msg))
+}
+
+// RenameChannel changes the name of a cached channel. Unknown channels are left to the next refresh.
+func (ap *ApiProvider) RenameChannel(channelID, name string) {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	ap.modifyChannel(channelID, func(prev Channel, exists bool) (Channel, bool) {
+		if !exists || prev.IsIM || prev.IsMpIM {
+			return prev, false
+		}
+		prev.Name = "#" + name
+		prev.NameNormalized = name
+		return prev, true
+	})
+}
+
+// AddChannelMember adds a user to the members of a cached channel
+func (ap *ApiProvider) AddChannelMember(channelID, userID string) {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	ap.modifyChannel(channelID, func(prev Channel, exists bool) (Channel, bool) {
+		if !exists {
+			return prev, false
+		}
+		for _, member := range prev.Members {
+			if member == userID {
+				return prev, false
+			}
+		}
+		// Members is shared with the previous snapshot
+		members := make([]string, 0, len(prev.Members)+1)
+		members = append(members, prev.Members...)
+		prev.Members = append(members, userID)
+		prev.MemberCount++
+		return prev, true
+	})
+}
+
+// UpdateUser replaces or adds one user in the users snapshot and writes the user's record to the cache
+// store. The JSON store does not rewrite its file for profile changes; the next refresh persists them.
+func (ap *ApiProvider) UpdateUser(user slack.User) {
+	if user.ID == "" {
+		return
+	}
+
+	ap.usersMu.Lock()
+	defer ap.usersMu.Unlock()
+
+	ap.mergeUsers([]slack.User{user})
+
+	if ap.store != nil {
+		if err := ap.store.PutUser(user); err != nil {
+			ap.logger.Error("Failed to write user to cache",
+				zap.String("user", user.ID),
+				zap.String("cache_file", ap.store.Location()),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,793 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/rusq/slackdump/v3/source"
+	"github.com/slack-go/slack"
+)
+
+// ErrOffline is returned by the SlackAPI methods an offline archive cannot serve, such as posting
+var ErrOffline = errors.New("not available from an offline archive")
+
+// offlineWorkspaceURL is used for permalinks when the archive does not name its workspace; Slack
+// redirects it to the workspace of the signed-in user
+const offlineWorkspaceURL = "https://slack.com/"
+
+// OfflineClient serves the read part of SlackAPI from a Slack export ZIP or a slackdump archive
+// directory, read with slackdump's source package: history, threads, users, channels, file metadata and
+// search. Everything else fails with ErrOffline.
+type OfflineClient struct {
+	src      source.SourceResumeCloser
+	auth     slack.AuthTestResponse
+	users    []slack.User
+	channels []slack.Channel
+
+	// the channel read last, as history and thread reads page through one channel at a time
+	mu             sync.Mutex
+	cachedChannel  string
+	cachedMessages []slack.Message // messages and replies, oldest first
+
+	// search and file lookups use an index of every channel, built by the first of them
+	indexMu  sync.Mutex
+	indexDir string
+	index    *MessageArchive
+	files    map[string]slack.File // file ID -> file
+}
+
+// OpenOfflineClient opens the archive at path. Messages are read when a channel is first used.
+func OpenOfflineClient(ctx context.Context, path string) (*OfflineClient, error) {
+	src, err := source.Load(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open offline archive %s: %w", path, err)
+	}
+	c := &OfflineClient{src: src}
+
+	if c.channels, err = fromArchive[[]slack.Channel](src.Channels(ctx)); err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to read channels of %s: %w", path, err)
+	}
+	if c.users, err = fromArchive[[]slack.User](src.Users(ctx)); err != nil && !errors.Is(err, source.ErrNotFound) {
+		src.Close()
+		return nil, fmt.Errorf("failed to read users of %s: %w", path, err)
+	}
+	if auth, err := fromArchive[*slack.AuthTestResponse](src.WorkspaceInfo(ctx)); err == nil && auth != nil {
+		c.auth = *auth
+	} else {
+		// Slack exports don't say which workspace they come from
+		c.auth.Team = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if c.auth.URL == "" {
+		c.auth.URL = offlineWorkspaceURL
+	}
+	return c, nil
+}
+
+// fromArchive converts the slackdump types of an archive to their slack-go equivalents. Both libraries
+// follow the Slack API JSON.
+func fromArchive[T, S any](v S, err error) (T, error) {
+	var out T
+	if err != nil {
+		return out, err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out, err
+	}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+func collect[M any](it iter.Seq2[M, error], err error) ([]M, error) {
+	if err != nil {
+		return nil, err
+	}
+	var out []M
+	for m, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// isMissing reports whether an archive has no data for a channel or thread
+func isMissing(err error) bool {
+	return errors.Is(err, source.ErrNotFound) || errors.Is(err, fs.ErrNotExist)
+}
+
+func (c *OfflineClient) Close() error {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+	var errs []error
+	if c.index != nil {
+		errs = append(errs, c.index.Close(), os.RemoveAll(c.indexDir))
+		c.index = nil
+	}
+	return errors.Join(append(errs, c.src.Close())...)
+}
+
+// isReply reports whether a message belongs to a thread without being its parent
+func isReply(msg slack.Message) bool {
+	return msg.ThreadTimestamp != "" && msg.ThreadTimestamp != msg.Timestamp
+}
+
+// channelMessages returns the messages of a channel with the replies of its threads, oldest first
+func (c *OfflineClient) channelMessages(ctx context.Context, channelID string) ([]slack.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cachedMessages != nil && c.cachedChannel == channelID {
+		return c.cachedMessages, nil
+	}
+	messages, err := c.readChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	c.cachedChannel, c.cachedMessages = channelID, messages
+	return messages, nil
+}
+
+// readChannel reads the messages of a channel and its threads from the archive
+func (c *OfflineClient) readChannel(ctx context.Context, channelID string) ([]slack.Message, error) {
+	if c.channel(channelID) == nil {
+		return nil, errors.New("channel_not_found")
+	}
+
+	byTs := make(map[string]slack.Message)
+	parents, err := fromArchive[[]slack.Message](collect(c.src.AllMessages(ctx, channelID)))
+	if err != nil && !isMissing(err) {
+		return nil, err
+	}
+	for _, msg := range parents {
+		byTs[msg.Timestamp] = msg
+		if msg.ReplyCount == 0 && msg.ThreadTimestamp != msg.Timestamp {
+			continue
+		}
+		replies, err := fromArchive[[]slack.Message](collect(c.src.AllThreadMessages(ctx, channelID, msg.Timestamp)))
+		if err != nil && !isMissing(err) {
+			return nil, err
+		}
+		for _, reply := range replies {
+			if reply.Timestamp == msg.Timestamp {
+				continue
+			}
+			if reply.ThreadTimestamp == "" {
+				reply.ThreadTimestamp = msg.Timestamp
+			}
+			byTs[reply.Timestamp] = reply
+		}
+	}
+
+	messages := make([]slack.Message, 0, len(byTs))
+	for _, msg := range byTs {
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return tsAfter(messages[j].Timestamp, messages[i].Timestamp)
+	})
+	return messages, nil
+}
+
+func (c *OfflineClient) channel(channelID string) *slack.Channel {
+	for i := range c.channels {
+		if c.channels[i].ID == channelID {
+			return &c.channels[i]
+		}
+	}
+	return nil
+}
+
+func (c *OfflineClient) user(userID string) *slack.User {
+	for i := range c.users {
+		if c.users[i].ID == userID {
+			return &c.users[i]
+		}
+	}
+	return nil
+}
+
+// inRange reports whether ts is within oldest and latest, which are exclusive unless inclusive is set.
+// Empty bounds are open.
+func inRange(ts, oldest, latest string, inclusive bool) bool {
+	if oldest != "" && !tsAfter(ts, oldest) && !(inclusive && ts == oldest) {
+		return false
+	}
+	if latest != "" && !tsAfter(latest, ts) && !(inclusive && ts == latest) {
+		return false
+	}
+	return true
+}
+
+// GetConversationHistoryContext returns the messages of a channel newest first. Replies are left out,
+// except those also sent to the channel. The cursor is the ts of the last message returned.
+func (c *OfflineClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	messages, err := c.channelMessages(ctx, params.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	latest := params.Latest
+	if params.Cursor != "" {
+		latest = params.Cursor
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	resp := &slack.GetConversationHistoryResponse{}
+	resp.Ok = true
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if isReply(msg) && msg.SubType != slack.MsgSubTypeThreadBroadcast {
+			continue
+		}
+		if !inRange(msg.Timestamp, params.Oldest, latest, params.Inclusive && params.Cursor == "") {
+			continue
+		}
+		if len(resp.Messages) == limit {
+			resp.HasMore = true
+			resp.ResponseMetaData.NextCursor = resp.Messages[limit-1].Timestamp
+			break
+		}
+		resp.Messages = append(resp.Messages, msg)
+	}
+	return resp, nil
+}
+
+// GetConversationRepliesContext returns a thread oldest first, its parent first on every page. The
+// cursor is the ts of the last reply returned.
+func (c *OfflineClient) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	messages, err := c.channelMessages(ctx, params.ChannelID)
+	if err != nil {
+		return nil, false, "", err
+	}
+	oldest := params.Oldest
+	if params.Cursor != "" {
+		oldest = params.Cursor
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	var (
+		parent  *slack.Message
+		replies []slack.Message
+		hasMore bool
+	)
+	for i, msg := range messages {
+		if msg.Timestamp == params.Timestamp {
+			parent = &messages[i]
+			continue
+		}
+		if msg.ThreadTimestamp != params.Timestamp || !inRange(msg.Timestamp, oldest, params.Latest, params.Inclusive && params.Cursor == "") {
+			continue
+		}
+		if len(replies) == limit {
+			hasMore = true
+			break
+		}
+		replies = append(replies, msg)
+	}
+	if parent == nil {
+		return nil, false, "", errors.New("thread_not_found")
+	}
+
+	var nextCursor string
+	if hasMore {
+		nextCursor = replies[len(replies)-1].Timestamp
+	}
+	return append([]slack.Message{*parent}, replies...), hasMore, nextCursor, nil
+}
+
+func (c *OfflineClient) AuthTest() (*slack.AuthTestResponse, error) {
+	auth := c.auth
+	return &auth, nil
+}
+
+func (c *OfflineClient) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	return c.AuthTest()
+}
+
+func (c *OfflineClient) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error) {
+	return c.users, nil
+}
+
+// GetUsersInfo takes user IDs, also comma-separated as users.info does
+func (c *OfflineClient) GetUsersInfo(users ...string) (*[]slack.User, error) {
+	var found []slack.User
+	for _, ids := range users {
+		for _, id := range strings.Split(ids, ",") {
+			if u := c.user(strings.TrimSpace(id)); u != nil {
+				found = append(found, *u)
+			}
+		}
+	}
+	return &found, nil
+}
+
+func (c *OfflineClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
+	if u := c.user(user); u != nil {
+		found := *u
+		return &found, nil
+	}
+	return nil, errors.New("user_not_found")
+}
+
+// UsersSearch matches query against the names and email of the archived users, ignoring case
+func (c *OfflineClient) UsersSearch(ctx context.Context, query string, count int) ([]slack.User, error) {
+	query = strings.ToLower(query)
+	var found []slack.User
+	for _, u := range c.users {
+		for _, field := range []string{u.Name, u.RealName, u.Profile.DisplayName, u.Profile.Email} {
+			if field != "" && strings.Contains(strings.ToLower(field), query) {
+				found = append(found, u)
+				break
+			}
+		}
+		if count > 0 && len(found) == count {
+			break
+		}
+	}
+	return found, nil
+}
+
+// GetBotInfoContext finds a bot among the archived bot users
+func (c *OfflineClient) GetBotInfoContext(ctx context.Context, parameters slack.GetBotInfoParameters) (*slack.Bot, error) {
+	for _, u := range c.users {
+		if u.IsBot && u.Profile.BotID == parameters.Bot {
+			return &slack.Bot{ID: parameters.Bot, AppID: u.Profile.ApiAppID, Name: u.Name}, nil
+		}
+	}
+	return nil, errors.New("bot_not_found")
+}
+
+// conversations returns the archived channels of the given conversations.list types, all without types
+func (c *OfflineClient) conversations(types []string, excludeArchived bool) []slack.Channel {
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+	var channels []slack.Channel
+	for _, ch := range c.channels {
+		chType := "public_channel"
+		switch {
+		case ch.IsIM:
+			chType = "im"
+		case ch.IsMpIM:
+			chType = "mpim"
+		case ch.IsPrivate:
+			chType = "private_channel"
+		}
+		if (len(wanted) > 0 && !wanted[chType]) || (excludeArchived && ch.IsArchived) {
+			continue
+		}
+		channels = append(channels, ch)
+	}
+	return channels
+}
+
+func (c *OfflineClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	return c.conversations(params.Types, params.ExcludeArchived), "", nil
+}
+
+func (c *OfflineClient) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	return c.conversations(params.Types, params.ExcludeArchived), "", nil
+}
+
+func (c *OfflineClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	if ch := c.channel(input.ChannelID); ch != nil {
+		found := *ch
+		return &found, nil
+	}
+	return nil, errors.New("channel_not_found")
+}
+
+func (c *OfflineClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	if ch := c.channel(params.ChannelID); ch != nil {
+		return ch.Members, "", nil
+	}
+	return nil, "", errors.New("channel_not_found")
+}
+
+func (c *OfflineClient) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	return text.MessagePermalink(c.auth.URL, params.Channel, params.Ts, ""), nil
+}
+
+// searchIndex returns the archive of every message, in a temporary message archive searched like
+// search_local_messages, and the files attached to them. The first call reads every channel.
+func (c *OfflineClient) searchIndex(ctx context.Context) (*MessageArchive, map[string]slack.File, error) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+	if c.index != nil {
+		return c.index, c.files, nil
+	}
+
+	dir, err := os.MkdirTemp("", "slack-mcp-offline-")
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := OpenMessageArchive(filepath.Join(dir, "messages.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	files := make(map[string]slack.File)
+	for _, ch := range c.channels {
+		messages, err := c.readChannel(ctx, ch.ID)
+		if err == nil {
+			archived := make([]ArchivedMessage, 0, len(messages))
+			for _, msg := range messages {
+				archived = append(archived, ArchivedFromMessage(ch.ID, msg))
+				for _, f := range msg.Files {
+					files[f.ID] = f
+				}
+			}
+			err = index.Put(archived...)
+		}
+		if err != nil {
+			index.Close()
+			os.RemoveAll(dir)
+			return nil, nil, err
+		}
+	}
+	c.indexDir, c.index, c.files = dir, index, files
+	return index, files, nil
+}
+
+func (c *OfflineClient) GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
+	_, files, err := c.searchIndex(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	f, ok := files[fileID]
+	if !ok {
+		return nil, nil, nil, errors.New("file_not_found")
+	}
+	return &f, nil, &slack.Paging{}, nil
+}
+
+// GetFileContext copies a file to writer when the archive includes its content
+func (c *OfflineClient) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
+	_, files, err := c.searchIndex(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.URLPrivateDownload != downloadURL && f.URLPrivate != downloadURL {
+			continue
+		}
+		name, err := c.src.Files().File(f.ID, f.Name)
+		if err != nil {
+			return fmt.Errorf("file %s is not included in the archive: %w", f.ID, err)
+		}
+		r, err := c.src.Files().FS().Open(name)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		_, err = io.Copy(writer, r)
+		return err
+	}
+	return errors.New("file_not_found")
+}
+
+// resolveConversation turns #name, <#C...|name>, @user and <@U...> (direct messages) and IDs into a
+// conversation ID
+func (c *OfflineClient) resolveConversation(raw string) (string, error) {
+	if strings.HasPrefix(raw, "<#") && strings.HasSuffix(raw, ">") {
+		id, _, _ := strings.Cut(raw[2:len(raw)-1], "|")
+		return id, nil
+	}
+	if strings.HasPrefix(raw, "@") || strings.HasPrefix(raw, "<@") {
+		userID, err := c.resolveUser(raw)
+		if err != nil {
+			return "", err
+		}
+		for _, ch := range c.channels {
+			if ch.IsIM && ch.User == userID {
+				return ch.ID, nil
+			}
+		}
+		return "", fmt.Errorf("no direct messages with %q in the archive", raw)
+	}
+	name := strings.TrimLeft(raw, "#")
+	for _, ch := range c.channels {
+		if ch.ID == raw || (ch.Name != "" && ch.Name == name) {
+			return ch.ID, nil
+		}
+	}
+	return "", fmt.Errorf("channel %q not found in the archive", raw)
+}
+
+// resolveUser turns @name, <@U...> mentions and IDs into a user ID
+func (c *OfflineClient) resolveUser(raw string) (string, error) {
+	if strings.HasPrefix(raw, "<@") && strings.HasSuffix(raw, ">") {
+		id, _, _ := strings.Cut(raw[2:len(raw)-1], "|")
+		return id, nil
+	}
+	name := strings.TrimPrefix(raw, "@")
+	for _, u := range c.users {
+		if u.ID == name || u.Name == name {
+			return u.ID, nil
+		}
+	}
+	return "", fmt.Errorf("user %q not found in the archive", raw)
+}
+
+// SearchContext searches every archived channel with the words and filters search_local_messages
+// supports. Matches are sorted by time, newest first unless params asks for ascending timestamps; there
+// is no relevance score.
+func (c *OfflineClient) SearchContext(ctx context.Context, query string, params slack.SearchParameters) (*slack.SearchMessages, *slack.SearchFiles, error) {
+	words, filters := SplitSearchQuery(query)
+	q, err := NewMessageQuery(words, filters, c.resolveConversation, c.resolveUser)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, _, err := c.searchIndex(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	found, err := index.Search(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if params.Sort == "timestamp" && params.SortDirection == "asc" {
+		slices.Reverse(found)
+	}
+
+	perPage := params.Count
+	if perPage <= 0 {
+		perPage = slack.DEFAULT_SEARCH_COUNT
+	}
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+	total := len(found)
+	first := min((page-1)*perPage, total)
+	last := min(first+perPage, total)
+	pages := (total + perPage - 1) / perPage
+
+	matches := make([]slack.SearchMessage, 0, last-first)
+	for _, m := range found[first:last] {
+		match := slack.SearchMessage{
+			Type:      "message",
+			Channel:   slack.CtxChannel{ID: m.Channel},
+			User:      m.User,
+			Username:  m.Username,
+			Timestamp: m.Ts,
+			Text:      m.Text,
+			Permalink: text.MessagePermalink(c.auth.URL, m.Channel, m.Ts, m.ThreadTs),
+		}
+		if ch := c.channel(m.Channel); ch != nil {
+			match.Channel = slack.CtxChannel{ID: ch.ID, Name: ch.Name, IsPrivate: ch.IsPrivate, IsMPIM: ch.IsMpIM}
+		}
+		matches = append(matches, match)
+	}
+
+	res := &slack.SearchMessages{
+		Matches:    matches,
+		Paging:     slack.Paging{Count: perPage, Total: total, Page: page, Pages: pages},
+		Pagination: slack.Pagination{TotalCount: total, Page: page, PerPage: perPage, PageCount: pages, First: first + 1, Last: last},
+		Total:      total,
+	}
+	return res, &slack.SearchFiles{}, nil
+}
+
+// Edge API methods have nothing to report offline
+
+func (c *OfflineClient) ClientUserBoot(ctx context.Context) (*edge.ClientUserBootResponse, error) {
+	return &edge.ClientUserBootResponse{}, nil
+}
+
+func (c *OfflineClient) ClientCounts(ctx context.Context) (edge.ClientCountsResponse, error) {
+	return edge.ClientCountsResponse{}, nil
+}
+
+func (c *OfflineClient) GetMutedChannels(ctx context.Context) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+// Methods that need Slack
+
+func (c *OfflineClient) PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error) {
+	return "", "", ErrOffline
+}
+
+func (c *OfflineClient) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error) {
+	return "", ErrOffline
+}
+
+func (c *OfflineClient) MarkConversationContext(ctx context.Context, channel, ts string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) ShareFilePublicURLContext(ctx context.Context, fileID string) (*slack.File, []slack.Comment, *slack.Paging, error) {
+	return nil, nil, nil, ErrOffline
+}
+
+func (c *OfflineClient) DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error) {
+	return "", "", ErrOffline
+}
+
+func (c *OfflineClient) UpdateMessageContext(ctx context.Context, channel, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	return "", "", "", ErrOffline
+}
+
+func (c *OfflineClient) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	return "", "", ErrOffline
+}
+
+func (c *OfflineClient) GetScheduledMessagesContext(ctx context.Context, params *slack.GetScheduledMessagesParameters) ([]slack.ScheduledMessage, string, error) {
+	return nil, "", ErrOffline
+}
+
+func (c *OfflineClient) DeleteScheduledMessageContext(ctx context.Context, params *slack.DeleteScheduledMessageParameters) (bool, error) {
+	return false, ErrOffline
+}
+
+func (c *OfflineClient) AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error) {
+	return nil, nil, ErrOffline
+}
+
+func (c *OfflineClient) AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error) {
+	return slack.Bookmark{}, ErrOffline
+}
+
+func (c *OfflineClient) ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) UnsetUserCustomStatusContext(ctx context.Context) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) SetUserPresenceContext(ctx context.Context, presence string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) EndDNDContext(ctx context.Context) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) GetDNDInfoContext(ctx context.Context, user *string) (*slack.DNDStatus, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) CompleteReminderContext(ctx context.Context, id string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) DeleteReminderContext(ctx context.Context, id string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) CreateConversationContext(ctx context.Context, channelName string, isPrivate bool) (*slack.Channel, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) UnArchiveConversationContext(ctx context.Context, channelID string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	return ErrOffline
+}
+
+func (c *OfflineClient) JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error) {
+	return nil, "", nil, ErrOffline
+}
+
+func (c *OfflineClient) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	return false, ErrOffline
+}
+
+func (c *OfflineClient) GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) ([]slack.UserGroup, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	return nil, ErrOffline
+}
+
+func (c *OfflineClient) CreateUserGroupContext(ctx context.Context, userGroup slack.UserGroup, options ...slack.CreateUserGroupOption) (slack.UserGroup, error) {
+	return slack.UserGroup{}, ErrOffline
+}
+
+func (c *OfflineClient) UpdateUserGroupContext(ctx context.Context, userGroupID string, options ...slack.UpdateUserGroupsOption) (slack.UserGroup, error) {
+	return slack.UserGroup{}, ErrOffline
+}
+
+func (c *OfflineClient) UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (slack.UserGroup, error) {
+	return slack.UserGroup{}, ErrOffline
+}
+
+var _ SlackAPI = (*OfflineClient)(nil)
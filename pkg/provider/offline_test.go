@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// writeExport writes a small Slack export: #general with a thread and a file, and #random
+func writeExport(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"channels.json": `[
+			{"id": "C1", "name": "general", "members": ["U1", "U2"], "topic": {"value": "company-wide"}},
+			{"id": "C2", "name": "random", "is_archived": true}
+		]`,
+		"users.json": `[
+			{"id": "U1", "name": "alice", "real_name": "Alice Liddell", "profile": {"email": "alice@example.com"}},
+			{"id": "U2", "name": "bob", "real_name": "Bob Dobbs"},
+			{"id": "U3", "name": "deploybot", "is_bot": true, "profile": {"bot_id": "B1", "api_app_id": "A1"}}
+		]`,
+		"general/2023-11-14.json": `[
+			{"type": "message", "user": "U1", "text": "The db primary is down", "ts": "1699999200.000100",
+			 "thread_ts": "1699999200.000100", "reply_count": 2, "latest_reply": "1699999500.000100"},
+			{"type": "message", "user": "U2", "text": "failing over now", "ts": "1699999300.000100",
+			 "thread_ts": "1699999200.000100", "parent_user_id": "U1"},
+			{"type": "message", "user": "U1", "text": "Postmortem notes attached", "ts": "1699999400.000100",
+			 "files": [{"id": "F1", "name": "notes.txt", "title": "notes", "url_private": "https://files.slack.com/F1/notes.txt"}]},
+			{"type": "message", "user": "U2", "text": "primary is back", "ts": "1699999500.000100",
+			 "thread_ts": "1699999200.000100", "parent_user_id": "U1"}
+		]`,
+		"general/2023-11-15.json": `[
+			{"type": "message", "user": "U2", "text": "good morning", "ts": "1700038800.000100"}
+		]`,
+		"random/2023-11-15.json": `[
+			{"type": "message", "user": "U1", "text": "anyone seen the db dashboard?", "ts": "1700040000.000100"}
+		]`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+// writeDump writes a small slackdump dump: #general with a thread, and the workspace it came from
+func writeDump(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"channels.json":  `[{"id": "C1", "name": "general"}]`,
+		"users.json":     `[{"id": "U1", "name": "alice"}, {"id": "U2", "name": "bob"}]`,
+		"workspace.json": `{"url": "https://initech.slack.com/", "team": "Initech", "team_id": "T1"}`,
+		"C1.json": `{"channel_id": "C1", "name": "general", "messages": [
+			{"type": "message", "user": "U1", "text": "The db primary is down", "ts": "1699999200.000100",
+			 "thread_ts": "1699999200.000100", "reply_count": 1, "slackdump_thread_replies": [
+				{"type": "message", "user": "U2", "text": "failing over now", "ts": "1699999300.000100",
+				 "thread_ts": "1699999200.000100"}
+			]},
+			{"type": "message", "user": "U2", "text": "good morning", "ts": "1700038800.000100"}
+		]}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+// zipDir packs the files of dir into a ZIP next to it and returns its path
+func zipDir(t *testing.T, dir string) string {
+	path := filepath.Join(t.TempDir(), filepath.Base(dir)+".zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	require.NoError(t, zw.AddFS(os.DirFS(dir)))
+	require.NoError(t, zw.Close())
+	return path
+}
+
+func messageTs(messages []slack.Message) []string {
+	ts := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ts = append(ts, msg.Timestamp)
+	}
+	return ts
+}
+
+func TestOfflineClient(t *testing.T) {
+	ctx := context.Background()
+	c, err := OpenOfflineClient(ctx, writeExport(t))
+	require.NoError(t, err)
+	defer c.Close()
+
+	t.Run("channels and users", func(t *testing.T) {
+		channels, _, err := c.GetConversationsContext(ctx, &slack.GetConversationsParameters{Types: []string{"public_channel"}, ExcludeArchived: true})
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+		assert.Equal(t, "general", channels[0].Name)
+		assert.Equal(t, "company-wide", channels[0].Topic.Value)
+
+		users, err := c.GetUsersInfo("U1,U2", "U9")
+		require.NoError(t, err)
+		assert.Len(t, *users, 2)
+		found, err := c.UsersSearch(ctx, "LIDDELL", 10)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "U1", found[0].ID)
+		bot, err := c.GetBotInfoContext(ctx, slack.GetBotInfoParameters{Bot: "B1"})
+		require.NoError(t, err)
+		assert.Equal(t, "A1", bot.AppID)
+	})
+
+	t.Run("history pages newest first without replies", func(t *testing.T) {
+		resp, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C1", Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1700038800.000100", "1699999400.000100"}, messageTs(resp.Messages))
+		require.True(t, resp.HasMore)
+
+		resp, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C1", Limit: 2, Cursor: resp.ResponseMetaData.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1699999200.000100"}, messageTs(resp.Messages))
+		assert.False(t, resp.HasMore)
+
+		resp, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C1", Oldest: "1699999400.000100", Inclusive: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1700038800.000100", "1699999400.000100"}, messageTs(resp.Messages))
+
+		_, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C9"})
+		assert.EqualError(t, err, "channel_not_found")
+	})
+
+	t.Run("replies start with the parent", func(t *testing.T) {
+		params := &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: "1699999200.000100", Limit: 1}
+		messages, hasMore, cursor, err := c.GetConversationRepliesContext(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1699999200.000100", "1699999300.000100"}, messageTs(messages))
+		require.True(t, hasMore)
+
+		params.Cursor = cursor
+		messages, hasMore, _, err = c.GetConversationRepliesContext(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1699999200.000100", "1699999500.000100"}, messageTs(messages))
+		assert.False(t, hasMore)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		f, _, _, err := c.GetFileInfoContext(ctx, "F1", 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", f.Name)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		search := func(query string, params slack.SearchParameters) []string {
+			t.Helper()
+			res, _, err := c.SearchContext(ctx, query, params)
+			require.NoError(t, err)
+			ts := make([]string, 0, len(res.Matches))
+			for _, m := range res.Matches {
+				ts = append(ts, m.Timestamp)
+			}
+			return ts
+		}
+
+		assert.Equal(t, []string{"1700040000.000100", "1699999200.000100"}, search("DB", slack.SearchParameters{}))
+		assert.Equal(t, []string{"1699999200.000100"}, search("db in:#general", slack.SearchParameters{}))
+		assert.Equal(t, []string{"1699999300.000100", "1699999500.000100"},
+			search("from:@bob is:thread", slack.SearchParameters{Sort: "timestamp", SortDirection: "asc"}))
+		assert.Equal(t, []string{"1700038800.000100", "1700040000.000100"},
+			search("after:2023-11-14", slack.SearchParameters{Sort: "timestamp", SortDirection: "asc"}))
+		assert.Len(t, search("on:2023-11-14", slack.SearchParameters{}), 4)
+		assert.Len(t, search(`"primary" before:2023-11-15`, slack.SearchParameters{Count: 1, Page: 2}), 1)
+
+		res, _, err := c.SearchContext(ctx, "failing", slack.SearchParameters{})
+		require.NoError(t, err)
+		require.Len(t, res.Matches, 1)
+		assert.Equal(t, "general", res.Matches[0].Channel.Name)
+		assert.Contains(t, res.Matches[0].Permalink, "thread_ts=1699999200.000100")
+
+		_, _, err = c.SearchContext(ctx, "in:#nowhere", slack.SearchParameters{})
+		assert.Error(t, err)
+	})
+
+	t.Run("search matches as search_local_messages", func(t *testing.T) {
+		count := func(query string) int {
+			t.Helper()
+			res, _, err := c.SearchContext(ctx, query, slack.SearchParameters{})
+			require.NoError(t, err)
+			return res.Total
+		}
+		assert.Equal(t, 0, count("prim"), "words match whole")
+		assert.Equal(t, 2, count("prim*"))
+		assert.Equal(t, 4, count("on:11/14/2023"), "dates are read like the date filters")
+
+		_, _, err := c.SearchContext(ctx, "in:#general in:#random", slack.SearchParameters{})
+		assert.Error(t, err)
+	})
+
+	t.Run("writes fail", func(t *testing.T) {
+		_, _, err := c.PostMessageContext(ctx, "C1", slack.MsgOptionText("hi", false))
+		assert.ErrorIs(t, err, ErrOffline)
+	})
+}
+
+func TestOfflineClientFormats(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		path         string
+		workspaceURL string
+	}{
+		{"export zip", zipDir(t, writeExport(t)), offlineWorkspaceURL},
+		{"slackdump dump", writeDump(t), "https://initech.slack.com/"},
+		{"slackdump dump zip", zipDir(t, writeDump(t)), "https://initech.slack.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := OpenOfflineClient(ctx, tt.path)
+			require.NoError(t, err)
+			defer c.Close()
+
+			auth, err := c.AuthTest()
+			require.NoError(t, err)
+			assert.Equal(t, tt.workspaceURL, auth.URL)
+			users, err := c.GetUsersContext(ctx)
+			require.NoError(t, err)
+			assert.NotEmpty(t, users)
+
+			resp, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C1", Oldest: "1699999200.000100", Inclusive: true})
+			require.NoError(t, err)
+			assert.Contains(t, messageTs(resp.Messages), "1699999200.000100")
+			assert.NotContains(t, messageTs(resp.Messages), "1699999300.000100", "replies are not in the history")
+
+			messages, _, _, err := c.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: "1699999200.000100"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"1699999200.000100", "1699999300.000100"}, messageTs(messages)[:2])
+
+			res, _, err := c.SearchContext(ctx, "failing in:#general", slack.SearchParameters{})
+			require.NoError(t, err)
+			require.Len(t, res.Matches, 1)
+			assert.Equal(t, "1699999300.000100", res.Matches[0].Timestamp)
+			assert.True(t, strings.HasPrefix(res.Matches[0].Permalink, tt.workspaceURL))
+		})
+	}
+}
+
+func TestNewOffline(t *testing.T) {
+	t.Setenv("SLACK_MCP_OFFLINE_ARCHIVE", writeExport(t))
+	ctx := context.Background()
+	ap := New("stdio", zap.NewNop())
+	defer ap.Close()
+
+	require.NoError(t, ap.RefreshUsers(ctx))
+	require.NoError(t, ap.RefreshChannels(ctx))
+	require.NoError(t, ap.RefreshEmojis(ctx))
+	ready, err := ap.IsReady()
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	assert.Equal(t, "C1", ap.ProvideChannelsMaps().ChannelsInv["#general"])
+	assert.Equal(t, "alice", ap.ProvideUsersMap().Users["U1"].Name)
+	assert.Equal(t, offlineWorkspaceURL, ap.WorkspaceURL())
+	assert.Nil(t, ap.MessageArchive(), "offline archives are not archived again")
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+)
+
+var searchFilterKeys = map[string]struct{}{
+	"is":     {},
+	"in":     {},
+	"from":   {},
+	"with":   {},
+	"before": {},
+	"after":  {},
+	"on":     {},
+	"during": {},
+}
+
+// SplitSearchQuery splits a search.messages query into its words and its in:, from:, date and other
+// filters, by lower-cased key
+func SplitSearchQuery(q string) (words []string, filters map[string][]string) {
+	filters = make(map[string][]string)
+	for _, tok := range strings.Fields(q) {
+		parts := strings.SplitN(tok, ":", 2)
+		if _, ok := searchFilterKeys[strings.ToLower(parts[0])]; len(parts) == 2 && ok {
+			key := strings.ToLower(parts[0])
+			filters[key] = append(filters[key], parts[1])
+		} else {
+			words = append(words, tok)
+		}
+	}
+	return words, filters
+}
+
+// NewMessageQuery builds a query of the message archive from the words and filters of a search.messages
+// query. channel and user resolve the in: and from: filters to IDs. with: is not supported, since the
+// archive does not know who took part in a thread or DM.
+func NewMessageQuery(words []string, filters map[string][]string, channel, user func(string) (string, error)) (MessageQuery, error) {
+	query := MessageQuery{Text: strings.Join(words, " ")}
+	if len(filters["with"]) > 0 {
+		return query, errors.New("with: is not supported by the local message archive")
+	}
+	for _, is := range filters["is"] {
+		if !strings.EqualFold(is, "thread") {
+			return query, fmt.Errorf("is:%s is not supported by the local message archive, only is:thread", is)
+		}
+		query.ThreadsOnly = true
+	}
+	if len(filters["in"]) > 1 || len(filters["from"]) > 1 {
+		return query, errors.New("only one in: and one from: filter can be used")
+	}
+
+	var err error
+	if len(filters["in"]) == 1 {
+		if query.Channel, err = channel(filters["in"][0]); err != nil {
+			return query, err
+		}
+	}
+	if len(filters["from"]) == 1 {
+		if query.User, err = user(filters["from"][0]); err != nil {
+			return query, err
+		}
+	}
+	query.After, query.Before, err = SearchDateRange(filters)
+	return query, err
+}
+
+// SearchDateRange turns the date filters of a search query into the range of a MessageQuery. As in Slack
+// search, before: and after: exclude the day they name; on: is that day and during: its month.
+func SearchDateRange(filters map[string][]string) (after, before time.Time, err error) {
+	narrow := func(from, to time.Time) {
+		if !from.IsZero() && from.After(after) {
+			after = from
+		}
+		if !to.IsZero() && (before.IsZero() || to.Before(before)) {
+			before = to
+		}
+	}
+	for _, key := range []string{"before", "after", "on", "during"} {
+		for _, val := range filters[key] {
+			day, _, err := text.ParseFlexibleDate(val)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid '%s' date: %v", key, err)
+			}
+			switch key {
+			case "before":
+				narrow(time.Time{}, day)
+			case "after":
+				narrow(day.AddDate(0, 0, 1), time.Time{})
+			case "on":
+				narrow(day, day.AddDate(0, 0, 1))
+			case "during":
+				month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+				narrow(month, month.AddDate(0, 1, 0))
+			}
+		}
+	}
+	return after, before, nil
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDateRange(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string][]string
+		after   time.Time
+		before  time.Time
+	}{
+		{"none", map[string][]string{}, time.Time{}, time.Time{}},
+		{"before excludes the day", map[string][]string{"before": {"2024-03-10"}}, time.Time{}, day("2024-03-10")},
+		{"after excludes the day", map[string][]string{"after": {"2024-03-10"}}, day("2024-03-11"), time.Time{}},
+		{"on", map[string][]string{"on": {"2024-03-10"}}, day("2024-03-10"), day("2024-03-11")},
+		{"during is the month", map[string][]string{"during": {"March 2024"}}, day("2024-03-01"), day("2024-04-01")},
+		{"narrowest wins", map[string][]string{"after": {"2024-03-01", "2024-03-05"}, "before": {"2024-04-01", "2024-03-20"}}, day("2024-03-06"), day("2024-03-20")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after, before, err := SearchDateRange(tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.after, after)
+			assert.Equal(t, tt.before, before)
+		})
+	}
+
+	_, _, err := SearchDateRange(map[string][]string{"before": {"someday"}})
+	assert.Error(t, err)
+}
+
+func TestNewMessageQuery(t *testing.T) {
+	resolve := func(raw string) (string, error) {
+		if raw == "#nowhere" {
+			return "", errors.New("channel \"#nowhere\" not found")
+		}
+		return "ID(" + raw + ")", nil
+	}
+	build := func(q string) (MessageQuery, error) {
+		words, filters := SplitSearchQuery(q)
+		return NewMessageQuery(words, filters, resolve, resolve)
+	}
+
+	q, err := build(`deploy* "failed" in:#ops From:@alice is:thread on:2024-03-10`)
+	require.NoError(t, err)
+	assert.Equal(t, `deploy* "failed"`, q.Text)
+	assert.Equal(t, "ID(#ops)", q.Channel)
+	assert.Equal(t, "ID(@alice)", q.User)
+	assert.True(t, q.ThreadsOnly)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), q.After)
+
+	for _, bad := range []string{"with:@bob", "is:pinned", "in:#a in:#b", "in:#nowhere", "after:someday"} {
+		_, err := build(bad)
+		assert.Error(t, err, bad)
+	}
+}
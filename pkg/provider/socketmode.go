@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"go.uber.org/zap"
+)
+
+// SocketMode keeps a Socket Mode connection open with an app-level token (xapp-...) and applies the
+// events Slack pushes to the providers of their workspaces, so the caches follow the workspaces without
+// waiting for the TTL.
+// The app needs the connections:write scope and subscriptions to the message.*, reaction_added,
+// reaction_removed, channel_created, channel_rename, member_joined_channel, user_change and team_join
+// events.
+type SocketMode struct {
+	router *EventRouter
+	client *socketmode.Client
+	logger *zap.Logger
+}
+
+// NewSocketMode prepares a Socket Mode connection; Run opens it. Options are passed to the Slack client
+// used for apps.connections.open.
+func NewSocketMode(router *EventRouter, appToken string, logger *zap.Logger, opts ...slack.Option) (*SocketMode, error) {
+	if !strings.HasPrefix(appToken, "xapp-") {
+		return nil, errors.New("socket mode needs an app-level token (xapp-...)")
+	}
+
+	slackOpts := []slack.Option{slack.OptionAppLevelToken(appToken)}
+	if os.Getenv("SLACK_MCP_GOVSLACK") == "true" {
+		slackOpts = append(slackOpts, slack.OptionAPIURL("https://slack-gov.com/api/"))
+	}
+	slackOpts = append(slackOpts, opts...)
+
+	return &SocketMode{
+		router: router,
+		client: socketmode.New(slack.New("", slackOpts...), socketmode.OptionLog(zap.NewStdLog(logger))),
+		logger: logger,
+	}, nil
+}
+
+// Run connects and handles events until ctx is done. The client reconnects by itself when Slack asks
+// it to; Run returns when a connection cannot be opened.
+func (sm *SocketMode) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-sm.client.Events:
+				if !ok {
+					return
+				}
+				sm.handle(evt)
+			}
+		}
+	}()
+
+	err := sm.client.RunContext(ctx)
+	sm.setLive(false)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (sm *SocketMode) handle(evt socketmode.Event) {
+	switch evt.Type {
+	case socketmode.EventTypeConnected:
+		sm.logger.Info("Socket Mode connected", zap.String("context", "console"))
+		sm.setLive(true)
+	case socketmode.EventTypeConnectionError:
+		sm.logger.Warn("Socket Mode connection failed, retrying", zap.Any("error", evt.Data))
+		sm.setLive(false)
+	case socketmode.EventTypeDisconnect:
+		sm.logger.Info("Socket Mode disconnect requested by Slack, reconnecting")
+		sm.setLive(false)
+	case socketmode.EventTypeEventsAPI:
+		// Slack redelivers events that are not acknowledged within 3 seconds
+		if evt.Request != nil {
+			sm.client.Ack(*evt.Request)
+		}
+		event, ok := evt.Data.(slackevents.EventsAPIEvent)
+		if !ok {
+			return
+		}
+		sm.router.ApplyEvent(event)
+	}
+}
+
+func (sm *SocketMode) setLive(live bool) {
+	sm.router.SetLive(live)
+}
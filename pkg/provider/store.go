@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// CacheStore persists the users, channels and emojis caches between runs. The provider serves lookups from
+// its in-memory snapshots; the store keeps them across restarts and answers the lookups the snapshots
+// have no index for, such as users by email.
+type CacheStore interface {
+	Users() ([]slack.User, CacheState, error)
+	// UsersState is the state Users returns, without reading the users
+	UsersState() (CacheState, error)
+	ReplaceUsers(users []slack.User) error
+	// MergeUsers writes the users of a delta refresh, leaving the others alone
+	MergeUsers(users []slack.User) error
+	PutUser(user slack.User) error
+	UserByID(id string) (slack.User, bool, error)
+	UserByName(name string) (slack.User, bool, error)
+	UserByEmail(email string) (slack.User, bool, error)
+
+	Channels() ([]Channel, CacheState, error)
+	ChannelsState() (CacheState, error)
+	ReplaceChannels(channels []Channel) error
+	MergeChannels(channels []Channel) error
+	PutChannel(channel Channel) error
+	ChannelByID(id string) (Channel, bool, error)
+	ChannelByName(name string) (Channel, bool, error)
+
+	Emojis() ([]Emoji, CacheState, error)
+	ReplaceEmojis(emojis []Emoji) error
+
+	// Location names the store in logs
+	Location() string
+	Close() error
+}
+
+// CacheState is when a cache was last brought up to date. Zero times are never.
+type CacheState struct {
+	Refreshed  time.Time // by a full or a delta refresh
+	Reconciled time.Time // by a full refresh, which also drops what was deleted in Slack
+}
+
+// openCacheStore opens the cache database at dbPath, importing the JSON cache files of earlier versions
+// into it on first start. SLACK_MCP_CACHE_STORE=json keeps the JSON files as the store instead, as does a
+// database that cannot be opened, for example because another process holds its lock.
+func openCacheStore(dbPath, usersPath, channelsPath, emojisPath string, logger *zap.Logger) CacheStore {
+	files := &jsonStore{usersPath: usersPath, channelsPath: channelsPath, emojisPath: emojisPath}
+	if strings.EqualFold(os.Getenv("SLACK_MCP_CACHE_STORE"), "json") {
+		return files
+	}
+
+	db, err := openBoltStore(dbPath)
+	if err != nil {
+		logger.Warn("Failed to open cache database, using JSON cache files",
+			zap.String("cache_file", dbPath),
+			zap.Error(err))
+		return files
+	}
+	if err := migrateCacheStore(files, db, logger); err != nil {
+		logger.Warn("Failed to import JSON cache files",
+			zap.String("cache_file", dbPath),
+			zap.Error(err))
+	}
+	return db
+}
+
+// migrateCacheStore copies the caches of from that to has none of, keeping their age. They have never been
+// reconciled in to, so the first refresh after is a full one.
+func migrateCacheStore(from *jsonStore, to *boltStore, logger *zap.Logger) error {
+	var errs []error
+	if users, _, err := to.Users(); err != nil {
+		errs = append(errs, err)
+	} else if len(users) == 0 {
+		if users, state, err := from.Users(); err == nil && len(users) > 0 {
+			errs = append(errs, to.replaceUsers(users, state))
+			logger.Info("Imported users cache", zap.Int("count", len(users)), zap.String("cache_file", from.usersPath))
+		}
+	}
+	if channels, _, err := to.Channels(); err != nil {
+		errs = append(errs, err)
+	} else if len(channels) == 0 {
+		if channels, state, err := from.Channels(); err == nil && len(channels) > 0 {
+			errs = append(errs, to.replaceChannels(channels, state))
+			logger.Info("Imported channels cache", zap.Int("count", len(channels)), zap.String("cache_file", from.channelsPath))
+		}
+	}
+	if emojis, _, err := to.Emojis(); err != nil {
+		errs = append(errs, err)
+	} else if len(emojis) == 0 {
+		if emojis, state, err := from.Emojis(); err == nil && len(emojis) > 0 {
+			errs = append(errs, to.replaceEmojis(emojis, state))
+			logger.Info("Imported emojis cache", zap.Int("count", len(emojis)), zap.String("cache_file", from.emojisPath))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// jsonStore keeps each cache in a JSON file, rewritten whole on every change, with the file's modification
+// time as its age. Lookups scan the file. Single user changes are not written; the next refresh does.
+// Reconciles are not recorded, so every refresh but a forced one is a full one.
+type jsonStore struct {
+	usersPath    string
+	channelsPath string
+	emojisPath   string
+}
+
+// readJSONCache reads a cache file and its modification time. A missing file is an empty cache.
+func readJSONCache[T any](path string) ([]T, CacheState, error) {
+	if path == "" {
+		return nil, CacheState{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, CacheState{}, nil
+	}
+	if err != nil {
+		return nil, CacheState{}, err
+	}
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, CacheState{}, err
+	}
+	state, err := jsonCacheState(path)
+	return items, state, err
+}
+
+// jsonCacheState is the state of a cache file, its modification time
+func jsonCacheState(path string) (CacheState, error) {
+	if path == "" {
+		return CacheState{}, nil
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return CacheState{}, nil
+	}
+	if err != nil {
+		return CacheState{}, err
+	}
+	return CacheState{Refreshed: info.ModTime()}, nil
+}
+
+// mergeJSONCache replaces the records of a cache file with the same ID as one of items and appends the rest
+func mergeJSONCache[T any](path string, id func(T) string, items ...T) error {
+	cached, _, err := readJSONCache[T](path)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]int, len(cached))
+	for i, item := range cached {
+		byID[id(item)] = i
+	}
+	for _, item := range items {
+		if i, ok := byID[id(item)]; ok {
+			cached[i] = item
+		} else {
+			byID[id(item)] = len(cached)
+			cached = append(cached, item)
+		}
+	}
+	return writeJSONCache(path, cached)
+}
+
+func writeJSONCache[T any](path string, items []T) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *jsonStore) Users() ([]slack.User, CacheState, error) {
+	return readJSONCache[slack.User](s.usersPath)
+}
+
+func (s *jsonStore) UsersState() (CacheState, error) {
+	return jsonCacheState(s.usersPath)
+}
+
+func (s *jsonStore) ReplaceUsers(users []slack.User) error {
+	return writeJSONCache(s.usersPath, users)
+}
+
+func (s *jsonStore) MergeUsers(users []slack.User) error {
+	return mergeJSONCache(s.usersPath, userID, users...)
+}
+
+func (s *jsonStore) PutUser(user slack.User) error {
+	return nil
+}
+
+func (s *jsonStore) findUser(match func(slack.User) bool) (slack.User, bool, error) {
+	users, _, err := s.Users()
+	if err != nil {
+		return slack.User{}, false, err
+	}
+	for _, u := range users {
+		if match(u) {
+			return u, true, nil
+		}
+	}
+	return slack.User{}, false, nil
+}
+
+func (s *jsonStore) UserByID(id string) (slack.User, bool, error) {
+	return s.findUser(func(u slack.User) bool { return u.ID == id })
+}
+
+func (s *jsonStore) UserByName(name string) (slack.User, bool, error) {
+	return s.findUser(func(u slack.User) bool { return u.Name == name })
+}
+
+func (s *jsonStore) UserByEmail(email string) (slack.User, bool, error) {
+	return s.findUser(func(u slack.User) bool {
+		return u.Profile.Email != "" && strings.EqualFold(u.Profile.Email, email)
+	})
+}
+
+func (s *jsonStore) Channels() ([]Channel, CacheState, error) {
+	return readJSONCache[Channel](s.channelsPath)
+}
+
+func (s *jsonStore) ChannelsState() (CacheState, error) {
+	return jsonCacheState(s.channelsPath)
+}
+
+func (s *jsonStore) ReplaceChannels(channels []Channel) error {
+	return writeJSONCache(s.channelsPath, channels)
+}
+
+func (s *jsonStore) MergeChannels(channels []Channel) error {
+	return mergeJSONCache(s.channelsPath, channelID, channels...)
+}
+
+func (s *jsonStore) PutChannel(channel Channel) error {
+	return mergeJSONCache(s.channelsPath, channelID, channel)
+}
+
+func (s *jsonStore) findChannel(match func(Channel) bool) (Channel, bool, error) {
+	channels, _, err := s.Channels()
+	if err != nil {
+		return Channel{}, false, err
+	}
+	for _, c := range channels {
+		if match(c) {
+			return c, true, nil
+		}
+	}
+	return Channel{}, false, nil
+}
+
+func (s *jsonStore) ChannelByID(id string) (Channel, bool, error) {
+	return s.findChannel(func(c Channel) bool { return c.ID == id })
+}
+
+func (s *jsonStore) ChannelByName(name string) (Channel, bool, error) {
+	return s.findChannel(func(c Channel) bool { return c.Name == name })
+}
+
+func (s *jsonStore) Emojis() ([]Emoji, CacheState, error) {
+	return readJSONCache[Emoji](s.emojisPath)
+}
+
+func (s *jsonStore) ReplaceEmojis(emojis []Emoji) error {
+	return writeJSONCache(s.emojisPath, emojis)
+}
+
+func (s *jsonStore) Location() string {
+	var paths []string
+	for _, path := range []string{s.usersPath, s.channelsPath, s.emojisPath} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return strings.Join(paths, ", ")
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
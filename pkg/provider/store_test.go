@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testUser(id, name, email string) slack.User {
+	u := slack.User{ID: id, Name: name}
+	u.Profile.Email = email
+	return u
+}
+
+func TestBoltStore(t *testing.T) {
+	s, err := openBoltStore(filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	users, state, err := s.Users()
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Equal(t, CacheState{}, state, "a new store has never been refreshed")
+
+	require.NoError(t, s.ReplaceUsers([]slack.User{
+		testUser("U1", "alice", "Alice@Example.com"),
+		testUser("U2", "bob", ""),
+	}))
+	users, state, err = s.Users()
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.WithinDuration(t, time.Now(), state.Refreshed, time.Minute)
+	assert.Equal(t, state.Refreshed, state.Reconciled, "replacing is a full refresh")
+
+	t.Run("lookups by id, name and email", func(t *testing.T) {
+		u, ok, err := s.UserByID("U2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "bob", u.Name)
+
+		u, ok, err = s.UserByName("alice")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U1", u.ID)
+
+		u, ok, err = s.UserByEmail("alice@example.COM")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U1", u.ID)
+
+		_, ok, err = s.UserByEmail("")
+		require.NoError(t, err)
+		assert.False(t, ok, "users without email are not indexed")
+	})
+
+	t.Run("put moves the indexes", func(t *testing.T) {
+		before := state
+		require.NoError(t, s.PutUser(testUser("U1", "alice.smith", "alice@new.example.com")))
+
+		_, ok, err := s.UserByName("alice")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		_, ok, err = s.UserByEmail("alice@example.com")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		u, ok, err := s.UserByEmail("alice@new.example.com")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "alice.smith", u.Name)
+
+		after, err := s.UsersState()
+		require.NoError(t, err)
+		assert.True(t, before.Refreshed.Equal(after.Refreshed), "single changes don't count as a refresh")
+	})
+
+	t.Run("merge is a delta refresh", func(t *testing.T) {
+		before := state
+		require.NoError(t, s.MergeUsers([]slack.User{testUser("U3", "carol", "carol@example.com")}))
+		users, after, err := s.Users()
+		require.NoError(t, err)
+		assert.Len(t, users, 3)
+		assert.True(t, after.Refreshed.After(before.Refreshed))
+		assert.True(t, before.Reconciled.Equal(after.Reconciled))
+		state, err := s.UsersState()
+		require.NoError(t, err)
+		assert.Equal(t, after, state)
+	})
+
+	t.Run("replace drops what is gone", func(t *testing.T) {
+		require.NoError(t, s.ReplaceUsers([]slack.User{testUser("U2", "bob", "")}))
+		_, ok, err := s.UserByEmail("alice@new.example.com")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		users, _, err := s.Users()
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+	})
+
+	t.Run("replace moves the indexes of the records that changed", func(t *testing.T) {
+		require.NoError(t, s.ReplaceUsers([]slack.User{
+			testUser("U1", "alice", "alice@example.com"),
+			testUser("U2", "bob", "bob@example.com"),
+		}))
+		require.NoError(t, s.ReplaceUsers([]slack.User{
+			testUser("U1", "alice", "bob@example.com"),
+			testUser("U2", "bob", "alice@example.com"),
+		}))
+		u, ok, err := s.UserByEmail("alice@example.com")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U2", u.ID)
+		u, ok, err = s.UserByEmail("bob@example.com")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U1", u.ID)
+
+		require.NoError(t, s.ReplaceUsers([]slack.User{
+			testUser("U1", "bob", "bob@example.com"),
+			testUser("U2", "alice", "alice@example.com"),
+		}))
+		u, ok, err = s.UserByName("alice")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U2", u.ID)
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		require.NoError(t, s.ReplaceChannels([]Channel{{ID: "C1", Name: "#general"}, {ID: "C2", Name: "#random"}}))
+		require.NoError(t, s.PutChannel(Channel{ID: "C1", Name: "#announcements"}))
+
+		_, ok, err := s.ChannelByName("#general")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		c, ok, err := s.ChannelByName("#announcements")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "C1", c.ID)
+		c, ok, err = s.ChannelByID("C2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "#random", c.Name)
+
+		state, err := s.ChannelsState()
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), state.Refreshed, time.Minute)
+	})
+}
+
+func TestBoltStoreShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	first, err := openBoltStore(path)
+	require.NoError(t, err)
+	second, err := openBoltStore(path)
+	require.NoError(t, err, "a database open in this process is shared instead of waiting for its lock")
+	assert.Same(t, first, second)
+
+	require.NoError(t, first.Close())
+	require.NoError(t, second.PutChannel(Channel{ID: "C1", Name: "#general"}), "still open for the other provider")
+	require.NoError(t, second.Close())
+
+	third, err := openBoltStore(path)
+	require.NoError(t, err)
+	defer third.Close()
+	_, ok, err := third.ChannelByID("C1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestOpenCacheStoreMigratesJSON(t *testing.T) {
+	dir := t.TempDir()
+	usersPath := filepath.Join(dir, "users_cache.json")
+	channelsPath := filepath.Join(dir, "channels_cache_v2.json")
+	emojisPath := filepath.Join(dir, "emojis_cache.json")
+
+	writeJSON := func(path string, v any) {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+	}
+	writeJSON(usersPath, []slack.User{testUser("U1", "alice", "alice@example.com")})
+	writeJSON(channelsPath, []Channel{{ID: "C1", Name: "#general"}})
+	writeJSON(emojisPath, []Emoji{{Name: "party", URL: "https://example.com/party.png", IsCustom: true}})
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(usersPath, twoHoursAgo, twoHoursAgo))
+
+	s := openCacheStore(filepath.Join(dir, "cache.db"), usersPath, channelsPath, emojisPath, zap.NewNop())
+	db, ok := s.(*boltStore)
+	require.True(t, ok)
+
+	u, ok, err := db.UserByEmail("alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "U1", u.ID)
+	_, state, err := db.Users()
+	require.NoError(t, err)
+	assert.WithinDuration(t, twoHoursAgo, state.Refreshed, time.Second, "imported caches keep their age")
+	assert.True(t, state.Reconciled.IsZero(), "imported caches were never reconciled")
+
+	_, ok, err = db.ChannelByName("#general")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	emojis, _, err := db.Emojis()
+	require.NoError(t, err)
+	assert.Len(t, emojis, 1)
+
+	// Later starts don't import again over newer data
+	require.NoError(t, db.ReplaceChannels([]Channel{{ID: "C2", Name: "#random"}}))
+	require.NoError(t, db.Close())
+	s = openCacheStore(filepath.Join(dir, "cache.db"), usersPath, channelsPath, emojisPath, zap.NewNop())
+	defer s.Close()
+	_, ok, err = s.ChannelByID("C1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	t.Run("json store when asked for", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CACHE_STORE", "json")
+		s := openCacheStore(filepath.Join(dir, "other.db"), usersPath, channelsPath, emojisPath, zap.NewNop())
+		assert.IsType(t, &jsonStore{}, s)
+		u, ok, err := s.UserByEmail("ALICE@example.com")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U1", u.ID)
+	})
+}
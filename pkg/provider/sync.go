@@ -0,0 +1,565 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultSyncInterval = 5 * time.Minute
+
+	syncPageSize = 200
+	// syncBackfillPages bounds the pages a channel backfills per round, so one long channel does not hold
+	// up tailing the others
+	syncBackfillPages = 25
+	// syncThreadWindow is how far back tailing re-reads a channel to find threads with new replies
+	syncThreadWindow = 72 * time.Hour
+	// syncClockSkew is how much earlier than the start of the last tail replies are looked for, in case
+	// the local clock is ahead of Slack's
+	syncClockSkew = time.Minute
+)
+
+// SyncState is how far a channel has been synced into the message archive. Tailing resumes after Latest,
+// and after Tailed for thread replies, and backfilling before Oldest, also after a restart.
+type SyncState struct {
+	Channel    string    `json:"channel"`
+	Name       string    `json:"name"`
+	Latest     string    `json:"latest,omitempty"`  // newest synced message
+	Oldest     string    `json:"oldest,omitempty"`  // oldest synced message
+	Backfilled bool      `json:"backfilled"`        // Oldest is the first message of the channel
+	Messages   int       `json:"messages"`          // messages and replies synced
+	Tailed     time.Time `json:"tailed,omitempty"`  // start of the last tail without errors
+	Synced     time.Time `json:"synced,omitempty"`  // end of the last round without errors
+	Error      string    `json:"error,omitempty"`   // of the last round
+	Updated    time.Time `json:"updated,omitempty"` // end of the last round
+}
+
+// SyncStatus describes a Syncer and the channels it syncs
+type SyncStatus struct {
+	Patterns  []string
+	Interval  time.Duration
+	Running   bool
+	Current   string // name of the channel being synced
+	LastRound time.Time
+	NextRound time.Time
+	Channels  []SyncState
+}
+
+// SyncPatterns returns the channels of SLACK_MCP_SYNC_CHANNELS, comma-separated glob patterns on channel
+// names such as #inc-*, or channel IDs. Names without a leading # or @ get a #.
+func SyncPatterns() ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(os.Getenv("SLACK_MCP_SYNC_CHANNELS"), ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if !strings.HasPrefix(pattern, "#") && !strings.HasPrefix(pattern, "@") && !isSlackConversationID(pattern) {
+			pattern = "#" + pattern
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid channel pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+func isSlackConversationID(s string) bool {
+	if len(s) < 9 || !strings.ContainsAny(s[:1], "CDG") {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// getSyncInterval returns how often synced channels are checked for new messages, from
+// SLACK_MCP_SYNC_INTERVAL or default (5 minutes).
+// Supports formats: "5m", "300" (seconds). Values under a second fall back to the default.
+func getSyncInterval() time.Duration {
+	raw := os.Getenv("SLACK_MCP_SYNC_INTERVAL")
+	if raw == "" {
+		return defaultSyncInterval
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		secs, convErr := strconv.ParseInt(raw, 10, 64)
+		if convErr != nil {
+			return defaultSyncInterval
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d < time.Second {
+		return defaultSyncInterval
+	}
+	return d
+}
+
+// Syncer backfills and tails the history of the channels matching its patterns, threads included, into the
+// message archive. It calls conversations.history and conversations.replies within the Tier 3 budget.
+type Syncer struct {
+	ap       *ApiProvider
+	archive  *MessageArchive
+	patterns []string
+	interval time.Duration
+	limiter  *rate.Limiter
+	logger   *zap.Logger
+
+	mu        sync.Mutex
+	running   bool
+	current   string
+	lastRound time.Time
+	nextRound time.Time
+}
+
+func newSyncer(ap *ApiProvider, archive *MessageArchive, patterns []string) *Syncer {
+	return &Syncer{
+		ap:       ap,
+		archive:  archive,
+		patterns: patterns,
+		interval: getSyncInterval(),
+		limiter:  limiter.Tier3.Limiter(),
+		logger:   ap.logger,
+	}
+}
+
+// Syncer returns the syncer of the channels in SLACK_MCP_SYNC_CHANNELS, or nil when none are configured
+func (ap *ApiProvider) Syncer() *Syncer {
+	return ap.syncer
+}
+
+// Channels returns the cached channels matching the sync patterns, by name
+func (s *Syncer) Channels() []Channel {
+	var channels []Channel
+	for _, ch := range s.ap.ProvideChannelsMaps().Channels {
+		for _, pattern := range s.patterns {
+			if ok, _ := path.Match(pattern, ch.Name); ok || pattern == ch.ID {
+				channels = append(channels, ch)
+				break
+			}
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	return channels
+}
+
+// Run syncs the channels every sync interval until ctx is done. The first round waits for the caches,
+// which resolve the patterns to channels.
+func (s *Syncer) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.nextRound = time.Time{}
+		s.mu.Unlock()
+	}()
+
+	for {
+		wait := 5 * time.Second
+		if ready, _ := s.ap.IsReady(); ready {
+			if err := s.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Warn("Channel sync round failed", zap.Error(err))
+			}
+			wait = s.interval
+		}
+
+		s.mu.Lock()
+		s.nextRound = time.Now().Add(wait)
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// SyncOnce syncs every matching channel once: new messages first, then the next part of the backfill
+func (s *Syncer) SyncOnce(ctx context.Context) error {
+	channels := s.Channels()
+	if len(channels) == 0 {
+		s.logger.Warn("No cached channel matches SLACK_MCP_SYNC_CHANNELS", zap.Strings("patterns", s.patterns))
+	}
+
+	var errs []error
+	for _, ch := range channels {
+		if err := s.syncChannel(ctx, ch); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+		}
+	}
+
+	s.mu.Lock()
+	s.lastRound = time.Now()
+	s.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (s *Syncer) syncChannel(ctx context.Context, ch Channel) error {
+	s.mu.Lock()
+	s.current = ch.Name
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.current = ""
+		s.mu.Unlock()
+	}()
+
+	state, err := s.archive.SyncState(ch.ID)
+	if err != nil {
+		return err
+	}
+	state.Name = ch.Name
+	synced := state.Messages
+
+	err = s.tail(ctx, &state)
+	if err == nil && !state.Backfilled {
+		err = s.backfill(ctx, &state)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	state.Updated = time.Now()
+	state.Error = ""
+	if err != nil {
+		state.Error = err.Error()
+	} else {
+		state.Synced = state.Updated
+	}
+	if putErr := s.archive.PutSyncState(state); putErr != nil {
+		return putErr
+	}
+	s.logger.Debug("Synced channel",
+		zap.String("channel", ch.Name),
+		zap.Int("new_messages", state.Messages-synced),
+		zap.Bool("backfilled", state.Backfilled),
+		zap.Error(err))
+	return err
+}
+
+// tail syncs the messages posted since the last round, and the replies posted since the last tail to
+// threads started within syncThreadWindow before it
+func (s *Syncer) tail(ctx context.Context, state *SyncState) error {
+	start := time.Now()
+	if state.Latest == "" && !state.Backfilled {
+		// Nothing synced yet, the backfill starts from the newest message and reads every thread
+		state.Tailed = start
+		return nil
+	}
+
+	since, repliesSince := state.Latest, state.Latest
+	if !state.Tailed.IsZero() {
+		if mark := slackTs(state.Tailed.Add(-syncClockSkew)); tsAfter(mark, repliesSince) {
+			repliesSince = mark
+		}
+	}
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: state.Channel,
+		Limit:     syncPageSize,
+	}
+	// A channel that was empty when backfilled is read from its start
+	if since != "" {
+		oldest := tsTime(repliesSince).Add(-syncThreadWindow)
+		if t := tsTime(since); t.Before(oldest) {
+			oldest = t
+		}
+		params.Oldest = slackTs(oldest)
+	}
+	for {
+		history, err := s.history(ctx, params)
+		if err != nil {
+			return err
+		}
+		var messages []slack.Message
+		for _, msg := range history.Messages {
+			if tsAfter(msg.Timestamp, since) {
+				messages = append(messages, msg)
+				if tsAfter(msg.Timestamp, state.Latest) {
+					state.Latest = msg.Timestamp
+				}
+				if state.Oldest == "" || tsAfter(state.Oldest, msg.Timestamp) {
+					state.Oldest = msg.Timestamp
+				}
+			}
+			if msg.ReplyCount > 0 && tsAfter(msg.LatestReply, repliesSince) {
+				if err := s.syncReplies(ctx, state, msg.Timestamp, repliesSince); err != nil {
+					return err
+				}
+				if !tsAfter(msg.Timestamp, since) {
+					// Keeps the reply count of the archived parent current
+					messages = append(messages, msg)
+				}
+			}
+		}
+		if err := s.put(state, messages); err != nil {
+			return err
+		}
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			state.Tailed = start
+			return nil
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+}
+
+// backfill syncs older messages, newest first, recording its progress after every page
+func (s *Syncer) backfill(ctx context.Context, state *SyncState) error {
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: state.Channel,
+		Latest:    state.Oldest,
+		Limit:     syncPageSize,
+	}
+	for page := 0; page < syncBackfillPages; page++ {
+		history, err := s.history(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, msg := range history.Messages {
+			if state.Latest == "" || tsAfter(msg.Timestamp, state.Latest) {
+				state.Latest = msg.Timestamp
+			}
+			if state.Oldest == "" || tsAfter(state.Oldest, msg.Timestamp) {
+				state.Oldest = msg.Timestamp
+			}
+			if msg.ReplyCount > 0 {
+				if err := s.syncReplies(ctx, state, msg.Timestamp, ""); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.put(state, history.Messages); err != nil {
+			return err
+		}
+
+		state.Backfilled = !history.HasMore || len(history.Messages) == 0
+		if err := s.archive.PutSyncState(*state); err != nil {
+			return err
+		}
+		if state.Backfilled {
+			return nil
+		}
+		params.Latest = state.Oldest
+	}
+	return nil
+}
+
+// syncReplies syncs the replies of a thread posted after since, or all of them when since is empty
+func (s *Syncer) syncReplies(ctx context.Context, state *SyncState, threadTs, since string) error {
+	params := &slack.GetConversationRepliesParameters{
+		ChannelID: state.Channel,
+		Timestamp: threadTs,
+		Limit:     syncPageSize,
+	}
+	for {
+		type repliesPage struct {
+			messages   []slack.Message
+			hasMore    bool
+			nextCursor string
+		}
+		page, err := limiter.CallWithRetry(ctx, s.limiter, 2, rateLimitRetryAfter, func() (repliesPage, error) {
+			messages, hasMore, nextCursor, err := s.ap.client.GetConversationRepliesContext(ctx, params)
+			return repliesPage{messages, hasMore, nextCursor}, err
+		})
+		if err != nil {
+			return err
+		}
+
+		var replies []slack.Message
+		for _, msg := range page.messages {
+			// The parent comes first on every page and is synced with the history
+			if msg.Timestamp != threadTs && (since == "" || tsAfter(msg.Timestamp, since)) {
+				replies = append(replies, msg)
+			}
+		}
+		if err := s.put(state, replies); err != nil {
+			return err
+		}
+
+		if !page.hasMore || page.nextCursor == "" {
+			return nil
+		}
+		params.Cursor = page.nextCursor
+	}
+}
+
+func (s *Syncer) history(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	return limiter.CallWithRetry(ctx, s.limiter, 2, rateLimitRetryAfter, func() (*slack.GetConversationHistoryResponse, error) {
+		return s.ap.client.GetConversationHistoryContext(ctx, params)
+	})
+}
+
+func (s *Syncer) put(state *SyncState, messages []slack.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	archived := make([]ArchivedMessage, 0, len(messages))
+	for _, msg := range messages {
+		archived = append(archived, ArchivedFromMessage(state.Channel, msg))
+	}
+	added, err := s.archive.put(archived)
+	if err != nil {
+		return err
+	}
+	state.Messages += added
+	return nil
+}
+
+// Status returns the syncer's progress and that of every matching channel
+func (s *Syncer) Status() (SyncStatus, error) {
+	s.mu.Lock()
+	status := SyncStatus{
+		Patterns:  s.patterns,
+		Interval:  s.interval,
+		Running:   s.running,
+		Current:   s.current,
+		LastRound: s.lastRound,
+		NextRound: s.nextRound,
+	}
+	s.mu.Unlock()
+
+	for _, ch := range s.Channels() {
+		state, err := s.archive.SyncState(ch.ID)
+		if err != nil {
+			return status, err
+		}
+		state.Name = ch.Name
+		status.Channels = append(status.Channels, state)
+	}
+	return status, nil
+}
+
+// SyncedHistory serves a conversations.history read of a synced channel from the message archive: the
+// top-level messages posted after oldest and before latest, newest first, at most limit. ok is false
+// unless the channel was tailed within the sync interval and the archive holds the whole range.
+func (ap *ApiProvider) SyncedHistory(channel, oldest, latest string, limit int) (messages []slack.Message, ok bool) {
+	state, archived, ok := ap.syncedMessages(channel)
+	if !ok || limit <= 0 {
+		return nil, false
+	}
+	for _, m := range archived {
+		if !tsAfter(m.Ts, oldest) || (latest != "" && !tsAfter(latest, m.Ts)) || tsAfter(state.Oldest, m.Ts) {
+			continue
+		}
+		msg, ok := m.message()
+		if !ok {
+			return nil, false
+		}
+		if msg.ThreadTimestamp != "" && msg.ThreadTimestamp != msg.Timestamp && msg.SubType != slack.MsgSubTypeThreadBroadcast {
+			continue // replies are only in the thread
+		}
+		messages = append(messages, msg)
+		if len(messages) == limit {
+			return messages, true
+		}
+	}
+	// Short of limit, the synced messages must reach back to oldest
+	covered := state.Backfilled || (state.Oldest != "" && oldest != "" && !tsAfter(state.Oldest, oldest))
+	return messages, covered
+}
+
+// SyncedReplies serves a conversations.replies read of a thread in a synced channel from the message
+// archive: the parent, then the replies posted after oldest and before latest, at most limit. ok is false
+// unless the channel was tailed within the sync interval and tailing still follows the thread.
+func (ap *ApiProvider) SyncedReplies(channel, threadTs, oldest, latest string, limit int) (messages []slack.Message, ok bool) {
+	state, archived, ok := ap.syncedMessages(channel)
+	if !ok || limit <= 0 {
+		return nil, false
+	}
+	if tsAfter(state.Oldest, threadTs) || tsTime(threadTs).Before(state.Tailed.Add(-syncThreadWindow)) {
+		return nil, false
+	}
+
+	var parent *slack.Message
+	var replies []slack.Message
+	for i := len(archived) - 1; i >= 0; i-- {
+		m := archived[i]
+		if m.Ts != threadTs && m.ThreadTs != threadTs {
+			continue
+		}
+		msg, ok := m.message()
+		if !ok {
+			return nil, false
+		}
+		if m.Ts == threadTs {
+			parent = &msg
+		} else if tsAfter(m.Ts, oldest) && (latest == "" || tsAfter(latest, m.Ts)) {
+			replies = append(replies, msg)
+		}
+	}
+	if parent == nil {
+		return nil, false
+	}
+	messages = append([]slack.Message{*parent}, replies...)
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, true
+}
+
+// syncedMessages returns the sync state and archived messages of a channel, newest first, when the channel
+// was tailed within the sync interval
+func (ap *ApiProvider) syncedMessages(channel string) (SyncState, []ArchivedMessage, bool) {
+	if ap.archive == nil || ap.syncer == nil {
+		return SyncState{}, nil, false
+	}
+	state, err := ap.archive.SyncState(channel)
+	if err != nil || state.Tailed.IsZero() || time.Since(state.Tailed) > ap.syncer.interval {
+		return state, nil, false
+	}
+	archived, err := ap.archive.Search(MessageQuery{Channel: channel})
+	if err != nil {
+		ap.logger.Warn("Failed to read synced messages",
+			zap.String("archive_file", ap.archive.Location()),
+			zap.String("channel", channel),
+			zap.Error(err))
+		return state, nil, false
+	}
+	return state, archived, true
+}
+
+// slackTs formats t as a Slack timestamp
+func slackTs(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/1000)
+}
+
+// tsAfter reports whether Slack timestamp a is later than b. Every timestamp is later than an empty one.
+func tsAfter(a, b string) bool {
+	if b == "" {
+		return a != ""
+	}
+	return tsTime(a).After(tsTime(b))
+}
+
+// rateLimitRetryAfter returns how long Slack asked to wait after a rate limited call, 0 for other errors
+func rateLimitRetryAfter(err error) time.Duration {
+	var rle *slack.RateLimitedError
+	if errors.As(err, &rle) {
+		return rle.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,282 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// syncClient serves the history and threads of channels a few messages per page
+type syncClient struct {
+	SlackAPI
+	history      map[string][]slack.Message // channel -> messages
+	replies      map[string][]slack.Message // thread ts -> replies
+	pageSize     int
+	failLatest   string // history calls with this latest fail
+	historyCalls []slack.GetConversationHistoryParameters
+}
+
+func (c *syncClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	c.historyCalls = append(c.historyCalls, *params)
+	if c.failLatest != "" && params.Latest == c.failLatest {
+		return nil, errors.New("internal_error")
+	}
+
+	latest := params.Latest
+	if params.Cursor != "" {
+		latest = params.Cursor
+	}
+	var messages []slack.Message
+	for _, msg := range c.history[params.ChannelID] {
+		if (latest == "" || tsAfter(latest, msg.Timestamp)) && tsAfter(msg.Timestamp, params.Oldest) {
+			messages = append(messages, msg)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return tsAfter(messages[i].Timestamp, messages[j].Timestamp)
+	})
+	resp := &slack.GetConversationHistoryResponse{}
+	if len(messages) > c.pageSize {
+		messages, resp.HasMore = messages[:c.pageSize], true
+		resp.ResponseMetaData.NextCursor = messages[len(messages)-1].Timestamp
+	}
+	resp.Messages = messages
+	return resp, nil
+}
+
+func (c *syncClient) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	parent := slack.Message{}
+	parent.Timestamp = params.Timestamp
+	return append([]slack.Message{parent}, c.replies[params.Timestamp]...), false, "", nil
+}
+
+func syncMessage(ts, text string) slack.Message {
+	msg := slack.Message{}
+	msg.Timestamp = ts
+	msg.User = "U1"
+	msg.Text = text
+	return msg
+}
+
+func TestSyncer(t *testing.T) {
+	// Threads are followed for syncThreadWindow after the last tail, so the messages are recent
+	base := time.Now().Add(-time.Hour).Unix()
+	ts := func(offset int64) string { return fmt.Sprintf("%d.000000", base+offset) }
+	thread := syncMessage(ts(200), "db primary is down")
+	thread.ReplyCount = 1
+	thread.LatestReply = ts(250)
+	reply := syncMessage(ts(250), "failing over")
+	reply.ThreadTimestamp = thread.Timestamp
+
+	client := &syncClient{
+		history: map[string][]slack.Message{
+			"C1": {
+				syncMessage(ts(0), "incident opened"),
+				syncMessage(ts(100), "paging the db oncall"),
+				thread,
+				syncMessage(ts(300), "resolved"),
+			},
+			"C2": {syncMessage(ts(0), "lunch?")},
+		},
+		replies:    map[string][]slack.Message{thread.Timestamp: {reply}},
+		pageSize:   2,
+		failLatest: ts(200),
+	}
+	ap := newDeltaProvider(t, &deltaClient{})
+	ap.client = client
+	ap.mergeChannels([]Channel{{ID: "C1", Name: "#inc-db"}, {ID: "C2", Name: "#general"}})
+
+	archive, err := OpenMessageArchive(filepath.Join(t.TempDir(), "messages.db"))
+	require.NoError(t, err)
+	defer archive.Close()
+	newTestSyncer := func() *Syncer {
+		s := newSyncer(ap, archive, []string{"#inc-*"})
+		s.limiter = rate.NewLimiter(rate.Inf, 1)
+		return s
+	}
+	ctx := context.Background()
+	archived := func(channel string) []string {
+		matches, err := archive.Search(MessageQuery{Channel: channel})
+		require.NoError(t, err)
+		return archivedTs(matches)
+	}
+
+	s := newTestSyncer()
+	require.Len(t, s.Channels(), 1)
+	assert.Error(t, s.SyncOnce(ctx))
+	state, err := archive.SyncState("C1")
+	require.NoError(t, err)
+	assert.Equal(t, "#inc-db", state.Name)
+	assert.Equal(t, ts(300), state.Latest)
+	assert.Equal(t, ts(200), state.Oldest, "the first page is kept")
+	assert.False(t, state.Backfilled)
+	assert.Equal(t, "internal_error", state.Error)
+	assert.Equal(t, []string{ts(300), ts(250), ts(200)}, archived("C1"))
+	assert.Empty(t, archived("C2"), "channels not matching the patterns are not synced")
+
+	t.Run("backfill resumes after a restart", func(t *testing.T) {
+		client.failLatest = ""
+		client.historyCalls = nil
+		require.NoError(t, newTestSyncer().SyncOnce(ctx))
+
+		state, err := archive.SyncState("C1")
+		require.NoError(t, err)
+		assert.True(t, state.Backfilled)
+		assert.Empty(t, state.Error)
+		assert.Equal(t, ts(0), state.Oldest)
+		assert.Equal(t, 5, state.Messages)
+		assert.Len(t, archived("C1"), 5)
+		last := client.historyCalls[len(client.historyCalls)-1]
+		assert.Equal(t, ts(200), last.Latest, "the backfill continues before the oldest synced message")
+	})
+
+	t.Run("tail syncs new messages and replies", func(t *testing.T) {
+		client.history["C1"] = append(client.history["C1"], syncMessage(ts(400), "postmortem scheduled"))
+		// Replies are looked for since the last tail, by the clock
+		late := syncMessage(slackTs(time.Now()), "root cause: disk full")
+		late.ThreadTimestamp = thread.Timestamp
+		client.replies[thread.Timestamp] = append(client.replies[thread.Timestamp], late)
+		client.history["C1"][2].ReplyCount = 2
+		client.history["C1"][2].LatestReply = late.Timestamp
+		client.historyCalls = nil
+
+		require.NoError(t, newTestSyncer().SyncOnce(ctx))
+		state, err := archive.SyncState("C1")
+		require.NoError(t, err)
+		assert.Equal(t, ts(400), state.Latest)
+		assert.Equal(t, 7, state.Messages, "messages synced before are not counted again")
+		assert.Equal(t, []string{late.Timestamp, ts(400)}, archived("C1")[:2])
+		for _, call := range client.historyCalls {
+			assert.Empty(t, call.Latest, "a backfilled channel is only tailed")
+		}
+	})
+
+	t.Run("rounds without new messages sync nothing", func(t *testing.T) {
+		before, err := archive.SyncState("C1")
+		require.NoError(t, err)
+		s := newTestSyncer()
+		require.NoError(t, s.SyncOnce(ctx))
+		require.NoError(t, s.SyncOnce(ctx))
+
+		state, err := archive.SyncState("C1")
+		require.NoError(t, err)
+		assert.Equal(t, before.Messages, state.Messages)
+		assert.Equal(t, before.Latest, state.Latest)
+		assert.Len(t, archived("C1"), 7, "replies read again are not archived twice")
+	})
+
+	t.Run("reads of synced channels are served from the archive", func(t *testing.T) {
+		ap.archive, ap.syncer = archive, newTestSyncer()
+		defer func() { ap.archive, ap.syncer = nil, nil }()
+		timestamps := func(messages []slack.Message) []string {
+			var ts []string
+			for _, msg := range messages {
+				ts = append(ts, msg.Timestamp)
+			}
+			return ts
+		}
+
+		history, ok := ap.SyncedHistory("C1", "", "", 3)
+		require.True(t, ok)
+		assert.Equal(t, []string{ts(400), ts(300), ts(200)}, timestamps(history), "replies stay in their thread")
+		assert.Equal(t, "postmortem scheduled", history[0].Text)
+		history, ok = ap.SyncedHistory("C1", ts(100), "", 100)
+		require.True(t, ok)
+		assert.Equal(t, []string{ts(400), ts(300), ts(200)}, timestamps(history))
+		assert.Equal(t, 2, history[2].ReplyCount, "tailing updates the parent of threads with new replies")
+
+		replies, ok := ap.SyncedReplies("C1", thread.Timestamp, "", "", 100)
+		require.True(t, ok)
+		assert.Equal(t, []string{ts(200), ts(250), client.replies[thread.Timestamp][1].Timestamp}, timestamps(replies))
+		replies, ok = ap.SyncedReplies("C1", thread.Timestamp, ts(250), "", 100)
+		require.True(t, ok)
+		assert.Len(t, replies, 2, "the parent comes first")
+
+		_, ok = ap.SyncedHistory("C2", "", "", 100)
+		assert.False(t, ok, "channels not synced are read from Slack")
+		state, err := archive.SyncState("C1")
+		require.NoError(t, err)
+		state.Tailed = time.Now().Add(-time.Hour)
+		require.NoError(t, archive.PutSyncState(state))
+		_, ok = ap.SyncedHistory("C1", "", "", 100)
+		assert.False(t, ok, "channels not tailed within the sync interval are read from Slack")
+		_, ok = ap.SyncedReplies("C1", thread.Timestamp, "", "", 100)
+		assert.False(t, ok)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		status, err := newTestSyncer().Status()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"#inc-*"}, status.Patterns)
+		require.Len(t, status.Channels, 1)
+		assert.Equal(t, "C1", status.Channels[0].Channel)
+		assert.True(t, status.Channels[0].Backfilled)
+	})
+}
+
+func TestSyncerEmptyChannel(t *testing.T) {
+	client := &syncClient{
+		history:  map[string][]slack.Message{},
+		replies:  map[string][]slack.Message{},
+		pageSize: 2,
+	}
+	ap := newDeltaProvider(t, &deltaClient{})
+	ap.client = client
+	ap.mergeChannels([]Channel{{ID: "C1", Name: "#inc-new"}})
+
+	archive, err := OpenMessageArchive(filepath.Join(t.TempDir(), "messages.db"))
+	require.NoError(t, err)
+	defer archive.Close()
+	s := newSyncer(ap, archive, []string{"#inc-*"})
+	s.limiter = rate.NewLimiter(rate.Inf, 1)
+	ctx := context.Background()
+
+	require.NoError(t, s.SyncOnce(ctx))
+	state, err := archive.SyncState("C1")
+	require.NoError(t, err)
+	assert.True(t, state.Backfilled)
+	assert.Empty(t, state.Latest)
+
+	// Messages posted after the channel was backfilled empty are tailed from its start
+	now := time.Now().Unix()
+	thread := syncMessage(fmt.Sprintf("%d.000000", now-20), "api latency is up")
+	thread.ReplyCount = 1
+	thread.LatestReply = fmt.Sprintf("%d.000000", now-10)
+	reply := syncMessage(thread.LatestReply, "rolling back")
+	reply.ThreadTimestamp = thread.Timestamp
+	client.history["C1"] = []slack.Message{thread}
+	client.replies[thread.Timestamp] = []slack.Message{reply}
+	client.historyCalls = nil
+
+	require.NoError(t, s.SyncOnce(ctx))
+	state, err = archive.SyncState("C1")
+	require.NoError(t, err)
+	assert.Equal(t, thread.Timestamp, state.Latest)
+	assert.Equal(t, thread.Timestamp, state.Oldest)
+	assert.Equal(t, 2, state.Messages)
+	require.NotEmpty(t, client.historyCalls)
+	assert.Empty(t, client.historyCalls[0].Oldest)
+	matches, err := archive.Search(MessageQuery{Channel: "C1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{reply.Timestamp, thread.Timestamp}, archivedTs(matches))
+}
+
+func TestSyncPatterns(t *testing.T) {
+	t.Setenv("SLACK_MCP_SYNC_CHANNELS", "inc-*, #postmortems,@alice,C0123456789,,")
+	patterns, err := SyncPatterns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"#inc-*", "#postmortems", "@alice", "C0123456789"}, patterns)
+
+	t.Setenv("SLACK_MCP_SYNC_CHANNELS", "#inc-[")
+	_, err = SyncPatterns()
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rusq/slackdump/v3/auth"
+	"go.uber.org/zap"
+)
+
+// WorkspaceNames returns the additional workspaces listed in SLACK_MCP_WORKSPACES, comma-separated.
+// The workspace of the unsuffixed token variables is always served and is not listed there.
+func WorkspaceNames() ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv("SLACK_MCP_WORKSPACES"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var names []string
+	suffixes := make(map[string]string)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.EqualFold(name, "all") {
+			return nil, fmt.Errorf("%q is reserved for searching every workspace", name)
+		}
+		suffix := workspaceEnvSuffix(name)
+		if other, ok := suffixes[suffix]; ok {
+			return nil, fmt.Errorf("workspaces %q and %q read the same token variables", other, name)
+		}
+		suffixes[suffix] = name
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// workspaceEnvSuffix returns what a workspace's token variables append to the default ones: the name
+// upper-cased, with anything but letters and digits replaced by underscores. "acme-eu" reads
+// SLACK_MCP_XOXP_TOKEN_ACME_EU.
+func workspaceEnvSuffix(name string) string {
+	var b strings.Builder
+	b.WriteByte('_')
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// NewWorkspace creates the provider of an additional workspace from SLACK_MCP_XOXP_TOKEN_<NAME>,
+// SLACK_MCP_XOXB_TOKEN_<NAME>, or SLACK_MCP_XOXC_TOKEN_<NAME> and SLACK_MCP_XOXD_TOKEN_<NAME>. The cache
+// path variables only apply to the default workspace; caches of the others are kept per team ID.
+func NewWorkspace(transport, name string, logger *zap.Logger) *ApiProvider {
+	suffix := workspaceEnvSuffix(name)
+	xoxpToken := os.Getenv("SLACK_MCP_XOXP_TOKEN" + suffix)
+	xoxbToken := os.Getenv("SLACK_MCP_XOXB_TOKEN" + suffix)
+	xoxcToken := os.Getenv("SLACK_MCP_XOXC_TOKEN" + suffix)
+	xoxdToken := os.Getenv("SLACK_MCP_XOXD_TOKEN" + suffix)
+
+	var token, cookie string
+	switch {
+	case xoxpToken != "":
+		token = xoxpToken
+	case xoxbToken != "":
+		token = xoxbToken
+	case xoxcToken != "" && xoxdToken != "":
+		token, cookie = xoxcToken, xoxdToken
+	default:
+		logger.Fatal("Authentication required for workspace: Either SLACK_MCP_XOXP_TOKEN"+suffix+", SLACK_MCP_XOXB_TOKEN"+suffix+", or both SLACK_MCP_XOXC_TOKEN"+suffix+" and SLACK_MCP_XOXD_TOKEN"+suffix+" must be provided",
+			zap.String("workspace", name))
+	}
+
+	authProvider, err := auth.NewValueAuth(token, cookie)
+	if err != nil {
+		logger.Fatal("Failed to create auth provider for workspace", zap.String("workspace", name), zap.Error(err))
+	}
+	client, err := NewMCPSlackClient(authProvider, logger)
+	if err != nil {
+		logger.Fatal("Authentication failed - check your Slack tokens", zap.String("workspace", name), zap.Error(err))
+	}
+	authResp, err := client.AuthTest()
+	if err != nil {
+		logger.Fatal("Authentication failed - check your Slack tokens", zap.String("workspace", name), zap.Error(err))
+	}
+
+	logger.Info("Authenticated to Slack",
+		zap.String("workspace", name),
+		zap.String("team", authResp.Team),
+		zap.String("team_id", authResp.TeamID),
+		zap.String("user", authResp.User))
+
+	return newApiProvider(transport, client, logger, openCacheStore(
+		getCachePathWithTeamID(authResp.TeamID, "cache.db"),
+		getCachePathWithTeamID(authResp.TeamID, "users_cache.json"),
+		getCachePathWithTeamID(authResp.TeamID, "channels_cache_v2.json"),
+		getCachePathWithTeamID(authResp.TeamID, "emojis_cache.json"),
+		logger,
+	), openMessageArchive(getCachePathWithTeamID(authResp.TeamID, "messages.db"), logger))
+}
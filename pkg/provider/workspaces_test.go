@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "none when env not set",
+			envValue: "",
+			expected: nil,
+		},
+		{
+			name:     "comma-separated with spaces",
+			envValue: "acme, globex ,,initech",
+			expected: []string{"acme", "globex", "initech"},
+		},
+		{
+			name:     "all is reserved",
+			envValue: "acme,All",
+			wantErr:  true,
+		},
+		{
+			name:     "names sharing token variables",
+			envValue: "acme-eu,ACME_EU",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SLACK_MCP_WORKSPACES", tt.envValue)
+			names, err := WorkspaceNames()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestWorkspaceEnvSuffix(t *testing.T) {
+	assert.Equal(t, "_ACME", workspaceEnvSuffix("acme"))
+	assert.Equal(t, "_ACME_EU", workspaceEnvSuffix("acme-eu"))
+	assert.Equal(t, "_T0123", workspaceEnvSuffix("T0123"))
+}
@@ -0,0 +1,388 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"go.uber.org/zap"
+)
+
+// The HTTP and SSE transports act as an OAuth 2.1 resource server, as the MCP authorization spec asks:
+// clients discover the authorization server from the protected resource metadata (RFC 9728), obtain an
+// access token there and send it as a bearer token. Tokens are JWTs (RFC 9068) checked against the
+// issuer's JWKS. The server never issues tokens itself.
+
+const (
+	protectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+	// Keys are fetched again after this long, and at most this often when a token names an unknown key
+	jwksTTL          = time.Hour
+	jwksMinRefresh   = time.Minute
+	jwtLeeway        = time.Minute
+	oauthHTTPTimeout = 10 * time.Second
+)
+
+// signingAlgorithms are the JWS algorithms accepted for access tokens. Symmetric algorithms and "none"
+// are refused: a JWKS only publishes public keys.
+var signingAlgorithms = map[string]bool{
+	string(jose.RS256): true, string(jose.RS384): true, string(jose.RS512): true,
+	string(jose.PS256): true, string(jose.PS384): true, string(jose.PS512): true,
+	string(jose.ES256): true, string(jose.ES384): true, string(jose.ES512): true,
+	string(jose.EdDSA): true,
+}
+
+// OAuthConfig configures access token validation
+type OAuthConfig struct {
+	Issuer   string // "iss" of the tokens and the authorization server advertised to clients
+	JWKSURL  string // discovered from the issuer's metadata when empty
+	Resource string // canonical URL of this server, e.g. https://mcp.example.com/mcp
+	Audience string // "aud" the tokens must carry, the resource by default
+}
+
+// OAuthConfigFromEnv reads SLACK_MCP_OAUTH_ISSUER, SLACK_MCP_OAUTH_RESOURCE, SLACK_MCP_OAUTH_JWKS_URL and
+// SLACK_MCP_OAUTH_AUDIENCE. It returns nil when no issuer is set, i.e. OAuth is off.
+func OAuthConfigFromEnv() (*OAuthConfig, error) {
+	config := &OAuthConfig{
+		Issuer:   os.Getenv("SLACK_MCP_OAUTH_ISSUER"),
+		JWKSURL:  os.Getenv("SLACK_MCP_OAUTH_JWKS_URL"),
+		Resource: os.Getenv("SLACK_MCP_OAUTH_RESOURCE"),
+		Audience: os.Getenv("SLACK_MCP_OAUTH_AUDIENCE"),
+	}
+	if config.Issuer == "" {
+		return nil, nil
+	}
+	if config.Resource == "" {
+		return nil, errors.New("SLACK_MCP_OAUTH_RESOURCE must be set to the URL of this server when SLACK_MCP_OAUTH_ISSUER is set")
+	}
+	for name, raw := range map[string]string{"issuer": config.Issuer, "JWKS URL": config.JWKSURL, "resource": config.Resource} {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("OAuth %s %q must be an absolute http(s) URL", name, raw)
+		}
+	}
+	if config.Audience == "" {
+		config.Audience = config.Resource
+	}
+	return config, nil
+}
+
+// oauthEnabled reports whether access tokens are OAuth tokens rather than SLACK_MCP_API_KEY
+func oauthEnabled() bool {
+	return os.Getenv("SLACK_MCP_OAUTH_ISSUER") != ""
+}
+
+// Claims are the parts of a validated access token the server uses
+type Claims struct {
+	Subject  string
+	ClientID string
+	Scopes   []string
+}
+
+type claimsKey struct{}
+
+func withClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the access token the request was authorized with
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// OAuth validates access tokens and serves the protected resource metadata
+type OAuth struct {
+	config OAuthConfig
+	keys   *jwks
+	logger *zap.Logger
+}
+
+// NewOAuth creates a validator for config. client fetches the issuer metadata and JWKS; nil uses a
+// client with a 10 second timeout.
+func NewOAuth(config OAuthConfig, client *http.Client, logger *zap.Logger) *OAuth {
+	if client == nil {
+		client = &http.Client{Timeout: oauthHTTPTimeout}
+	}
+	return &OAuth{
+		config: config,
+		keys:   &jwks{url: config.JWKSURL, issuer: config.Issuer, client: client},
+		logger: logger,
+	}
+}
+
+// Validate checks the signature, issuer, audience and lifetime of a JWT access token
+func (o *OAuth) Validate(ctx context.Context, token string) (*Claims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed access token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, errors.New("access token must have exactly one signature")
+	}
+	header := parsed.Headers[0]
+	if !signingAlgorithms[header.Algorithm] {
+		return nil, fmt.Errorf("access token signed with unsupported algorithm %q", header.Algorithm)
+	}
+
+	key, err := o.keys.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+		return nil, fmt.Errorf("key %q is for %s, not %s", key.KeyID, key.Algorithm, header.Algorithm)
+	}
+
+	var (
+		standard jwt.Claims
+		extra    struct {
+			Scope    string `json:"scope"`
+			Scp      any    `json:"scp"`
+			ClientID string `json:"client_id"`
+			AZP      string `json:"azp"`
+		}
+	)
+	if err := parsed.Claims(key.Key, &standard, &extra); err != nil {
+		return nil, fmt.Errorf("invalid access token signature: %w", err)
+	}
+	if standard.Expiry == nil {
+		return nil, errors.New("access token has no expiry")
+	}
+	err = standard.ValidateWithLeeway(jwt.Expected{
+		Issuer:   o.config.Issuer,
+		Audience: jwt.Audience{o.config.Audience},
+		Time:     time.Now(),
+	}, jwtLeeway)
+	if err != nil {
+		return nil, fmt.Errorf("access token rejected: %w", err)
+	}
+
+	claims := &Claims{Subject: standard.Subject, ClientID: extra.ClientID, Scopes: strings.Fields(extra.Scope)}
+	if claims.ClientID == "" {
+		claims.ClientID = extra.AZP
+	}
+	// Some authorization servers list scopes in "scp", as a string or an array
+	switch scp := extra.Scp.(type) {
+	case string:
+		claims.Scopes = append(claims.Scopes, strings.Fields(scp)...)
+	case []any:
+		for _, scope := range scp {
+			if s, ok := scope.(string); ok {
+				claims.Scopes = append(claims.Scopes, s)
+			}
+		}
+	}
+	return claims, nil
+}
+
+// Middleware rejects requests without a valid access token allowing at least the read tools. Per the MCP
+// spec, the 401 and 403 answers point clients at the protected resource metadata.
+func (o *OAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			o.challenge(w, http.StatusUnauthorized, "", "")
+			return
+		}
+
+		claims, err := o.Validate(r.Context(), token)
+		if err != nil {
+			o.logger.Warn("Rejected access token", zap.String("context", "http"), zap.Error(err))
+			o.challenge(w, http.StatusUnauthorized, "invalid_token", "")
+			return
+		}
+		if !claims.Allows(GroupRead) {
+			o.logger.Warn("Access token lacks the read scope",
+				zap.String("context", "http"),
+				zap.String("sub", claims.Subject),
+				zap.Strings("scopes", claims.Scopes),
+			)
+			o.challenge(w, http.StatusForbidden, "insufficient_scope", GroupRead.Scope())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
+
+func (o *OAuth) challenge(w http.ResponseWriter, status int, errorCode, scope string) {
+	value := fmt.Sprintf(`Bearer resource_metadata=%q`, o.MetadataURL())
+	if errorCode != "" {
+		value += fmt.Sprintf(`, error=%q`, errorCode)
+	}
+	if scope != "" {
+		value += fmt.Sprintf(`, scope=%q`, scope)
+	}
+	w.Header().Set("WWW-Authenticate", value)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// MetadataPaths returns the paths the protected resource metadata is served at: the well-known path
+// with the resource's path appended, as RFC 9728 specifies, and the bare well-known path for clients
+// that only try that one
+func (o *OAuth) MetadataPaths() []string {
+	paths := []string{protectedResourceMetadataPath}
+	if u, err := url.Parse(o.config.Resource); err == nil && strings.Trim(u.Path, "/") != "" {
+		paths = append(paths, protectedResourceMetadataPath+"/"+strings.Trim(u.Path, "/"))
+	}
+	return paths
+}
+
+// MetadataURL is the URL of the protected resource metadata of this server
+func (o *OAuth) MetadataURL() string {
+	u, err := url.Parse(o.config.Resource)
+	if err != nil {
+		return protectedResourceMetadataPath
+	}
+	paths := o.MetadataPaths()
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: paths[len(paths)-1]}).String()
+}
+
+// MetadataHandler serves the protected resource metadata
+func (o *OAuth) MetadataHandler() http.Handler {
+	metadata := map[string]any{
+		"resource":                 o.config.Resource,
+		"authorization_servers":    []string{o.config.Issuer},
+		"scopes_supported":         []string{GroupRead.Scope(), GroupWrite.Scope(), GroupAdmin.Scope()},
+		"bearer_methods_supported": []string{"header"},
+		"resource_name":            "Slack MCP Server",
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if err := json.NewEncoder(w).Encode(metadata); err != nil {
+			o.logger.Warn("Failed to write protected resource metadata", zap.Error(err))
+		}
+	})
+}
+
+// jwks caches the issuer's signing keys
+type jwks struct {
+	url    string
+	issuer string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    jose.JSONWebKeySet
+	fetched time.Time
+}
+
+// key returns the signing key with the given ID. Keys are fetched again when they are older than an hour
+// or, at most once a minute, when the ID is unknown, so rotated keys are picked up.
+func (k *jwks) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.fetched.IsZero() || time.Since(k.fetched) > jwksTTL {
+		if err := k.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if key := k.find(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(k.fetched) < jwksMinRefresh {
+		return nil, fmt.Errorf("no signing key %q in the issuer's JWKS", kid)
+	}
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key := k.find(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key %q in the issuer's JWKS", kid)
+}
+
+// find returns the signing key with the given ID. Tokens without a key ID match the only signing key.
+func (k *jwks) find(kid string) *jose.JSONWebKey {
+	var signing []jose.JSONWebKey
+	for _, key := range k.keys.Keys {
+		if key.Use == "" || key.Use == "sig" {
+			signing = append(signing, key)
+		}
+	}
+	if kid == "" {
+		if len(signing) == 1 {
+			return &signing[0]
+		}
+		return nil
+	}
+	for i := range signing {
+		if signing[i].KeyID == kid {
+			return &signing[i]
+		}
+	}
+	return nil
+}
+
+func (k *jwks) refresh(ctx context.Context) error {
+	if k.url == "" {
+		jwksURL, err := k.discover(ctx)
+		if err != nil {
+			return err
+		}
+		k.url = jwksURL
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := k.getJSON(ctx, k.url, &keys); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	k.keys = keys
+	k.fetched = time.Now()
+	return nil
+}
+
+// discover finds the JWKS URL in the issuer's authorization server metadata (RFC 8414), falling back to
+// its OpenID Connect discovery document
+func (k *jwks) discover(ctx context.Context) (string, error) {
+	issuer := strings.TrimSuffix(k.issuer, "/")
+	var lastErr error
+	for _, path := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+		var metadata struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := k.getJSON(ctx, issuer+path, &metadata); err != nil {
+			lastErr = err
+			continue
+		}
+		if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+			return "", fmt.Errorf("authorization server metadata is for issuer %q, not %q", metadata.Issuer, k.issuer)
+		}
+		if metadata.JWKSURI == "" {
+			return "", errors.New("authorization server metadata has no jwks_uri")
+		}
+		return metadata.JWKSURI, nil
+	}
+	return "", fmt.Errorf("failed to discover the issuer's JWKS: %w", lastErr)
+}
+
+func (k *jwks) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
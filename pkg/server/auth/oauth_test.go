@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testResource = "https://mcp.example.com/mcp"
+
+// testIssuer is an authorization server stand-in publishing its metadata and JWKS over httptest
+type testIssuer struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	keys       map[string]*rsa.PrivateKey
+	jwksServed int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	issuer := &testIssuer{keys: make(map[string]*rsa.PrivateKey)}
+	issuer.addKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/jwks.json",
+		})
+	})
+	mux.HandleFunc("/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		issuer.jwksServed++
+		var set jose.JSONWebKeySet
+		for kid, key := range issuer.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: &key.PublicKey, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) addKey(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys[kid] = key
+}
+
+func (i *testIssuer) served() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.jwksServed
+}
+
+// token signs claims with the key kid. Standard claims default to a token for testResource valid for
+// an hour; fields of override replace them.
+func (i *testIssuer) token(t *testing.T, kid string, override map[string]any) string {
+	t.Helper()
+	i.mu.Lock()
+	key := i.keys[kid]
+	i.mu.Unlock()
+	require.NotNil(t, key, kid)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithType("at+jwt").WithHeader("kid", kid))
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":       i.server.URL,
+		"sub":       "U01AAAAAAAA",
+		"aud":       testResource,
+		"client_id": "claude-desktop",
+		"scope":     "slack:read slack:write",
+		"iat":       now.Unix(),
+		"exp":       now.Add(time.Hour).Unix(),
+	}
+	for k, v := range override {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func newTestOAuth(issuer *testIssuer) *OAuth {
+	return NewOAuth(OAuthConfig{Issuer: issuer.server.URL, Resource: testResource, Audience: testResource}, issuer.server.Client(), zap.NewNop())
+}
+
+func TestOAuthValidate(t *testing.T) {
+	issuer := newTestIssuer(t)
+	oauth := newTestOAuth(issuer)
+	ctx := context.Background()
+
+	claims, err := oauth.Validate(ctx, issuer.token(t, "key-1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "U01AAAAAAAA", claims.Subject)
+	assert.Equal(t, "claude-desktop", claims.ClientID)
+	assert.Equal(t, []string{"slack:read", "slack:write"}, claims.Scopes)
+	assert.Equal(t, 1, issuer.served(), "keys are discovered and fetched once")
+
+	claims, err = oauth.Validate(ctx, issuer.token(t, "key-1", map[string]any{"scope": nil, "scp": []string{"slack:admin"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slack:admin"}, claims.Scopes)
+
+	for name, override := range map[string]map[string]any{
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix(), "iat": time.Now().Add(-2 * time.Hour).Unix()},
+		"no expiry":      {"exp": nil},
+		"other issuer":   {"iss": "https://login.example.com"},
+		"other audience": {"aud": "https://other.example.com/mcp"},
+		"not yet valid":  {"nbf": time.Now().Add(time.Hour).Unix()},
+		"issued in 2099": {"iat": time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC).Unix()},
+	} {
+		_, err := oauth.Validate(ctx, issuer.token(t, "key-1", override))
+		assert.Error(t, err, name)
+	}
+
+	t.Run("refused algorithms", func(t *testing.T) {
+		hmacSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, (&jose.SignerOptions{}).WithHeader("kid", "key-1"))
+		require.NoError(t, err)
+		token, err := jwt.Signed(hmacSigner).Claims(jwt.Claims{Issuer: issuer.server.URL, Audience: jwt.Audience{testResource}, Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour))}).CompactSerialize()
+		require.NoError(t, err)
+		_, err = oauth.Validate(ctx, token)
+		assert.ErrorContains(t, err, "unsupported algorithm")
+
+		// alg "none" with an empty signature
+		parts := strings.Split(issuer.token(t, "key-1", nil), ".")
+		_, err = oauth.Validate(ctx, "eyJhbGciOiJub25lIn0."+parts[1]+".")
+		assert.Error(t, err)
+
+		_, err = oauth.Validate(ctx, "not-a-jwt")
+		assert.Error(t, err)
+	})
+
+	t.Run("rotated keys", func(t *testing.T) {
+		issuer.addKey(t, "key-2")
+		token := issuer.token(t, "key-2", nil)
+
+		// The JWKS was fetched less than a minute ago, so the unknown key is not looked up yet
+		_, err := oauth.Validate(ctx, token)
+		assert.ErrorContains(t, err, `no signing key "key-2"`)
+
+		oauth.keys.mu.Lock()
+		oauth.keys.fetched = time.Now().Add(-2 * jwksMinRefresh)
+		oauth.keys.mu.Unlock()
+		served := issuer.served()
+		_, err = oauth.Validate(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, served+1, issuer.served())
+	})
+
+	t.Run("signature from another key", func(t *testing.T) {
+		token := issuer.token(t, "key-1", nil)
+		forged := issuer.token(t, "key-2", nil)
+		parts, forgedParts := strings.Split(token, "."), strings.Split(forged, ".")
+		_, err := oauth.Validate(ctx, parts[0]+"."+parts[1]+"."+forgedParts[2])
+		assert.ErrorContains(t, err, "signature")
+	})
+}
+
+func TestOAuthMiddleware(t *testing.T) {
+	issuer := newTestIssuer(t)
+	oauth := newTestOAuth(issuer)
+
+	var seen *Claims
+	handler := oauth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = ClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	call := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{}`))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call("")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`, rec.Header().Get("WWW-Authenticate"))
+
+	rec = call("Bearer " + issuer.token(t, "key-1", map[string]any{"aud": "https://other.example.com"}))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+
+	rec = call("Bearer " + issuer.token(t, "key-1", map[string]any{"scope": "openid profile"}))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="insufficient_scope", scope="slack:read"`)
+
+	rec = call("Bearer " + issuer.token(t, "key-1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, seen)
+	assert.Equal(t, "U01AAAAAAAA", seen.Subject)
+}
+
+func TestOAuthMetadata(t *testing.T) {
+	issuer := newTestIssuer(t)
+	oauth := newTestOAuth(issuer)
+
+	assert.Equal(t, []string{"/.well-known/oauth-protected-resource", "/.well-known/oauth-protected-resource/mcp"}, oauth.MetadataPaths())
+
+	rec := httptest.NewRecorder()
+	oauth.MetadataHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource/mcp", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var metadata struct {
+		Resource             string   `json:"resource"`
+		AuthorizationServers []string `json:"authorization_servers"`
+		ScopesSupported      []string `json:"scopes_supported"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metadata))
+	assert.Equal(t, testResource, metadata.Resource)
+	assert.Equal(t, []string{issuer.server.URL}, metadata.AuthorizationServers)
+	assert.Equal(t, []string{"slack:read", "slack:write", "slack:admin"}, metadata.ScopesSupported)
+}
+
+func TestOAuthConfigFromEnv(t *testing.T) {
+	t.Setenv("SLACK_MCP_OAUTH_ISSUER", "")
+	config, err := OAuthConfigFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	t.Setenv("SLACK_MCP_OAUTH_ISSUER", "https://login.example.com")
+	_, err = OAuthConfigFromEnv()
+	assert.ErrorContains(t, err, "SLACK_MCP_OAUTH_RESOURCE")
+
+	t.Setenv("SLACK_MCP_OAUTH_RESOURCE", testResource)
+	config, err = OAuthConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, testResource, config.Audience)
+
+	t.Setenv("SLACK_MCP_OAUTH_JWKS_URL", "/jwks.json")
+	_, err = OAuthConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestToolGroups(t *testing.T) {
+	read := mcp.NewTool("list_channels", mcp.WithReadOnlyHintAnnotation(true))
+	write := mcp.NewTool("post_message", mcp.WithReadOnlyHintAnnotation(false), mcp.WithDestructiveHintAnnotation(false))
+	admin := mcp.NewTool("delete_message", mcp.WithReadOnlyHintAnnotation(false), mcp.WithDestructiveHintAnnotation(true))
+	assert.Equal(t, GroupRead, ToolGroupOf(read))
+	assert.Equal(t, GroupWrite, ToolGroupOf(write))
+	assert.Equal(t, GroupAdmin, ToolGroupOf(admin))
+	assert.Equal(t, GroupWrite, ToolGroupOf(mcp.NewTool("set_channel_topic", mcp.WithDestructiveHintAnnotation(true))),
+		"the table decides, not destructiveHint")
+	assert.Equal(t, GroupAdmin, ToolGroupOf(mcp.NewTool("new_tool", mcp.WithDestructiveHintAnnotation(false))),
+		"tools missing from the table need admin")
+
+	readOnly := &Claims{Scopes: []string{"slack:read"}}
+	assert.True(t, readOnly.Allows(GroupRead))
+	assert.False(t, readOnly.Allows(GroupWrite))
+	adminScope := &Claims{Scopes: []string{"openid", "slack:admin"}}
+	assert.True(t, adminScope.Allows(GroupRead), "admin includes read")
+	assert.True(t, adminScope.Allows(GroupAdmin))
+	assert.False(t, (&Claims{}).Allows(GroupRead))
+
+	tools := []mcp.Tool{read, write, admin}
+	assert.Equal(t, tools, FilterTools(context.Background(), tools), "no OAuth token, nothing hidden")
+	writer := withClaims(context.Background(), &Claims{Scopes: []string{"slack:write"}})
+	assert.Equal(t, []mcp.Tool{read, write}, FilterTools(writer, tools))
+}
+
+func TestValidateTokenWithOAuth(t *testing.T) {
+	t.Setenv("SLACK_MCP_OAUTH_ISSUER", "https://login.example.com")
+	t.Setenv("SLACK_MCP_API_KEY", "static-key")
+
+	ok, err := IsAuthenticated(withAuthKey(context.Background(), "Bearer static-key"), "http", zap.NewNop())
+	assert.False(t, ok, "the static key is not accepted once OAuth is on")
+	assert.Error(t, err)
+
+	ok, err = IsAuthenticated(withClaims(context.Background(), &Claims{Scopes: []string{"slack:read"}}), "http", zap.NewNop())
+	assert.True(t, ok)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// ToolGroup is the access level a tool needs. Each group has an OAuth scope; a scope also grants the
+// groups below it, so slack:admin allows every tool.
+type ToolGroup int
+
+const (
+	GroupRead  ToolGroup = iota // tools that only read (readOnlyHint)
+	GroupWrite                  // tools that change Slack but can be undone
+	GroupAdmin                  // tools whose changes can't simply be undone: deletions, archiving, removals
+)
+
+var groupScopes = map[ToolGroup]string{
+	GroupRead:  "slack:read",
+	GroupWrite: "slack:write",
+	GroupAdmin: "slack:admin",
+}
+
+// Scope returns the OAuth scope granting the group
+func (g ToolGroup) Scope() string {
+	return groupScopes[g]
+}
+
+// writeToolGroups is the group of each tool that changes Slack. destructiveHint is no guide here: it is
+// also set for changes that are easily undone, such as a new status or topic.
+var writeToolGroups = map[string]ToolGroup{
+	"post_message":             GroupWrite,
+	"post_message_as_bot":      GroupWrite,
+	"post_ephemeral":           GroupWrite,
+	"post_ephemeral_as_bot":    GroupWrite,
+	"post_me_message":          GroupWrite,
+	"post_me_message_as_bot":   GroupWrite,
+	"schedule_message":         GroupWrite,
+	"update_message":           GroupWrite,
+	"update_message_as_bot":    GroupWrite,
+	"add_reaction":             GroupWrite,
+	"remove_reaction":          GroupWrite,
+	"set_my_status":            GroupWrite,
+	"clear_my_status":          GroupWrite,
+	"set_my_presence":          GroupWrite,
+	"snooze_dnd":               GroupWrite,
+	"end_dnd":                  GroupWrite,
+	"add_reminder":             GroupWrite,
+	"complete_reminder":        GroupWrite,
+	"create_channel":           GroupWrite,
+	"unarchive_channel":        GroupWrite,
+	"rename_channel":           GroupWrite,
+	"set_channel_topic":        GroupWrite,
+	"set_channel_purpose":      GroupWrite,
+	"invite_to_channel":        GroupWrite,
+	"join_channel":             GroupWrite,
+	"leave_channel":            GroupWrite,
+	"pin_message":              GroupWrite,
+	"unpin_message":            GroupWrite,
+	"add_bookmark":             GroupWrite,
+	"download_file":            GroupWrite,
+	"upload_file":              GroupWrite,
+	"make_file_public":         GroupWrite,
+	"conversations_mark":       GroupWrite,
+	"usergroups_me":            GroupWrite,
+	"usergroups_create":        GroupWrite,
+	"usergroups_update":        GroupWrite,
+	"delete_message":           GroupAdmin,
+	"delete_message_as_bot":    GroupAdmin,
+	"delete_scheduled_message": GroupAdmin,
+	"delete_reminder":          GroupAdmin,
+	"remove_bookmark":          GroupAdmin,
+	"archive_channel":          GroupAdmin,
+	"remove_from_channel":      GroupAdmin,
+	"usergroups_users_update":  GroupAdmin,
+}
+
+// ToolGroupOf classifies a tool: read-only tools by their annotation, the others by writeToolGroups.
+// Tools missing from the table need slack:admin, so a new tool is never allowed by mistake.
+func ToolGroupOf(tool mcp.Tool) ToolGroup {
+	if readOnly := tool.Annotations.ReadOnlyHint; readOnly != nil && *readOnly {
+		return GroupRead
+	}
+	if group, ok := writeToolGroups[tool.Name]; ok {
+		return group
+	}
+	return GroupAdmin
+}
+
+// Allows reports whether the token's scopes grant a tool group
+func (c *Claims) Allows(group ToolGroup) bool {
+	for _, scope := range c.Scopes {
+		for g, s := range groupScopes {
+			if s == scope && g >= group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BuildScopeMiddleware refuses tool calls the request's access token has no scope for. Requests without
+// an OAuth token (stdio, SLACK_MCP_API_KEY) are left to the authentication middleware.
+func BuildScopeMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			claims, ok := ClaimsFromContext(ctx)
+			if !ok {
+				return next(ctx, req)
+			}
+			mcpServer := server.ServerFromContext(ctx)
+			if mcpServer == nil {
+				return next(ctx, req)
+			}
+			tool := mcpServer.GetTool(req.Params.Name)
+			if tool == nil {
+				return next(ctx, req)
+			}
+
+			group := ToolGroupOf(tool.Tool)
+			if !claims.Allows(group) {
+				logger.Warn("Tool call refused, insufficient scope",
+					zap.String("context", "http"),
+					zap.String("tool", req.Params.Name),
+					zap.String("sub", claims.Subject),
+					zap.String("required_scope", group.Scope()),
+				)
+				return mcp.NewToolResultError(fmt.Sprintf("insufficient scope: %s needs the %s scope", req.Params.Name, group.Scope())), nil
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// FilterTools hides the tools the request's access token has no scope for from tools/list
+func FilterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return tools
+	}
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if claims.Allows(ToolGroupOf(tool)) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
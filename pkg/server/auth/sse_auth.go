@@ -23,6 +23,21 @@ func withAuthKey(ctx context.Context, auth string) context.Context {
 
 // Authenticate checks if the request is authenticated based on the provided context.
 func validateToken(ctx context.Context, logger *zap.Logger) (bool, error) {
+	// OAuth access tokens are validated by OAuth.Middleware before the request reaches the MCP server
+	if oauthEnabled() {
+		claims, ok := ClaimsFromContext(ctx)
+		if !ok {
+			logger.Warn("Missing OAuth access token in context",
+				zap.String("context", "http"),
+			)
+			return false, fmt.Errorf("missing or invalid access token")
+		}
+		if !claims.Allows(GroupRead) {
+			return false, fmt.Errorf("access token lacks the %s scope", GroupRead.Scope())
+		}
+		return true, nil
+	}
+
 	// no configured token means no authentication
 	keyA := os.Getenv("SLACK_MCP_API_KEY")
 	if keyA == "" {
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+// Slack's Events API posts to /slack/events when the app's Request URL points at the HTTP transport. It is
+// the alternative to Socket Mode for deployments that accept inbound HTTPS but cannot keep a websocket
+// open to Slack. Both deliver events to ApiProvider.ApplyEvent.
+
+const slackEventsPath = "/slack/events"
+
+// maxSlackEventSize bounds the request bodies read from Slack; events are a few kilobytes
+const maxSlackEventSize = 1 << 20
+
+// slackSigningSecret returns SLACK_MCP_SIGNING_SECRET. The events endpoint is only served when it is set.
+func slackSigningSecret() string {
+	return os.Getenv("SLACK_MCP_SIGNING_SECRET")
+}
+
+// slackEventsHandler verifies the Slack signature of each request, answers the url_verification
+// challenge sent when the Request URL is saved, and passes callback events to apply.
+func slackEventsHandler(signingSecret string, apply func(slackevents.EventsAPIEvent), logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackEventSize))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		// The verifier also rejects requests signed more than five minutes ago, which stops replays
+		verifier, err := slack.NewSecretsVerifier(r.Header, signingSecret)
+		if err == nil {
+			_, err = verifier.Write(body)
+		}
+		if err == nil {
+			err = verifier.Ensure()
+		}
+		if err != nil {
+			logger.Warn("Rejected Slack event with an invalid signature", zap.Error(err))
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		// The signature already proves the request comes from Slack
+		event, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+		if err != nil {
+			logger.Warn("Failed to parse Slack event", zap.Error(err))
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Type {
+		case slackevents.URLVerification:
+			challenge, ok := event.Data.(*slackevents.EventsAPIURLVerificationEvent)
+			if !ok {
+				http.Error(w, "invalid challenge", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, challenge.Challenge)
+		case slackevents.CallbackEvent:
+			apply(event)
+			w.WriteHeader(http.StatusOK)
+		default:
+			// app_rate_limited and future envelope types need no answer
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// Tools keep answering with the CSV (or JSON, or plain) text they always have; the structured content
+// is derived from that text by buildStructuredContentMiddleware according to the tool's output schema.
+// Every output schema is an object with optional properties only:
+//
+//	rows      one object per CSV row, typed after the row struct given to withCSVOutput
+//	metadata  the "# Key: value" lines printed above the CSV, keyed in snake_case
+//	notes     the other "# ..." lines
+//	result    the parsed object, for tools that answer with JSON
+//	text      the raw text, for answers that are neither (e.g. "Marked #general as read")
+
+// csvColumnRe matches CSV header cells. Sentences such as "No users found matching the query." do not
+// match, which is how plain-text answers of CSV tools are told apart from an empty table.
+var csvColumnRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// withOutputProperties adds properties to a tool's output schema, creating the schema on first use
+func withOutputProperties(properties map[string]any) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		t.OutputSchema.Type = "object"
+		if t.OutputSchema.Properties == nil {
+			t.OutputSchema.Properties = map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "The text answer, when it is not a table or a JSON object",
+				},
+			}
+		}
+		for name, schema := range properties {
+			t.OutputSchema.Properties[name] = schema
+		}
+	}
+}
+
+// withCSVOutput declares the structured content of a tool answering with CSV. Rows are typed after the
+// csv tags of row; with a nil row (tools whose columns depend on the request) every value is a string.
+func withCSVOutput(row any) mcp.ToolOption {
+	items := map[string]any{"type": "object"}
+	if row != nil {
+		items["properties"] = fieldSchemas(reflect.TypeOf(row), "csv")
+	} else {
+		items["additionalProperties"] = map[string]any{"type": "string"}
+	}
+	return withOutputProperties(map[string]any{
+		"rows": map[string]any{
+			"type":        "array",
+			"description": "One object per CSV row, keyed by column name",
+			"items":       items,
+		},
+		"metadata": map[string]any{
+			"type":                 "object",
+			"description":          "The '# Key: value' lines above the CSV, such as next_cursor",
+			"additionalProperties": map[string]any{"type": "string"},
+		},
+		"notes": map[string]any{
+			"type":  "array",
+			"items": map[string]any{"type": "string"},
+		},
+	})
+}
+
+// withJSONOutput declares the structured content of a tool answering with a JSON object, typed after the
+// json tags of v. A nil v allows any object.
+func withJSONOutput(v any) mcp.ToolOption {
+	result := map[string]any{"type": "object"}
+	if v != nil {
+		result["properties"] = fieldSchemas(reflect.TypeOf(v), "json")
+	}
+	return withOutputProperties(map[string]any{"result": result})
+}
+
+// withTextOutput declares the structured content of a tool answering with free text
+func withTextOutput() mcp.ToolOption {
+	return withOutputProperties(nil)
+}
+
+// fieldSchemas maps the tagged fields of a struct to JSON schemas, following the naming rules of gocsv
+// and encoding/json: the tag name when set, the field name otherwise, and "-" skips the field.
+func fieldSchemas(t reflect.Type, tag string) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, schema := range fieldSchemas(field.Type, tag) {
+				properties[name] = schema
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = map[string]any{"type": jsonType(field.Type.Kind())}
+	}
+	return properties
+}
+
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// buildStructuredContentMiddleware adds structured content to successful results of tools that declare an
+// output schema. Results that already carry structured content, such as dry runs, are left alone.
+func buildStructuredContentMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err != nil || result == nil || result.IsError || result.StructuredContent != nil || len(result.Content) != 1 {
+				return result, err
+			}
+			mcpServer := server.ServerFromContext(ctx)
+			if mcpServer == nil {
+				return result, err
+			}
+			tool := mcpServer.GetTool(req.Params.Name)
+			if tool == nil || tool.Tool.OutputSchema.Type == "" {
+				return result, err
+			}
+			text, ok := result.Content[0].(mcp.TextContent)
+			if !ok {
+				return result, err
+			}
+
+			result.StructuredContent = structuredContent(tool.Tool.OutputSchema.Properties, text.Text)
+			logger.Debug("Added structured content", zap.String("tool", req.Params.Name))
+			return result, err
+		}
+	}
+}
+
+// structuredContent parses a text result into the shape declared by the output schema properties
+func structuredContent(properties map[string]any, text string) map[string]any {
+	if _, ok := properties["result"]; ok {
+		var object map[string]any
+		if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &object) == nil {
+			return map[string]any{"result": object}
+		}
+	}
+
+	if rowsSchema, ok := properties["rows"].(map[string]any); ok {
+		var columns map[string]any
+		if items, ok := rowsSchema["items"].(map[string]any); ok {
+			columns, _ = items["properties"].(map[string]any)
+		}
+		if content, ok := parseCSVResult(text, columns); ok {
+			return content
+		}
+	}
+
+	return map[string]any{"text": text}
+}
+
+// parseCSVResult splits a CSV result into its "#" header lines and rows. When columns is not empty, every
+// CSV column must be one of them and values are converted to the declared type; empty or malformed
+// values of non-string columns are left out.
+func parseCSVResult(text string, columns map[string]any) (map[string]any, bool) {
+	metadata := make(map[string]string)
+	var notes []string
+
+	rest := text
+	for strings.HasPrefix(rest, "#") {
+		line, remainder, _ := strings.Cut(rest, "\n")
+		rest = remainder
+
+		line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if line == "" {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			value = strings.TrimSpace(value)
+			// "(none - last page)" and similar mean there is no value
+			if strings.HasPrefix(value, "(none") {
+				value = ""
+			}
+			metadata[metadataKey(key)] = value
+		} else {
+			notes = append(notes, line)
+		}
+	}
+
+	records, err := csv.NewReader(strings.NewReader(rest)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, false
+	}
+	header := records[0]
+	for _, column := range header {
+		if !csvColumnRe.MatchString(column) {
+			return nil, false
+		}
+		if len(columns) > 0 {
+			if _, ok := columns[column]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	rows := make([]map[string]any, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			if i >= len(record) {
+				break
+			}
+			if value, ok := typedValue(record[i], columns[column]); ok {
+				row[column] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	content := map[string]any{"rows": rows}
+	if len(metadata) > 0 {
+		content["metadata"] = metadata
+	}
+	if len(notes) > 0 {
+		content["notes"] = notes
+	}
+	return content, true
+}
+
+func typedValue(raw string, schema any) (any, bool) {
+	columnType := "string"
+	if s, ok := schema.(map[string]any); ok {
+		if t, ok := s["type"].(string); ok {
+			columnType = t
+		}
+	}
+
+	var err error
+	var value any
+	switch columnType {
+	case "boolean":
+		value, err = strconv.ParseBool(raw)
+	case "integer":
+		value, err = strconv.ParseInt(raw, 10, 64)
+	case "number":
+		value, err = strconv.ParseFloat(raw, 64)
+	default:
+		return raw, true
+	}
+	return value, err == nil
+}
+
+// metadataKey turns "Next cursor" or "Returned in this page" into next_cursor and returned_in_this_page
+func metadataKey(label string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return b.String()
+}
@@ -2,17 +2,23 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/signal"
 	"slices"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/handler"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
 	"github.com/korotovsky/slack-mcp-server/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -20,38 +26,82 @@ import (
 )
 
 type MCPServer struct {
-	server      *server.MCPServer
-	logger      *zap.Logger
-	fileHandler *handler.FileHandler
+	server        *server.MCPServer
+	apiProvider   *provider.ApiProvider
+	logger        *zap.Logger
+	fileHandler   *handler.FileHandler
+	subscriptions *subscriptionRouter
+	stopPolling   context.CancelFunc
+
+	workspaces           *workspaceRouter
+	workspaceMiddlewares []server.ToolHandlerMiddleware
+	directories          []server.ServerResource // slack://<workspace>/channels and /users
+	extraWorkspaces      []*MCPServer
 }
 
 const (
 	// User's tool names
-	ToolGetCurrentUser       = "get_current_user"
-	ToolGetChannelMessages   = "get_channel_messages"
-	ToolGetThreadMessages    = "get_thread_messages"
-	ToolPostMessage          = "post_message"
-	ToolPostMessageAsBot     = "post_message_as_bot"
-	ToolAddReaction           = "add_reaction"
-	ToolRemoveReaction        = "remove_reaction"
-	ToolDeleteMessage        = "delete_message"
-	ToolUpdateMessage        = "update_message"
-	ToolUpdateMessageAsBot   = "update_message_as_bot"
-	ToolDeleteMessageAsBot   = "delete_message_as_bot"
-	ToolSearchMessages       = "search_messages"
-	ToolListChannels         = "list_channels"
-	ToolListChannelMembers   = "list_channel_members"
-	ToolListUsers            = "list_users"
-	ToolGetUserInfo          = "get_user_info"
-	ToolGetOrgOverview       = "get_org_overview"
-	ToolCreateChannel        = "create_channel"
-	ToolArchiveChannel       = "archive_channel"
-	ToolListEmojis           = "list_emojis"
-	ToolDownloadFile         = "download_file"
-	ToolGetFileInfo          = "get_file_info"
-	ToolUploadFile           = "upload_file"
-	ToolMakeFilePublic       = "make_file_public"
-	ToolGetSlackTemplates    = "get_slack_templates"
+	ToolGetCurrentUser         = "get_current_user"
+	ToolListWorkspaces         = "list_workspaces"
+	ToolGetChannelMessages     = "get_channel_messages"
+	ToolGetThreadMessages      = "get_thread_messages"
+	ToolWaitForReply           = "wait_for_reply"
+	ToolPostMessage            = "post_message"
+	ToolPostMessageAsBot       = "post_message_as_bot"
+	ToolPostEphemeral          = "post_ephemeral"
+	ToolPostEphemeralAsBot     = "post_ephemeral_as_bot"
+	ToolPostMeMessage          = "post_me_message"
+	ToolPostMeMessageAsBot     = "post_me_message_as_bot"
+	ToolScheduleMessage        = "schedule_message"
+	ToolListScheduledMessages  = "list_scheduled_messages"
+	ToolDeleteScheduledMessage = "delete_scheduled_message"
+	ToolAddReaction            = "add_reaction"
+	ToolRemoveReaction         = "remove_reaction"
+	ToolDeleteMessage          = "delete_message"
+	ToolUpdateMessage          = "update_message"
+	ToolUpdateMessageAsBot     = "update_message_as_bot"
+	ToolDeleteMessageAsBot     = "delete_message_as_bot"
+	ToolGetPermalink           = "get_permalink"
+	ToolSearchMessages         = "search_messages"
+	ToolSearchLocalMessages    = "search_local_messages"
+	ToolSyncStatus             = "sync_status"
+	ToolListChannels           = "list_channels"
+	ToolListChannelMembers     = "list_channel_members"
+	ToolListUsers              = "list_users"
+	ToolGetUserInfo            = "get_user_info"
+	ToolSetMyStatus            = "set_my_status"
+	ToolClearMyStatus          = "clear_my_status"
+	ToolSetMyPresence          = "set_my_presence"
+	ToolSnoozeDND              = "snooze_dnd"
+	ToolEndDND                 = "end_dnd"
+	ToolGetDNDInfo             = "get_dnd_info"
+	ToolAddReminder            = "add_reminder"
+	ToolListReminders          = "list_reminders"
+	ToolCompleteReminder       = "complete_reminder"
+	ToolDeleteReminder         = "delete_reminder"
+	ToolGetOrgOverview         = "get_org_overview"
+	ToolCreateChannel          = "create_channel"
+	ToolArchiveChannel         = "archive_channel"
+	ToolUnarchiveChannel       = "unarchive_channel"
+	ToolRenameChannel          = "rename_channel"
+	ToolSetChannelTopic        = "set_channel_topic"
+	ToolSetChannelPurpose      = "set_channel_purpose"
+	ToolInviteToChannel        = "invite_to_channel"
+	ToolRemoveFromChannel      = "remove_from_channel"
+	ToolJoinChannel            = "join_channel"
+	ToolLeaveChannel           = "leave_channel"
+	ToolPinMessage             = "pin_message"
+	ToolUnpinMessage           = "unpin_message"
+	ToolListPins               = "list_pins"
+	ToolAddBookmark            = "add_bookmark"
+	ToolListBookmarks          = "list_bookmarks"
+	ToolRemoveBookmark         = "remove_bookmark"
+	ToolListEmojis             = "list_emojis"
+	ToolDownloadFile           = "download_file"
+	ToolGetFileInfo            = "get_file_info"
+	ToolUploadFile             = "upload_file"
+	ToolMakeFilePublic         = "make_file_public"
+	ToolGetSlackTemplates      = "get_slack_templates"
 
 	// Upstream tool names (new tools not in user's fork)
 	ToolConversationsUnreads  = "conversations_unreads"
@@ -65,24 +115,60 @@ const (
 
 var ValidToolNames = []string{
 	ToolGetCurrentUser,
+	ToolListWorkspaces,
 	ToolGetChannelMessages,
 	ToolGetThreadMessages,
+	ToolWaitForReply,
 	ToolPostMessage,
 	ToolPostMessageAsBot,
+	ToolPostEphemeral,
+	ToolPostEphemeralAsBot,
+	ToolPostMeMessage,
+	ToolPostMeMessageAsBot,
+	ToolScheduleMessage,
+	ToolListScheduledMessages,
+	ToolDeleteScheduledMessage,
 	ToolAddReaction,
 	ToolRemoveReaction,
 	ToolDeleteMessage,
 	ToolUpdateMessage,
 	ToolUpdateMessageAsBot,
 	ToolDeleteMessageAsBot,
+	ToolGetPermalink,
 	ToolSearchMessages,
+	ToolSearchLocalMessages,
+	ToolSyncStatus,
 	ToolListChannels,
 	ToolListChannelMembers,
 	ToolListUsers,
 	ToolGetUserInfo,
+	ToolSetMyStatus,
+	ToolClearMyStatus,
+	ToolSetMyPresence,
+	ToolSnoozeDND,
+	ToolEndDND,
+	ToolGetDNDInfo,
+	ToolAddReminder,
+	ToolListReminders,
+	ToolCompleteReminder,
+	ToolDeleteReminder,
 	ToolGetOrgOverview,
 	ToolCreateChannel,
 	ToolArchiveChannel,
+	ToolUnarchiveChannel,
+	ToolRenameChannel,
+	ToolSetChannelTopic,
+	ToolSetChannelPurpose,
+	ToolInviteToChannel,
+	ToolRemoveFromChannel,
+	ToolJoinChannel,
+	ToolLeaveChannel,
+	ToolPinMessage,
+	ToolUnpinMessage,
+	ToolListPins,
+	ToolAddBookmark,
+	ToolListBookmarks,
+	ToolRemoveBookmark,
 	ToolListEmojis,
 	ToolDownloadFile,
 	ToolGetFileInfo,
@@ -118,6 +204,44 @@ func ValidateEnabledTools(tools []string) error {
 	return nil
 }
 
+// DefaultConfirmTools are the irreversible tools that need a person's approval when
+// SLACK_MCP_CONFIRM_TOOLS is not set
+var DefaultConfirmTools = []string{
+	ToolDeleteMessage,
+	ToolDeleteMessageAsBot,
+	ToolArchiveChannel,
+	ToolUsergroupsUsersUpdate,
+}
+
+// ParseConfirmTools parses SLACK_MCP_CONFIRM_TOOLS: empty means DefaultConfirmTools, "none" turns
+// confirmation off, anything else is a comma-separated list of tool names.
+func ParseConfirmTools(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	switch strings.ToLower(raw) {
+	case "":
+		return DefaultConfirmTools, nil
+	case "none", "false", "0":
+		return nil, nil
+	}
+
+	var tools []string
+	for _, tool := range strings.Split(raw, ",") {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			tools = append(tools, tool)
+		}
+	}
+	if err := ValidateEnabledTools(tools); err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+func confirmToolsFromEnv() []string {
+	// main validates SLACK_MCP_CONFIRM_TOOLS at startup, an invalid value cannot get here
+	tools, _ := ParseConfirmTools(os.Getenv("SLACK_MCP_CONFIRM_TOOLS"))
+	return tools
+}
+
 func shouldAddTool(name string, enabledTools []string, envVarName string) bool {
 	if envVarName == "" {
 		if len(enabledTools) == 0 {
@@ -138,40 +262,89 @@ func shouldAddTool(name string, enabledTools []string, envVarName string) bool {
 }
 
 func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledTools []string) *MCPServer {
-	s := server.NewMCPServer(
-		"Slack MCP Server",
-		version.Version,
+	confirmationSummarizer := handler.NewConfirmationSummarizer(provider, logger)
+	completionsHandler := handler.NewCompletionsHandler(provider, logger)
+	subscriptions := &subscriptionRouter{
+		subscriptions: handler.NewSubscriptionsHandler(provider, logger),
+		transport:     provider.ServerTransport(),
+		logger:        logger,
+	}
+
+	workspaces := &workspaceRouter{logger: logger}
+	// Middlewares that depend on the workspace: a call routed to another workspace runs those of its server
+	workspaceMiddlewares := []server.ToolHandlerMiddleware{
+		buildMessageURLMiddleware(logger),
+		buildDryRunMiddleware(isDryRunEnabled(), logger),
+		buildConfirmationMiddleware(confirmToolsFromEnv(), confirmationSummarizer.Summarize, logger),
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		subscriptions.subscriptions.RemoveSession(session.SessionID())
+	})
+
+	opts := []server.ServerOption{
 		server.WithLogging(),
 		server.WithRecovery(),
+		server.WithElicitation(),
+		server.WithCompletions(),
+		server.WithResourceCapabilities(true, false),
+		server.WithHooks(hooks),
+		server.WithToolFilter(auth.FilterTools),
+		server.WithResourceCompletionProvider(completionsHandler),
+		server.WithPromptCompletionProvider(completionsHandler),
 		server.WithToolHandlerMiddleware(buildErrorRecoveryMiddleware(logger)),
 		server.WithToolHandlerMiddleware(buildLoggerMiddleware(logger)),
+		server.WithToolHandlerMiddleware(buildStructuredContentMiddleware(logger)),
 		server.WithToolHandlerMiddleware(auth.BuildMiddleware(provider.ServerTransport(), logger)),
-	)
+		server.WithToolHandlerMiddleware(auth.BuildScopeMiddleware(logger)),
+		server.WithToolHandlerMiddleware(workspaces.middleware),
+	}
+	for _, middleware := range workspaceMiddlewares {
+		opts = append(opts, server.WithToolHandlerMiddleware(middleware))
+	}
+	s := server.NewMCPServer("Slack MCP Server", version.Version, opts...)
 
 	conversationsHandler := handler.NewConversationsHandler(provider, logger)
 	chatHandler := handler.NewChatHandler(provider, logger)
 	reactionsHandler := handler.NewReactionsHandler(provider, logger)
 	searchHandler := handler.NewSearchHandler(provider, logger)
+	syncHandler := handler.NewSyncHandler(provider, logger)
 	emojiHandler := handler.NewEmojiHandler(provider, logger)
 	channelsHandler := handler.NewChannelsHandler(provider, logger)
 	usersHandler := handler.NewUsersHandler(provider, logger)
 	authHandler := handler.NewAuthHandler(provider, logger)
 	usergroupsHandler := handler.NewUsergroupsHandler(provider, logger)
+	pinsHandler := handler.NewPinsHandler(provider, logger)
+	remindersHandler := handler.NewRemindersHandler(provider, logger)
 
 	// Get download directory from env var (empty string means use temp directory)
 	downloadDir := os.Getenv("SLACK_MCP_DOWNLOAD_DIR")
 	// Pass empty string to NewFileHandler - it will create a temp directory
 	fileHandler := handler.NewFileHandler(provider, logger, downloadDir)
+	workspacesHandler := handler.NewWorkspacesHandler(provider, workspaces.list, logger)
 
 	if shouldAddTool(ToolGetCurrentUser, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetCurrentUser,
 			mcp.WithDescription("Get information about the authenticated user (Slack API: auth.test)"),
+			readOnlyHints(),
+			withCSVOutput(handler.CurrentUser{}),
 		), authHandler.GetCurrentUserHandler)
 	}
 
+	if shouldAddTool(ToolListWorkspaces, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolListWorkspaces,
+			mcp.WithDescription("List the Slack workspaces this server is signed in to. Other tools take a name, team ID or domain from it in their workspace argument."),
+			readOnlyHints(),
+			withCSVOutput(handler.Workspace{}),
+		), workspacesHandler.ListWorkspacesHandler)
+	}
+
 	if shouldAddTool(ToolGetChannelMessages, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetChannelMessages,
 			mcp.WithDescription("Get messages from a channel or DM (Slack API: conversations.history)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("    - `channel_id` (string): ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
@@ -189,7 +362,7 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			),
 			mcp.WithString("fields",
 				mcp.DefaultString("msgID,userUser,realName,text,time"),
-				mcp.Description("Comma-separated list of fields to return. Options: 'msgID', 'userID', 'userUser', 'realName', 'channelID', 'threadTs', 'text', 'time', 'reactions', 'files', 'filesFull', 'cursor'. 'files' returns id:name:type:size (efficient), 'filesFull' adds URLs (verbose). Use 'all' for all fields except filesFull. Default: 'msgID,userUser,realName,text,time'"),
+				mcp.Description("Comma-separated list of fields to return. Options: 'msgID', 'userID', 'userUser', 'realName', 'channelID', 'threadTs', 'text', 'time', 'reactions', 'files', 'filesFull', 'permalink', 'cursor'. 'files' returns id:name:type:size (efficient), 'filesFull' adds URLs (verbose). Use 'all' for all fields except filesFull. Default: 'msgID,userUser,realName,text,time'"),
 			),
 		), conversationsHandler.ConversationsHistoryHandler)
 	}
@@ -197,13 +370,15 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolGetThreadMessages, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetThreadMessages,
 			mcp.WithDescription("Get messages from a thread (Slack API: conversations.replies)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 			),
 			mcp.WithString("thread_ts",
 				mcp.Required(),
-				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies."),
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies, or the message URL (https://<workspace>.slack.com/archives/C.../p...)."),
 			),
 			mcp.WithBoolean("include_activity_messages",
 				mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
@@ -218,20 +393,52 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			),
 			mcp.WithString("fields",
 				mcp.DefaultString("msgID,userUser,realName,text,time"),
-				mcp.Description("Comma-separated list of fields to return. Options: 'msgID', 'userID', 'userUser', 'realName', 'channelID', 'threadTs', 'text', 'time', 'reactions', 'files', 'filesFull'. 'files' returns id:name:type:size (efficient), 'filesFull' adds URLs (verbose). Use 'all' for all fields except filesFull. Default: 'msgID,userUser,realName,text,time'"),
+				mcp.Description("Comma-separated list of fields to return. Options: 'msgID', 'userID', 'userUser', 'realName', 'channelID', 'threadTs', 'text', 'time', 'reactions', 'files', 'filesFull', 'permalink'. 'files' returns id:name:type:size (efficient), 'filesFull' adds URLs (verbose). Use 'all' for all fields except filesFull. Default: 'msgID,userUser,realName,text,time'"),
 			),
 		), conversationsHandler.ConversationsRepliesHandler)
 	}
 
+	if shouldAddTool(ToolWaitForReply, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolWaitForReply,
+			mcp.WithDescription("Wait until someone replies in a channel or thread, e.g. to the question just posted with post_message. Blocks, sending progress notifications, until a matching message is posted or the timeout passes, then returns the new messages in the same format as get_thread_messages. Returns only a header when nobody replied in time."),
+			readOnlyHints(),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of the thread's parent message (format 1234567890.123456) or the message URL. Optional - if not provided, waits for a new message in the channel itself."),
+			),
+			mcp.WithString("users",
+				mcp.Description("Comma-separated user IDs or @handles to wait for. Optional - if not provided, a message from anyone counts."),
+			),
+			mcp.WithString("oldest",
+				mcp.Description("Only messages posted after this timestamp count, e.g. the ts of the question returned by post_message. Defaults to the time of the call."),
+			),
+			mcp.WithString("timeout",
+				mcp.DefaultString("5m"),
+				mcp.Description("How long to wait, as a duration (e.g. 90s, 5m) or a number of seconds. At most 30m."),
+			),
+			mcp.WithString("fields",
+				mcp.DefaultString("msgID,userUser,realName,text,time"),
+				mcp.Description("Comma-separated list of fields to return, as for get_thread_messages. Default: 'msgID,userUser,realName,text,time'"),
+			),
+		), conversationsHandler.WaitForReplyHandler)
+	}
+
 	if shouldAddTool(ToolPostMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolPostMessage,
 			mcp.WithDescription("Post a message to a channel or DM (Slack API: chat.postMessage). Supports mrkdwn text and/or Block Kit blocks for rich formatting. When using blocks, text serves as fallback for notifications and accessibility."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 			),
 			mcp.WithString("thread_ts",
-				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. Timestamp format: 1234567890.123456. Optional - if not provided, posts to channel; if provided, posts as reply."),
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. Timestamp format: 1234567890.123456, or the message URL (https://<workspace>.slack.com/archives/C.../p...). Optional - if not provided, posts to channel; if provided, posts as reply."),
 			),
 			mcp.WithString("text",
 				mcp.Description("Message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility. Syntax: *bold*, _italic_, ~strike~, `code`, ```codeblock```, >quote, <URL|text>, <@U123> mentions, <#C123> channels."),
@@ -250,12 +457,15 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolPostMessageAsBot, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolPostMessageAsBot,
 			mcp.WithDescription("Post a message as the bot user (not as your personal user). Use this when you want messages to be clearly identified as coming from an AI assistant with a bot icon and 'APP' badge. Requires SLACK_MCP_BOT_TOKEN to be configured. Supports mrkdwn text and/or Block Kit blocks for rich formatting."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 			),
 			mcp.WithString("thread_ts",
-				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. Timestamp format: 1234567890.123456. Optional - if not provided, posts to channel; if provided, posts as reply."),
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. Timestamp format: 1234567890.123456, or the message URL (https://<workspace>.slack.com/archives/C.../p...). Optional - if not provided, posts to channel; if provided, posts as reply."),
 			),
 			mcp.WithString("text",
 				mcp.Description("Message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility. Syntax: *bold*, _italic_, ~strike~, `code`, ```codeblock```, >quote, <URL|text>, <@U123> mentions, <#C123> channels."),
@@ -270,16 +480,171 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), chatHandler.ChatPostMessageAsBotHandler)
 	}
 
+	// Ephemeral and /me messages share the post_message (and post_message_as_bot) channel policy
+	if shouldAddTool(ToolPostEphemeral, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolPostEphemeral,
+			mcp.WithDescription("Post an ephemeral message that only one member of the channel can see (Slack API: chat.postEphemeral). Nobody else is notified and the message is not kept in the channel history. The recipient must be a member of the channel. Supports mrkdwn text and/or Block Kit blocks."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.EphemeralResult{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("user",
+				mcp.Required(),
+				mcp.Description("User who will see the message: user ID (U...), @handle, or a display or real name such as 'Jane Doe'."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of a thread's parent message (format 1234567890.123456) or the message URL, to show the message inside that thread. Optional."),
+			),
+			mcp.WithString("text",
+				mcp.Description("Message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility."),
+			),
+			mcp.WithString("blocks",
+				mcp.Description("Block Kit blocks as JSON array string for rich layouts. Max 50 blocks."),
+			),
+		), chatHandler.ChatPostEphemeralHandler)
+	}
+
+	if shouldAddTool(ToolPostEphemeralAsBot, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolPostEphemeralAsBot,
+			mcp.WithDescription("Post an ephemeral message as the bot user that only one member of the channel can see (Slack API: chat.postEphemeral). Requires SLACK_MCP_BOT_TOKEN to be configured and the bot to be in the channel. Supports mrkdwn text and/or Block Kit blocks."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.EphemeralResult{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("user",
+				mcp.Required(),
+				mcp.Description("User who will see the message: user ID (U...), @handle, or a display or real name such as 'Jane Doe'."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of a thread's parent message (format 1234567890.123456) or the message URL, to show the message inside that thread. Optional."),
+			),
+			mcp.WithString("text",
+				mcp.Description("Message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility."),
+			),
+			mcp.WithString("blocks",
+				mcp.Description("Block Kit blocks as JSON array string for rich layouts. Max 50 blocks."),
+			),
+		), chatHandler.ChatPostEphemeralAsBotHandler)
+	}
+
+	if shouldAddTool(ToolPostMeMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolPostMeMessage,
+			mcp.WithDescription("Post a /me message, shown in italics after your name (Slack API: chat.meMessage). Plain text only, no blocks or threads."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("text",
+				mcp.Required(),
+				mcp.Description("Message text, e.g. 'is deploying the release branch'."),
+			),
+		), chatHandler.ChatPostMeMessageHandler)
+	}
+
+	if shouldAddTool(ToolPostMeMessageAsBot, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolPostMeMessageAsBot,
+			mcp.WithDescription("Post a /me message as the bot user (Slack API: chat.meMessage). Requires SLACK_MCP_BOT_TOKEN to be configured. Plain text only, no blocks or threads."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("text",
+				mcp.Required(),
+				mcp.Description("Message text, e.g. 'is deploying the release branch'."),
+			),
+		), chatHandler.ChatPostMeMessageAsBotHandler)
+	}
+
+	// Scheduled messages share the post_message channel policy
+	if shouldAddTool(ToolScheduleMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolScheduleMessage,
+			mcp.WithDescription("Schedule a message to be posted later (Slack API: chat.scheduleMessage). Accepts the same text/blocks/thread options as post_message. Returns the scheduled message ID, which can be used with delete_scheduled_message to cancel it."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.ScheduledMessage{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("post_at",
+				mcp.Required(),
+				mcp.Description("When to post, in server local time unless a zone is given. Accepts Unix timestamps, RFC3339 ('2025-07-15T09:00:00-07:00'), '2025-07-15 09:00', relative offsets ('in 2h', 'in 30 minutes', 'in 1 day'), or a day with a time ('tomorrow 9am', 'today at 17:30', 'friday 2pm', 'July 15 2025 9:30am'). A day without a time means 09:00. Must be in the future and at most 120 days ahead."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. Timestamp format: 1234567890.123456, or the message URL. Optional - if provided, the scheduled message is posted as a reply."),
+			),
+			mcp.WithString("text",
+				mcp.Description("Message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility."),
+			),
+			mcp.WithString("blocks",
+				mcp.Description("Block Kit blocks as JSON array string for rich layouts. Max 50 blocks. See: https://api.slack.com/block-kit"),
+			),
+			mcp.WithBoolean("reply_broadcast",
+				mcp.Description("When replying to a thread (thread_ts provided), set to true to also send the reply to the main channel. Default: false."),
+				mcp.DefaultBool(false),
+			),
+		), chatHandler.ChatScheduleMessageHandler)
+	}
+
+	if shouldAddTool(ToolListScheduledMessages, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolListScheduledMessages,
+			mcp.WithDescription("List messages that are scheduled but not yet posted (Slack API: chat.scheduledMessages.list). Only channels allowed by SLACK_MCP_ADD_MESSAGE_TOOL are shown."),
+			readOnlyHints(),
+			withCSVOutput(handler.ScheduledMessage{}),
+			mcp.WithString("channel_id",
+				mcp.Description("Optional channel ID (C...) or name (#general, @user_dm) to limit results to one conversation."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(100),
+				mcp.Description("Maximum number of scheduled messages to return (max 1000)."),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor for pagination. Use the value of the '# Next cursor' line returned by the previous request."),
+			),
+		), chatHandler.ChatListScheduledMessagesHandler)
+	}
+
+	if shouldAddTool(ToolDeleteScheduledMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
+		s.AddTool(mcp.NewTool(ToolDeleteScheduledMessage,
+			mcp.WithDescription("Cancel a scheduled message before it is posted (Slack API: chat.deleteScheduledMessage)."),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm) the message is scheduled for."),
+			),
+			mcp.WithString("scheduled_message_id",
+				mcp.Required(),
+				mcp.Description("ID returned by schedule_message or list_scheduled_messages (e.g., Q1298393284)."),
+			),
+		), chatHandler.ChatDeleteScheduledMessageHandler)
+	}
+
 	// Add reaction tool
 	if shouldAddTool(ToolAddReaction, enabledTools, "SLACK_MCP_REACTION_TOOL") {
 		s.AddTool(mcp.NewTool(ToolAddReaction,
 			mcp.WithDescription("Add an emoji reaction to a message (Slack API: reactions.add)"),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
-				mcp.Required(),
-				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
 			mcp.WithString("emoji",
 				mcp.Required(),
 				mcp.Description("Emoji name without colons (e.g., thumbsup, rocket)")),
@@ -290,12 +655,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolRemoveReaction, enabledTools, "SLACK_MCP_REACTION_TOOL") {
 		s.AddTool(mcp.NewTool(ToolRemoveReaction,
 			mcp.WithDescription("Remove an emoji reaction from a message (Slack API: reactions.remove)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
-				mcp.Required(),
-				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
 			mcp.WithString("emoji",
 				mcp.Required(),
 				mcp.Description("Emoji name without colons (e.g., thumbsup, rocket)")),
@@ -306,12 +673,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolDeleteMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolDeleteMessage,
 			mcp.WithDescription("Delete a message from a channel (Slack API: chat.delete)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
-				mcp.Required(),
-				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
 		), chatHandler.ChatDeleteMessageHandler)
 	}
 
@@ -319,12 +688,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUpdateMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolUpdateMessage,
 			mcp.WithDescription("Edit/update an existing message (Slack API: chat.update). Supports mrkdwn text and/or Block Kit blocks for rich formatting. When using blocks, text serves as fallback for notifications and accessibility."),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
-				mcp.Required(),
-				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
 			mcp.WithString("text",
 				mcp.Description("New message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility. Syntax: *bold*, _italic_, ~strike~, `code`, ```codeblock```, >quote, <URL|text>, <@U123> mentions, <#C123> channels.")),
 			mcp.WithString("blocks",
@@ -336,12 +707,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUpdateMessageAsBot, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolUpdateMessageAsBot,
 			mcp.WithDescription("Edit/update an existing bot message (Slack API: chat.update). Use this to update messages previously posted with post_message_as_bot. Requires SLACK_MCP_BOT_TOKEN to be configured. Supports mrkdwn text and/or Block Kit blocks for rich formatting."),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
-				mcp.Required(),
-				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
 			mcp.WithString("text",
 				mcp.Description("New message text in Slack mrkdwn format. Required if blocks not provided. When blocks are provided, serves as fallback for notifications/accessibility.")),
 			mcp.WithString("blocks",
@@ -353,19 +726,37 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolDeleteMessageAsBot, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
 		s.AddTool(mcp.NewTool(ToolDeleteMessageAsBot,
 			mcp.WithDescription("Delete a bot message (Slack API: chat.delete). Use this to delete messages previously posted with post_message_as_bot. Requires SLACK_MCP_BOT_TOKEN to be configured."),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
+			mcp.WithString("timestamp",
+				mcp.Required(),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
+		), chatHandler.ChatDeleteMessageAsBotHandler)
+	}
+
+	if shouldAddTool(ToolGetPermalink, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolGetPermalink,
+			mcp.WithDescription("Get the permalink (shareable URL) of a message (Slack API: chat.getPermalink)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
 			mcp.WithString("timestamp",
 				mcp.Required(),
 				mcp.Description("Message timestamp (e.g., 1234567890.123456)")),
-		), chatHandler.ChatDeleteMessageAsBotHandler)
+		), chatHandler.ChatGetPermalinkHandler)
 	}
 
 	// Search messages tool - only register for non-bot tokens (bot tokens cannot use search.messages API)
 	if !provider.IsBotToken() && shouldAddTool(ToolSearchMessages, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolSearchMessages,
 			mcp.WithDescription("Search for messages across channels and DMs (Slack API: search.messages)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("search_query",
 				mcp.Description("Search query to filter messages. Example: 'marketing report' or full URL of Slack message e.g. 'https://slack.com/archives/C1234567890/p1234567890123456', then the tool will return a single message matching given URL, herewith all other parameters will be ignored."),
 			),
@@ -415,9 +806,72 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), searchHandler.SearchMessagesHandler)
 	}
 
+	// Local search tool - only registered when SLACK_MCP_MESSAGE_ARCHIVE or SLACK_MCP_SYNC_CHANNELS is set; works with bot tokens
+	if provider.MessageArchive() != nil && shouldAddTool(ToolSearchLocalMessages, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolSearchLocalMessages,
+			mcp.WithDescription("Search the messages this server has already seen, through get_channel_messages, get_thread_messages, search_messages and Slack events, in its local archive. Needs no Slack API call and works with bot tokens, but only finds archived messages. Without a query it lists the archived messages of a channel, newest first."),
+			readOnlyHints(),
+			withCSVOutput(nil),
+			mcp.WithString("search_query",
+				mcp.Description("Words that must all appear in a message, in any order and case. A word ending in * matches words starting with it, e.g. 'deploy*'. Supports the in:, from:, before:, after:, on:, during: and is:thread filters of search_messages, e.g. 'outage in:#incidents from:@alice after:2024-01-01'."),
+			),
+			mcp.WithString("filter_in_channel",
+				mcp.Description("Filter messages in a specific public/private channel by its ID or name. Example: 'C1234567890', 'G1234567890', or '#general'."),
+			),
+			mcp.WithString("filter_in_im_or_mpim",
+				mcp.Description("Filter messages in a direct message (DM) or multi-person direct message (MPIM) conversation by its ID or name. Example: 'D1234567890' or '@username_dm'."),
+			),
+			mcp.WithString("filter_users_from",
+				mcp.Description("Filter messages from a specific user: '@username', 'U1234567890' (user ID), or 'me' for the current user."),
+			),
+			mcp.WithString("filter_date_before",
+				mcp.Description("Filter messages sent before a specific date in format 'YYYY-MM-DD'. Example: '2023-10-01', 'Yesterday' or 'Today'."),
+			),
+			mcp.WithString("filter_date_after",
+				mcp.Description("Filter messages sent after a specific date in format 'YYYY-MM-DD'. Example: '2023-10-01', 'Yesterday' or 'Today'."),
+			),
+			mcp.WithString("filter_date_on",
+				mcp.Description("Filter messages sent on a specific date in format 'YYYY-MM-DD'. Example: '2023-10-01', 'Yesterday' or 'Today'."),
+			),
+			mcp.WithString("filter_date_during",
+				mcp.Description("Filter messages sent during the month of a date. Example: '2023-10-01' or 'July 2023'."),
+			),
+			mcp.WithBoolean("filter_threads_only",
+				mcp.Description("If true, the response will include only messages from threads. Default is boolean false."),
+			),
+			mcp.WithString("cursor",
+				mcp.DefaultString(""),
+				mcp.Description("Cursor for pagination. Use the value of the 'Next cursor' line of the previous response."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("The maximum number of items to return. Must be an integer between 1 and 100."),
+			),
+			mcp.WithString("fields",
+				mcp.DefaultString("msgID,userUser,realName,channelID,text,time"),
+				mcp.Description("Comma-separated list of fields to return. Options: 'msgID', 'userID', 'userUser', 'realName', 'channelID', 'threadTs', 'text', 'time', 'permalink'. Default: 'msgID,userUser,realName,channelID,text,time'. Reactions and files are not archived."),
+			),
+			mcp.WithString("sort",
+				mcp.DefaultString("newest_first"),
+				mcp.Description("Sort order: 'newest_first' (default) or 'oldest_first'."),
+			),
+		), searchHandler.SearchLocalMessagesHandler)
+	}
+
+	// Sync status tool - only registered when SLACK_MCP_SYNC_CHANNELS is set
+	if provider.Syncer() != nil && shouldAddTool(ToolSyncStatus, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolSyncStatus,
+			mcp.WithDescription("Show how far the channels of SLACK_MCP_SYNC_CHANNELS are synced into the local message archive, which search_local_messages searches: the oldest and newest synced message of each channel, whether its history is fully backfilled, and the last sync error."),
+			readOnlyHints(),
+			withCSVOutput(handler.SyncedChannel{}),
+		), syncHandler.SyncStatusHandler)
+	}
+
 	if shouldAddTool(ToolListChannels, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolListChannels,
 			mcp.WithDescription("List channels, DMs, and group DMs (Slack API: conversations.list)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("query",
 				mcp.Description("Search for channels by name. Searches in channel name, topic, and purpose (case-insensitive)"),
 			),
@@ -449,6 +903,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolListChannelMembers, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolListChannelMembers,
 			mcp.WithDescription("List members of a channel, DM, or group DM (Slack API: conversations.members, conversations.info)"),
+			readOnlyHints(),
+			withCSVOutput(handler.Member{}),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("Channel ID (C..., D..., G...) or name (#general, @user_dm)"),
@@ -466,6 +922,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolListUsers, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolListUsers,
 			mcp.WithDescription("List users in the workspace (Slack API: users.list). Returns transparency headers showing org member vs external user counts."),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("query",
 				mcp.Description("Search for users by name. Searches in username, real name, and display name (case-insensitive)"),
 			),
@@ -502,9 +960,11 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolGetUserInfo, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetUserInfo,
 			mcp.WithDescription("Get detailed information about a specific user (Slack API: users.info, users.getPresence)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("user_id",
 				mcp.Required(),
-				mcp.Description("User ID (U...) or username (@username)"),
+				mcp.Description("User ID (U...) username (@username) or email address"),
 			),
 			mcp.WithString("fields",
 				mcp.DefaultString("id,name,real_name,display_name,email,title,status_text,is_admin,is_bot"),
@@ -513,9 +973,146 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usersHandler.GetUserInfoHandler)
 	}
 
+	// Status, presence and DND changes only affect the authenticated user but are still opt-in
+	if shouldAddTool(ToolSetMyStatus, enabledTools, "SLACK_MCP_STATUS_TOOL") {
+		s.AddTool(mcp.NewTool(ToolSetMyStatus,
+			mcp.WithDescription("Set the custom status of the authenticated user (Slack API: users.profile.set)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.Status{}),
+			mcp.WithString("text",
+				mcp.Description("Status text, max 100 characters, e.g. 'In incident'"),
+			),
+			mcp.WithString("emoji",
+				mcp.Description("Status emoji, with or without colons, e.g. ':rotating_light:' or 'rotating_light'"),
+			),
+			mcp.WithString("expiration",
+				mcp.Description("When the status clears. Accepts a duration ('2h', '90 minutes', 'in 1 day'), a Unix timestamp, RFC3339 or a natural time ('today 6pm', 'tomorrow 9am'). Omit to keep the status until cleared"),
+			),
+		), usersHandler.SetMyStatusHandler)
+	}
+
+	if shouldAddTool(ToolClearMyStatus, enabledTools, "SLACK_MCP_STATUS_TOOL") {
+		s.AddTool(mcp.NewTool(ToolClearMyStatus,
+			mcp.WithDescription("Clear the custom status text, emoji and expiration of the authenticated user (Slack API: users.profile.set)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.Status{}),
+		), usersHandler.ClearMyStatusHandler)
+	}
+
+	if shouldAddTool(ToolSetMyPresence, enabledTools, "SLACK_MCP_STATUS_TOOL") {
+		s.AddTool(mcp.NewTool(ToolSetMyPresence,
+			mcp.WithDescription("Set the presence of the authenticated user (Slack API: users.setPresence)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("presence",
+				mcp.Required(),
+				mcp.Enum("auto", "away"),
+				mcp.Description("'auto' lets Slack determine presence from activity, 'away' forces the user away"),
+			),
+		), usersHandler.SetMyPresenceHandler)
+	}
+
+	if shouldAddTool(ToolSnoozeDND, enabledTools, "SLACK_MCP_STATUS_TOOL") {
+		s.AddTool(mcp.NewTool(ToolSnoozeDND,
+			mcp.WithDescription("Snooze notifications (turn on Do Not Disturb) for the authenticated user (Slack API: dnd.setSnooze)"),
+			withDryRun(),
+			writeHints(true, false),
+			withCSVOutput(handler.DNDInfo{}),
+			mcp.WithString("duration",
+				mcp.Required(),
+				mcp.Description("How long to snooze: a number of minutes ('60'), a duration ('2h', '30 minutes') or a time to snooze until ('5pm', 'tomorrow 9am')"),
+			),
+		), usersHandler.SnoozeDNDHandler)
+	}
+
+	if shouldAddTool(ToolEndDND, enabledTools, "SLACK_MCP_STATUS_TOOL") {
+		s.AddTool(mcp.NewTool(ToolEndDND,
+			mcp.WithDescription("End the current snooze and any scheduled Do Not Disturb session of the authenticated user (Slack API: dnd.endSnooze, dnd.endDnd)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.DNDInfo{}),
+		), usersHandler.EndDNDHandler)
+	}
+
+	if shouldAddTool(ToolGetDNDInfo, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolGetDNDInfo,
+			mcp.WithDescription("Get the Do Not Disturb state of the authenticated user or of other users (Slack API: dnd.info, dnd.teamInfo)"),
+			readOnlyHints(),
+			withCSVOutput(handler.DNDInfo{}),
+			mcp.WithString("users",
+				mcp.Description("Comma-separated user IDs or @handles, up to 50. Omit for the authenticated user"),
+			),
+		), usersHandler.GetDNDInfoHandler)
+	}
+
+	if shouldAddTool(ToolAddReminder, enabledTools, "SLACK_MCP_REMINDER_TOOL") {
+		s.AddTool(mcp.NewTool(ToolAddReminder,
+			mcp.WithDescription("Create a reminder for the authenticated user or someone else (Slack API: reminders.add). Requires a user token."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.Reminder{}),
+			mcp.WithString("time",
+				mcp.Required(),
+				mcp.Description("When to remind. Accepts 'in 2 hours', 'tomorrow 9am', 'friday at 14:30', 'next mon', '2025-07-20 10:00', RFC3339 or a Unix timestamp. A day without a time means 9:00. Recurring schedules starting with 'every' (e.g. 'every weekday at 9am') are passed to Slack as-is"),
+			),
+			mcp.WithString("text",
+				mcp.Description("What to be reminded about. Optional when permalink is given"),
+			),
+			mcp.WithString("user",
+				mcp.Description("Who to remind: @handle or user ID. Omit (or 'me') for the authenticated user"),
+			),
+			mcp.WithString("permalink",
+				mcp.Description("Optional permalink of a message or thread the reminder is about, e.g. https://team.slack.com/archives/C123/p1700000000000100"),
+			),
+		), remindersHandler.AddReminderHandler)
+	}
+
+	if shouldAddTool(ToolListReminders, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolListReminders,
+			mcp.WithDescription("List reminders created by or for the authenticated user, soonest first (Slack API: reminders.list). Requires a user token."),
+			readOnlyHints(),
+			withCSVOutput(handler.Reminder{}),
+			mcp.WithBoolean("include_completed",
+				mcp.DefaultBool(false),
+				mcp.Description("Include reminders that are already complete. Default: false"),
+			),
+		), remindersHandler.ListRemindersHandler)
+	}
+
+	if shouldAddTool(ToolCompleteReminder, enabledTools, "SLACK_MCP_REMINDER_TOOL") {
+		s.AddTool(mcp.NewTool(ToolCompleteReminder,
+			mcp.WithDescription("Mark a reminder as complete (Slack API: reminders.complete)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("reminder_id",
+				mcp.Required(),
+				mcp.Description("Reminder ID (Rm...) as returned by list_reminders or add_reminder"),
+			),
+		), remindersHandler.CompleteReminderHandler)
+	}
+
+	if shouldAddTool(ToolDeleteReminder, enabledTools, "SLACK_MCP_REMINDER_TOOL") {
+		s.AddTool(mcp.NewTool(ToolDeleteReminder,
+			mcp.WithDescription("Delete a reminder (Slack API: reminders.delete)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("reminder_id",
+				mcp.Required(),
+				mcp.Description("Reminder ID (Rm...) as returned by list_reminders or add_reminder"),
+			),
+		), remindersHandler.DeleteReminderHandler)
+	}
+
 	if shouldAddTool(ToolGetOrgOverview, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetOrgOverview,
 			mcp.WithDescription("Get a summary of the organization's user composition. Shows native vs external user counts, breakdown by title, and helps answer questions like 'how many employees do we have?' or 'what engineering roles exist?'"),
+			readOnlyHints(),
+			withTextOutput(),
 			mcp.WithString("group_by",
 				mcp.DefaultString("title"),
 				mcp.Description("How to group the summary. Options: 'title' (default, groups native active users by job title). More groupings may be added later."),
@@ -526,6 +1123,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolCreateChannel, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolCreateChannel,
 			mcp.WithDescription("Create a new public or private channel (Slack API: conversations.create)"),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(nil),
 			mcp.WithString("name",
 				mcp.Required(),
 				mcp.Description("Name for the new channel (lowercase, no spaces, max 80 chars)"),
@@ -549,6 +1149,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolArchiveChannel, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolArchiveChannel,
 			mcp.WithDescription("Archive a channel (Slack API: conversations.archive)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("Channel ID (C...) or name (#channel-name) to archive"),
@@ -556,9 +1159,215 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), channelsHandler.ArchiveChannelHandler)
 	}
 
+	if shouldAddTool(ToolUnarchiveChannel, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolUnarchiveChannel,
+			mcp.WithDescription("Restore an archived channel (Slack API: conversations.unarchive)"),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(handler.ChannelUpdated{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the archived channel (C...). Archived channels are not in the channels cache, so names usually cannot be resolved"),
+			),
+		), channelsHandler.UnarchiveChannelHandler)
+	}
+
+	if shouldAddTool(ToolRenameChannel, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolRenameChannel,
+			mcp.WithDescription("Rename a channel (Slack API: conversations.rename)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.ChannelUpdated{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name) to rename"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("New name for the channel (lowercase, no spaces, max 80 chars)"),
+			),
+		), channelsHandler.RenameChannelHandler)
+	}
+
+	if shouldAddTool(ToolSetChannelTopic, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolSetChannelTopic,
+			mcp.WithDescription("Set the topic of a channel (Slack API: conversations.setTopic)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.ChannelUpdated{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)"),
+			),
+			mcp.WithString("topic",
+				mcp.Required(),
+				mcp.Description("New topic, max 250 characters. Pass an empty string to clear the topic"),
+			),
+		), channelsHandler.SetChannelTopicHandler)
+	}
+
+	if shouldAddTool(ToolSetChannelPurpose, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolSetChannelPurpose,
+			mcp.WithDescription("Set the purpose (description) of a channel (Slack API: conversations.setPurpose)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.ChannelUpdated{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)"),
+			),
+			mcp.WithString("purpose",
+				mcp.Required(),
+				mcp.Description("New purpose, max 250 characters. Pass an empty string to clear the purpose"),
+			),
+		), channelsHandler.SetChannelPurposeHandler)
+	}
+
+	// Channel membership tools are gated by their own channel policy
+	if shouldAddTool(ToolInviteToChannel, enabledTools, "SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL") {
+		s.AddTool(mcp.NewTool(ToolInviteToChannel,
+			mcp.WithDescription("Invite users to a channel (Slack API: conversations.invite). Usergroups are expanded to their members. Returns one CSV row per user with its outcome (invited, already_member, failed, not_found)."),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(handler.MembershipResult{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)")),
+			mcp.WithString("users",
+				mcp.Required(),
+				mcp.Description("Comma-separated users to invite: @handle, user IDs (U...), usergroup handles (@oncall-team) or usergroup IDs (S...). Example: '@alice,U0123456789,@platform-team'")),
+		), channelsHandler.InviteToChannelHandler)
+	}
+
+	if shouldAddTool(ToolRemoveFromChannel, enabledTools, "SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL") {
+		s.AddTool(mcp.NewTool(ToolRemoveFromChannel,
+			mcp.WithDescription("Remove users from a channel (Slack API: conversations.kick). Usergroups are expanded to their members. Returns one CSV row per user with its outcome (removed, not_member, failed, not_found)."),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(handler.MembershipResult{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)")),
+			mcp.WithString("users",
+				mcp.Required(),
+				mcp.Description("Comma-separated users to remove: @handle, user IDs (U...), usergroup handles or usergroup IDs (S...)")),
+		), channelsHandler.RemoveFromChannelHandler)
+	}
+
+	if shouldAddTool(ToolJoinChannel, enabledTools, "SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL") {
+		s.AddTool(mcp.NewTool(ToolJoinChannel,
+			mcp.WithDescription("Join a public channel as the authenticated user (Slack API: conversations.join)"),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)")),
+		), channelsHandler.JoinChannelHandler)
+	}
+
+	if shouldAddTool(ToolLeaveChannel, enabledTools, "SLACK_MCP_CHANNEL_MEMBERSHIP_TOOL") {
+		s.AddTool(mcp.NewTool(ToolLeaveChannel,
+			mcp.WithDescription("Leave a channel as the authenticated user (Slack API: conversations.leave)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#channel-name)")),
+		), channelsHandler.LeaveChannelHandler)
+	}
+
+	if shouldAddTool(ToolPinMessage, enabledTools, "SLACK_MCP_PIN_TOOL") {
+		s.AddTool(mcp.NewTool(ToolPinMessage,
+			mcp.WithDescription("Pin a message to a channel (Slack API: pins.add)"),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
+			mcp.WithString("timestamp",
+				mcp.Required(),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
+		), pinsHandler.PinMessageHandler)
+	}
+
+	if shouldAddTool(ToolUnpinMessage, enabledTools, "SLACK_MCP_PIN_TOOL") {
+		s.AddTool(mcp.NewTool(ToolUnpinMessage,
+			mcp.WithDescription("Unpin a message from a channel (Slack API: pins.remove)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm). Required unless timestamp is a message URL")),
+			mcp.WithString("timestamp",
+				mcp.Required(),
+				mcp.Description("Message timestamp (e.g., 1234567890.123456) or the message URL (https://<workspace>.slack.com/archives/C.../p...), in which case channel_id may be left empty")),
+		), pinsHandler.UnpinMessageHandler)
+	}
+
+	if shouldAddTool(ToolListPins, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolListPins,
+			mcp.WithDescription("List messages and files pinned to a channel (Slack API: pins.list)"),
+			readOnlyHints(),
+			withCSVOutput(handler.Pin{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+		), pinsHandler.ListPinsHandler)
+	}
+
+	if shouldAddTool(ToolAddBookmark, enabledTools, "SLACK_MCP_PIN_TOOL") {
+		s.AddTool(mcp.NewTool(ToolAddBookmark,
+			mcp.WithDescription("Add a link bookmark to a channel's bookmark bar (Slack API: bookmarks.add)"),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.Bookmark{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+			mcp.WithString("title",
+				mcp.Required(),
+				mcp.Description("Bookmark title shown in the channel header")),
+			mcp.WithString("link",
+				mcp.Required(),
+				mcp.Description("URL the bookmark points to (http:// or https://)")),
+			mcp.WithString("emoji",
+				mcp.Description("Optional emoji shown next to the title (e.g., :books:)")),
+		), pinsHandler.AddBookmarkHandler)
+	}
+
+	if shouldAddTool(ToolListBookmarks, enabledTools, "") {
+		s.AddTool(mcp.NewTool(ToolListBookmarks,
+			mcp.WithDescription("List the bookmarks of a channel (Slack API: bookmarks.list)"),
+			readOnlyHints(),
+			withCSVOutput(handler.Bookmark{}),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+		), pinsHandler.ListBookmarksHandler)
+	}
+
+	if shouldAddTool(ToolRemoveBookmark, enabledTools, "SLACK_MCP_PIN_TOOL") {
+		s.AddTool(mcp.NewTool(ToolRemoveBookmark,
+			mcp.WithDescription("Remove a bookmark from a channel (Slack API: bookmarks.remove)"),
+			withDryRun(),
+			writeHints(true, true),
+			withCSVOutput(nil),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("Channel ID (C...) or name (#general, @user_dm)")),
+			mcp.WithString("bookmark_id",
+				mcp.Required(),
+				mcp.Description("Bookmark ID as returned by list_bookmarks (e.g., Bk1234567890)")),
+		), pinsHandler.RemoveBookmarkHandler)
+	}
+
 	if shouldAddTool(ToolListEmojis, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolListEmojis,
 			mcp.WithDescription("List available emojis/reactions (Slack API: emoji.list)"),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithString("query",
 				mcp.Description("Search for emojis by name (case-insensitive)"),
 			),
@@ -579,6 +1388,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolDownloadFile, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolDownloadFile,
 			mcp.WithDescription("Download Slack files to local filesystem. Use file IDs from message 'files' or 'filesFull' fields. Files are downloaded with authentication and saved to the specified directory. Maximum file size: 50MB."),
+			writeHints(false, true),
+			withCSVOutput(handler.FileDownloadResult{}),
 			mcp.WithString("file_ids",
 				mcp.Required(),
 				mcp.Description("Array of file IDs to download (e.g., ['F09RFRJ8QSV', 'F09R0TL40DC']). File IDs are obtained from the 'files' or 'filesFull' fields in message responses. Can also be a single file ID string."),
@@ -592,6 +1403,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolGetFileInfo, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetFileInfo,
 			mcp.WithDescription("Get file metadata including sharing status, permalink, and visibility (Slack API: files.info). Use to check if a file is public/private, which channels it's shared in, and get its permalink. File IDs come from the 'files' or 'filesFull' fields in message responses."),
+			readOnlyHints(),
+			withCSVOutput(handler.FileInfoResult{}),
 			mcp.WithString("file_id",
 				mcp.Required(),
 				mcp.Description("Slack file ID (e.g., F09RFRJ8QSV). Obtained from the 'files' or 'filesFull' fields in message responses."),
@@ -602,6 +1415,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUploadFile, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolUploadFile,
 			mcp.WithDescription("Upload a local file to a Slack channel (Slack API: files.uploadV2). The file must exist on the local filesystem (e.g., previously downloaded via download_file). The uploaded file is scoped to the target channel - it is private by default, visible only to channel members. Use the download_file + upload_file flow to copy a file from one conversation to another without changing the original file's permissions. Maximum file size: 50MB."),
+			withDryRun(),
+			writeHints(false, false),
+			withCSVOutput(handler.FileUploadResult{}),
 			mcp.WithString("file_path",
 				mcp.Required(),
 				mcp.Description("Local filesystem path to the file to upload. Use the local_path value returned by download_file."),
@@ -617,7 +1433,7 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 				mcp.Description("Message text to accompany the file upload."),
 			),
 			mcp.WithString("thread_ts",
-				mcp.Description("Thread timestamp to upload the file as a thread reply. Format: 1234567890.123456"),
+				mcp.Description("Thread timestamp to upload the file as a thread reply. Format: 1234567890.123456, or the message URL."),
 			),
 		), fileHandler.UploadFileHandler)
 	}
@@ -625,6 +1441,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolMakeFilePublic, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolMakeFilePublic,
 			mcp.WithDescription("Make a Slack file publicly accessible (Slack API: files.sharedPublicURL). Activates the file's public URL so it can be used in Block Kit image blocks or shared externally. WARNING: Anyone with the URL can view the file. Returns the public permalink."),
+			withDryRun(),
+			writeHints(false, true),
+			withCSVOutput(handler.FilePublicResult{}),
 			mcp.WithString("file_id",
 				mcp.Required(),
 				mcp.Description("Slack file ID to make public (e.g., F09RFRJ8QSV)."),
@@ -635,6 +1454,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolGetSlackTemplates, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolGetSlackTemplates,
 			mcp.WithDescription("Get curated Block Kit templates for professional Slack messages. Returns the SLACK_TEMPLATES.md file with examples for status updates, alerts, meeting summaries, announcements, requests, reports, errors, and empty states. Use these templates as a starting point when composing well-formatted messages."),
+			readOnlyHints(),
+			mcp.WithOpenWorldHintAnnotation(false),
+			withTextOutput(),
 		), chatHandler.GetSlackTemplatesHandler)
 	}
 
@@ -646,7 +1468,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		s.AddTool(mcp.NewTool(ToolConversationsUnreads,
 			mcp.WithDescription("Get unread messages across all channels. With browser session tokens (xoxc/xoxd), uses a single API call for complete results. With OAuth user tokens (xoxp), scans a subset of channels per type (limited by max_channels) — results may be partial on large workspaces. Results are prioritized: DMs > group DMs > partner channels > internal channels."),
 			mcp.WithTitleAnnotation("Get Unread Messages"),
-			mcp.WithReadOnlyHintAnnotation(true),
+			readOnlyHints(),
+			withCSVOutput(nil),
 			mcp.WithBoolean("include_messages",
 				mcp.Description("If true (default), returns the actual unread messages. If false, returns only a summary of channels with unreads."),
 				mcp.DefaultBool(true),
@@ -678,14 +1501,16 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolConversationsMark, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolConversationsMark,
 			mcp.WithDescription("Mark a channel or DM as read. If no timestamp is provided, marks all messages as read."),
+			withDryRun(),
 			mcp.WithTitleAnnotation("Mark as Read"),
-			mcp.WithDestructiveHintAnnotation(false),
+			writeHints(false, true),
+			withTextOutput(),
 			mcp.WithString("channel_id",
 				mcp.Required(),
 				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... (e.g., #general, @username)."),
 			),
 			mcp.WithString("ts",
-				mcp.Description("Timestamp of the message to mark as read up to. If not provided, marks all messages as read."),
+				mcp.Description("Timestamp of the message to mark as read up to, or the message URL. If not provided, marks all messages as read."),
 			),
 		), conversationsHandler.ConversationsMarkHandler)
 	}
@@ -695,7 +1520,8 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		s.AddTool(mcp.NewTool(ToolUsergroupsList,
 			mcp.WithDescription("List all user groups (subteams) in the Slack workspace. User groups are mention groups like @engineering or @design that notify all members. Use this to discover available groups, check group membership counts, or find a group's ID before joining/updating it. Returns CSV with columns: id, name, handle, description, user_count, is_external."),
 			mcp.WithTitleAnnotation("List User Groups"),
-			mcp.WithReadOnlyHintAnnotation(true),
+			readOnlyHints(),
+			withCSVOutput(handler.UserGroup{}),
 			mcp.WithBoolean("include_users",
 				mcp.Description("Include list of user IDs in each group. Default is false."),
 				mcp.DefaultBool(false),
@@ -715,6 +1541,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		s.AddTool(mcp.NewTool(ToolUsergroupsMe,
 			mcp.WithDescription("Manage your own user group membership. Use action='list' to see which groups you belong to. Use action='join' with a usergroup_id to add yourself to a group (e.g., to receive @mentions). Use action='leave' with a usergroup_id to remove yourself. This is the easiest way to join/leave groups without needing to know the full member list."),
 			mcp.WithTitleAnnotation("My User Groups"),
+			writeHints(true, true),
+			withCSVOutput(handler.UserGroup{}),
+			withJSONOutput(nil),
 			mcp.WithString("action",
 				mcp.Required(),
 				mcp.Description("Action to perform: 'list' returns CSV of groups you're a member of, 'join' adds you to a group, 'leave' removes you from a group."),
@@ -728,8 +1557,10 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUsergroupsCreate, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolUsergroupsCreate,
 			mcp.WithDescription("Create a new user group (mention group) in the Slack workspace. After creation, use usergroups_users_update to add members, or users can join themselves with usergroups_me. The handle becomes the @mention (e.g., handle='engineering' creates @engineering)."),
+			withDryRun(),
 			mcp.WithTitleAnnotation("Create User Group"),
-			mcp.WithDestructiveHintAnnotation(true),
+			writeHints(false, false),
+			withJSONOutput(handler.UserGroup{}),
 			mcp.WithString("name",
 				mcp.Required(),
 				mcp.Description("Display name of the user group (e.g., 'Engineering Team', 'Design Squad')."),
@@ -749,8 +1580,10 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUsergroupsUpdate, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolUsergroupsUpdate,
 			mcp.WithDescription("Update a user group's metadata: name, handle (@mention), description, or default channels. Does NOT change members - use usergroups_users_update for that. At least one field must be provided."),
+			withDryRun(),
 			mcp.WithTitleAnnotation("Update User Group"),
-			mcp.WithDestructiveHintAnnotation(true),
+			writeHints(true, true),
+			withJSONOutput(handler.UserGroup{}),
 			mcp.WithString("usergroup_id",
 				mcp.Required(),
 				mcp.Description("ID of the user group to update (starts with 'S', e.g., 'S0123456789'). Get IDs from usergroups_list."),
@@ -773,8 +1606,10 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	if shouldAddTool(ToolUsergroupsUsersUpdate, enabledTools, "") {
 		s.AddTool(mcp.NewTool(ToolUsergroupsUsersUpdate,
 			mcp.WithDescription("Replace all members of a user group with a new list. WARNING: This completely replaces the member list - any user not in the 'users' parameter will be removed. To add/remove just yourself, use usergroups_me instead. To add a single user without removing others, first get current members from usergroups_list with include_users=true, then call this with the combined list."),
+			withDryRun(),
 			mcp.WithTitleAnnotation("Update User Group Members"),
-			mcp.WithDestructiveHintAnnotation(true),
+			writeHints(true, true),
+			withJSONOutput(nil),
 			mcp.WithString("usergroup_id",
 				mcp.Required(),
 				mcp.Description("ID of the user group (starts with 'S', e.g., 'S0123456789'). Get IDs from usergroups_list."),
@@ -786,6 +1621,13 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usergroupsHandler.UsergroupsUsersUpdateHandler)
 	}
 
+	// An offline archive can't be changed, so tools that write would only fail
+	if provider.IsOffline() {
+		removeWriteTools(s)
+	}
+
+	registerPrompts(s, logger)
+
 	logger.Info("Authenticating with Slack API...",
 		zap.String("context", "console"),
 	)
@@ -814,24 +1656,89 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		)
 	}
 
-	s.AddResource(mcp.NewResource(
-		"slack://"+ws+"/channels",
-		"Directory of Slack channels",
-		mcp.WithResourceDescription("This resource provides a directory of Slack channels."),
-		mcp.WithMIMEType("text/csv"),
-	), channelsHandler.ChannelsResource)
+	workspaces.add(&workspace{name: ws, domain: ws, auth: ar})
+
+	directories := []server.ServerResource{
+		{
+			Resource: mcp.NewResource(
+				"slack://"+ws+"/channels",
+				"Directory of Slack channels",
+				mcp.WithResourceDescription("This resource provides a directory of Slack channels."),
+				mcp.WithMIMEType("text/csv"),
+			),
+			Handler: channelsHandler.ChannelsResource,
+		},
+		{
+			Resource: mcp.NewResource(
+				"slack://"+ws+"/users",
+				"Directory of Slack users",
+				mcp.WithResourceDescription("This resource provides a directory of Slack users."),
+				mcp.WithMIMEType("text/csv"),
+			),
+			Handler: conversationsHandler.UsersResource,
+		},
+	}
+	s.AddResources(directories...)
 
 	s.AddResource(mcp.NewResource(
-		"slack://"+ws+"/users",
-		"Directory of Slack users",
-		mcp.WithResourceDescription("This resource provides a directory of Slack users."),
+		handler.WorkspacesResourceURI,
+		"Slack workspaces",
+		mcp.WithResourceDescription("The Slack workspaces this server is signed in to."),
 		mcp.WithMIMEType("text/csv"),
-	), conversationsHandler.UsersResource)
+	), workspacesHandler.WorkspacesResource)
+
+	// Template variables take IDs or names; a #name or @name must be percent-encoded (%23general, %40alice)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://channel/{id}/history",
+		"Slack channel history",
+		mcp.WithTemplateDescription("The latest 100 messages of a channel or DM, by ID or #name."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), conversationsHandler.ChannelHistoryResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://channel/{id}/thread/{ts}",
+		"Slack thread",
+		mcp.WithTemplateDescription("A thread and its replies, by channel and thread timestamp."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), conversationsHandler.ThreadResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://user/{id}",
+		"Slack user",
+		mcp.WithTemplateDescription("The profile of a user, by ID or @name."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), usersHandler.UserResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://file/{id}",
+		"Slack file",
+		mcp.WithTemplateDescription("The metadata of a file, by ID."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), fileHandler.FileResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://usergroup/{handle}",
+		"Slack user group",
+		mcp.WithTemplateDescription("A user group and its members, by handle or ID."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), usergroupsHandler.UsergroupResource)
+
+	pollCtx, stopPolling := context.WithCancel(context.Background())
+	go subscriptions.subscriptions.Run(pollCtx, subscriptionPollInterval(), subscriptions.notify(s))
+	// Messages received as events reach subscribers before the next poll
+	provider.OnEvent(subscriptions.event(s))
 
 	return &MCPServer{
-		server:      s,
-		logger:      logger,
-		fileHandler: fileHandler,
+		server:        s,
+		apiProvider:   provider,
+		logger:        logger,
+		fileHandler:   fileHandler,
+		subscriptions: subscriptions,
+		stopPolling:   stopPolling,
+
+		workspaces:           workspaces,
+		workspaceMiddlewares: workspaceMiddlewares,
+		directories:          directories,
 	}
 }
 
@@ -839,14 +1746,35 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 // Should be called when the server exits (via defer in main.go).
 func (s *MCPServer) Cleanup() {
 	s.logger.Info("MCPServer.Cleanup() called", zap.String("context", "console"))
+	if s.stopPolling != nil {
+		s.stopPolling()
+	}
 	if s.fileHandler != nil {
 		s.fileHandler.Cleanup()
 	} else {
 		s.logger.Warn("No fileHandler to cleanup", zap.String("context", "console"))
 	}
+	for _, ws := range s.extraWorkspaces {
+		ws.Cleanup()
+	}
+	if s.apiProvider != nil {
+		if err := s.apiProvider.Close(); err != nil {
+			s.logger.Warn("Failed to close cache store", zap.String("context", "console"), zap.Error(err))
+		}
+	}
 	s.logger.Info("MCPServer.Cleanup() finished", zap.String("context", "console"))
 }
 
+// EventRouter sends Slack events to the provider of this server's workspace or of the workspace added
+// with AddWorkspace they come from
+func (s *MCPServer) EventRouter() *provider.EventRouter {
+	providers := []*provider.ApiProvider{s.apiProvider}
+	for _, ws := range s.extraWorkspaces {
+		providers = append(providers, ws.apiProvider)
+	}
+	return provider.NewEventRouter(s.logger, providers...)
+}
+
 func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
 	s.logger.Info("Creating SSE server",
 		zap.String("context", "console"),
@@ -855,14 +1783,36 @@ func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
 	)
-	return server.NewSSEServer(s.server,
+	httpServer := &http.Server{}
+	sseServer := server.NewSSEServer(s.server,
 		server.WithBaseURL(fmt.Sprintf("http://%s", addr)),
 		server.WithSSEContextFunc(func(ctx context.Context, r *http.Request) context.Context {
 			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
 
 			return ctx
 		}),
+		server.WithHTTPServer(httpServer),
 	)
+	// SSE answers arrive on the event stream, so subscription answers are queued there too
+	var handler http.Handler = s.subscriptions.http(sseServer,
+		func(r *http.Request) string { return r.URL.Query().Get("sessionId") },
+		func(w http.ResponseWriter, r *http.Request, response mcp.JSONRPCMessage) {
+			w.WriteHeader(http.StatusAccepted)
+			if err := sseServer.SendEventToSession(r.URL.Query().Get("sessionId"), response); err != nil {
+				s.logger.Warn("Failed to send subscription response", zap.Error(err))
+			}
+		},
+	)
+	mux := http.NewServeMux()
+	if oauth := oauthFromEnv(s.logger); oauth != nil {
+		handler = oauth.Middleware(handler)
+		for _, path := range oauth.MetadataPaths() {
+			mux.Handle(path, oauth.MetadataHandler())
+		}
+	}
+	mux.Handle("/", handler)
+	httpServer.Handler = mux
+	return sseServer
 }
 
 func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
@@ -873,14 +1823,71 @@ func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
 	)
-	return server.NewStreamableHTTPServer(s.server,
+	httpServer := &http.Server{}
+	streamableServer, handler := s.streamableHandler(server.WithStreamableHTTPServer(httpServer))
+	mux := http.NewServeMux()
+	if oauth := oauthFromEnv(s.logger); oauth != nil {
+		handler = oauth.Middleware(handler)
+		for _, path := range oauth.MetadataPaths() {
+			mux.Handle(path, oauth.MetadataHandler())
+		}
+	}
+	mux.Handle("/mcp", handler)
+	if signingSecret := slackSigningSecret(); signingSecret != "" {
+		router := s.EventRouter()
+		mux.Handle(slackEventsPath, slackEventsHandler(signingSecret, router.ApplyEvent, s.logger))
+		router.SetLive(true)
+		s.logger.Info("Receiving Slack events", zap.String("context", "console"), zap.String("path", slackEventsPath))
+	}
+	httpServer.Handler = mux
+	return streamableServer
+}
+
+// streamableHandler creates the streamable HTTP server of /mcp and the handler serving it, which answers
+// resource subscriptions itself
+func (s *MCPServer) streamableHandler(opts ...server.StreamableHTTPOption) (*server.StreamableHTTPServer, http.Handler) {
+	opts = append([]server.StreamableHTTPOption{
 		server.WithEndpointPath("/mcp"),
 		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
 			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
 
 			return ctx
 		}),
+	}, opts...)
+	streamableServer := server.NewStreamableHTTPServer(s.server, opts...)
+	handler := s.subscriptions.http(streamableServer,
+		func(r *http.Request) string { return r.Header.Get(server.HeaderKeySessionID) },
+		func(w http.ResponseWriter, r *http.Request, response mcp.JSONRPCMessage) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				s.logger.Warn("Failed to send subscription response", zap.Error(err))
+			}
+		},
+	)
+	return streamableServer, handler
+}
+
+// oauthFromEnv returns the access token validator of the HTTP transports, or nil when OAuth is off.
+// main validates the configuration at startup; should it fail here anyway, every request is refused,
+// since authentication still expects OAuth claims.
+func oauthFromEnv(logger *zap.Logger) *auth.OAuth {
+	config, err := auth.OAuthConfigFromEnv()
+	if err != nil {
+		logger.Error("Invalid OAuth configuration, refusing all requests", zap.String("context", "console"), zap.Error(err))
+		return nil
+	}
+	if config == nil {
+		return nil
+	}
+	if os.Getenv("SLACK_MCP_API_KEY") != "" {
+		logger.Warn("SLACK_MCP_API_KEY is ignored, clients authenticate with OAuth access tokens", zap.String("context", "console"))
+	}
+	logger.Info("Validating OAuth access tokens",
+		zap.String("context", "console"),
+		zap.String("issuer", config.Issuer),
+		zap.String("resource", config.Resource),
 	)
+	return auth.NewOAuth(*config, nil, logger)
 }
 
 func (s *MCPServer) ServeStdio() error {
@@ -889,7 +1896,16 @@ func (s *MCPServer) ServeStdio() error {
 		zap.String("build_time", version.BuildTime),
 		zap.String("commit_hash", version.CommitHash),
 	)
-	err := server.ServeStdio(s.server)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	// Subscription requests are answered before the stdio server sees its input
+	stdout := &lockedWriter{w: os.Stdout}
+	stdin, forward := io.Pipe()
+	go s.subscriptions.stdio(ctx, os.Stdin, stdout, forward)
+
+	err := server.NewStdioServer(s.server).Listen(ctx, stdin, stdout)
 	if err != nil {
 		s.logger.Error("STDIO server error", zap.Error(err))
 	}
@@ -938,3 +1954,296 @@ func buildLoggerMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
 		}
 	}
 }
+
+// messageURLParams maps the tools that address a message by channel_id and timestamp to the name of
+// their timestamp argument. thread_ts arguments take the thread of the linked message, the others
+// take the message itself.
+var messageURLParams = map[string]string{
+	ToolGetThreadMessages:  "thread_ts",
+	ToolWaitForReply:       "thread_ts",
+	ToolPostMessage:        "thread_ts",
+	ToolPostMessageAsBot:   "thread_ts",
+	ToolPostEphemeral:      "thread_ts",
+	ToolPostEphemeralAsBot: "thread_ts",
+	ToolScheduleMessage:    "thread_ts",
+	ToolUploadFile:         "thread_ts",
+	ToolAddReaction:        "timestamp",
+	ToolRemoveReaction:     "timestamp",
+	ToolDeleteMessage:      "timestamp",
+	ToolUpdateMessage:      "timestamp",
+	ToolUpdateMessageAsBot: "timestamp",
+	ToolDeleteMessageAsBot: "timestamp",
+	ToolPinMessage:         "timestamp",
+	ToolUnpinMessage:       "timestamp",
+	ToolGetPermalink:       "timestamp",
+	ToolConversationsMark:  "ts",
+}
+
+// buildMessageURLMiddleware lets message tools take a Slack message URL
+// (https://<workspace>.slack.com/archives/C.../p...) in channel_id or in the timestamp argument.
+// The URL is split into channel ID and timestamp before the handler sees the arguments.
+func buildMessageURLMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tsParam, ok := messageURLParams[req.Params.Name]
+			if !ok {
+				return next(ctx, req)
+			}
+
+			args := req.GetArguments()
+			var ref text.MessageRef
+			var found bool
+			for _, key := range []string{tsParam, "channel_id"} {
+				if raw, isString := args[key].(string); isString {
+					if ref, found = text.ParseMessageURL(raw); found {
+						break
+					}
+				}
+			}
+			if !found {
+				return next(ctx, req)
+			}
+
+			ts := ref.Timestamp
+			if tsParam == "thread_ts" && ref.ThreadTs != "" {
+				ts = ref.ThreadTs
+			}
+
+			rewritten := make(map[string]any, len(args)+2)
+			for k, v := range args {
+				rewritten[k] = v
+			}
+			rewritten["channel_id"] = ref.ChannelID
+			rewritten[tsParam] = ts
+			req.Params.Arguments = rewritten
+
+			logger.Debug("Resolved message URL",
+				zap.String("tool", req.Params.Name),
+				zap.String("channel_id", ref.ChannelID),
+				zap.String(tsParam, ts),
+			)
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// isDryRunEnabled reports whether SLACK_MCP_DRY_RUN puts every tool call in dry-run mode
+func isDryRunEnabled() bool {
+	v := os.Getenv("SLACK_MCP_DRY_RUN")
+	return v == "1" || v == "true" || v == "yes"
+}
+
+// withDryRun declares the per-call dry_run argument of tools that change Slack state, and the
+// structured content of a dry-run answer
+func withDryRun() mcp.ToolOption {
+	argument := mcp.WithBoolean("dry_run",
+		mcp.Description("If true, channels and users are resolved and the call is validated, but nothing is sent to Slack. The response lists the Slack API requests that would have been made. Always on when the server runs with SLACK_MCP_DRY_RUN."),
+		mcp.DefaultBool(false),
+	)
+	output := withOutputProperties(map[string]any{
+		"dry_run": map[string]any{"type": "boolean"},
+		"requests": map[string]any{
+			"type":        "array",
+			"description": "The Slack API requests a dry run would have sent",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"method": map[string]any{"type": "string"},
+					"params": map[string]any{"type": "object"},
+				},
+			},
+		},
+	})
+	return func(t *mcp.Tool) {
+		argument(t)
+		output(t)
+	}
+}
+
+// removeWriteTools unregisters the tools that aren't read-only
+func removeWriteTools(s *server.MCPServer) {
+	var writeTools []string
+	for name, tool := range s.ListTools() {
+		if readOnly := tool.Tool.Annotations.ReadOnlyHint; readOnly == nil || !*readOnly {
+			writeTools = append(writeTools, name)
+		}
+	}
+	s.DeleteTools(writeTools...)
+}
+
+// registerPrompts offers the Block Kit templates of SLACK_TEMPLATES.md as prompts, along with the
+// workflow prompts whose tools are all enabled
+func registerPrompts(s *server.MCPServer, logger *zap.Logger) {
+	if content, path, err := handler.ReadSlackTemplates(); err != nil {
+		logger.Warn("SLACK_TEMPLATES.md not found, template prompts are not available", zap.Error(err))
+	} else {
+		templates := handler.ParseSlackTemplates(string(content))
+		for _, t := range templates {
+			s.AddPrompt(handler.TemplatePrompt(t), handler.TemplatePromptHandler(t))
+		}
+		logger.Debug("Registered template prompts", zap.String("path", path), zap.Int("count", len(templates)))
+	}
+
+	for _, p := range handler.WorkflowPrompts() {
+		if !slices.ContainsFunc(p.Tools, func(tool string) bool { return s.GetTool(tool) == nil }) {
+			s.AddPrompt(p.Prompt, p.Handler)
+		}
+	}
+}
+
+// readOnlyHints annotates a tool that only reads from Slack
+func readOnlyHints() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithReadOnlyHintAnnotation(true)(t)
+		mcp.WithDestructiveHintAnnotation(false)(t)
+		mcp.WithIdempotentHintAnnotation(true)(t)
+		mcp.WithOpenWorldHintAnnotation(true)(t)
+	}
+}
+
+// writeHints annotates a tool that changes Slack state. destructive marks tools that delete or overwrite
+// existing data rather than only add to it; idempotent marks tools where repeating a call with the same
+// arguments has no further effect.
+func writeHints(destructive, idempotent bool) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithReadOnlyHintAnnotation(false)(t)
+		mcp.WithDestructiveHintAnnotation(destructive)(t)
+		mcp.WithIdempotentHintAnnotation(idempotent)(t)
+		mcp.WithOpenWorldHintAnnotation(true)(t)
+	}
+}
+
+// buildDryRunMiddleware runs a tool call with mutating Slack API requests intercepted when the server
+// is in dry-run mode or the call passes dry_run=true. Handlers run unchanged, so resolution, allowlists
+// and block validation behave exactly as for a real call; the first write is recorded instead of sent
+// and the recorded requests replace the handler result.
+func buildDryRunMiddleware(serverWide bool, logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !serverWide && !req.GetBool("dry_run", false) {
+				return next(ctx, req)
+			}
+
+			ctx, recorder := transport.WithDryRun(ctx)
+			res, err := next(ctx, req)
+
+			calls := recorder.Calls()
+			if len(calls) == 0 {
+				// Validation failed before anything would be sent, or the tool does not write
+				return res, err
+			}
+
+			logger.Info("Dry run, Slack requests not sent",
+				zap.String("tool", req.Params.Name),
+				zap.Int("requests", len(calls)),
+			)
+			return dryRunResult(calls)
+		}
+	}
+}
+
+func dryRunResult(calls []transport.DryRunCall) (*mcp.CallToolResult, error) {
+	type DryRunRequest struct {
+		Method string `csv:"method"`
+		Params string `csv:"params"`
+	}
+	rows := make([]DryRunRequest, 0, len(calls))
+	for _, call := range calls {
+		params, err := json.Marshal(call.Params)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format dry run request", err), nil
+		}
+		rows = append(rows, DryRunRequest{Method: call.Method, Params: string(params)})
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format dry run result as CSV", err), nil
+	}
+
+	requests := make([]map[string]any, 0, len(calls))
+	for _, call := range calls {
+		requests = append(requests, map[string]any{"method": call.Method, "params": call.Params})
+	}
+	return mcp.NewToolResultStructured(
+		map[string]any{"dry_run": true, "requests": requests},
+		"# Dry run: nothing was sent to Slack\n"+string(csvBytes),
+	), nil
+}
+
+// confirmationSchema is the form shown to the person approving a tool call
+var confirmationSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Run this action. It cannot be undone.",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// buildConfirmationMiddleware asks the connected client, via MCP elicitation, to have a person approve
+// calls to the given tools before their handler runs. The prompt carries the rendered summary of the
+// call. Clients without elicitation support get a refusal instead, so the tools fail closed. Dry runs
+// send nothing to Slack and are not confirmed.
+func buildConfirmationMiddleware(tools []string, summarize func(ctx context.Context, tool string, args map[string]any) string, logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !slices.Contains(tools, req.Params.Name) || transport.IsDryRun(ctx) {
+				return next(ctx, req)
+			}
+
+			session := server.ClientSessionFromContext(ctx)
+			if withInfo, ok := session.(server.SessionWithClientInfo); ok && withInfo.GetClientCapabilities().Elicitation == nil {
+				logger.Warn("Refusing tool call, client does not support elicitation", zap.String("tool", req.Params.Name))
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"%s requires confirmation, but this MCP client does not support elicitation. "+
+						"Use a client that supports elicitation, run the call with dry_run=true to preview it, "+
+						"or remove the tool from SLACK_MCP_CONFIRM_TOOLS.", req.Params.Name)), nil
+			}
+			mcpServer := server.ServerFromContext(ctx)
+			if mcpServer == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s requires confirmation, but no client session is available", req.Params.Name)), nil
+			}
+
+			summary := summarize(ctx, req.Params.Name, req.GetArguments())
+			result, err := mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{
+				Params: mcp.ElicitationParams{
+					Message:         summary,
+					RequestedSchema: confirmationSchema,
+				},
+			})
+			if err != nil {
+				logger.Warn("Confirmation request failed", zap.String("tool", req.Params.Name), zap.Error(err))
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("%s requires confirmation, and the confirmation request failed", req.Params.Name), err), nil
+			}
+
+			if !isConfirmed(result) {
+				logger.Info("Tool call not confirmed",
+					zap.String("tool", req.Params.Name),
+					zap.String("action", string(result.Action)),
+				)
+				return mcp.NewToolResultError(fmt.Sprintf("%s was not run: the user did not confirm it (%s)", req.Params.Name, result.Action)), nil
+			}
+
+			logger.Info("Tool call confirmed", zap.String("tool", req.Params.Name))
+			return next(ctx, req)
+		}
+	}
+}
+
+// isConfirmed accepts an elicitation answer only when the form was submitted with confirm=true
+func isConfirmed(result *mcp.ElicitationResult) bool {
+	if result == nil || result.Action != mcp.ElicitationResponseActionAccept {
+		return false
+	}
+	content, ok := result.Content.(map[string]any)
+	if !ok {
+		return false
+	}
+	confirm, _ := content["confirm"].(bool)
+	return confirm
+}
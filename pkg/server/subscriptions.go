@@ -0,0 +1,196 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// mcp-go advertises the resources.subscribe capability but does not route resources/subscribe and
+// resources/unsubscribe to anything, answering "method not found". subscriptionRouter answers them
+// in front of each transport and hands everything else to mcp-go unchanged.
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+
+	defaultSubscriptionPollInterval = 30 * time.Second
+)
+
+type subscriptionRouter struct {
+	subscriptions *handler.SubscriptionsHandler
+	transport     string
+	logger        *zap.Logger
+}
+
+// subscriptionPollInterval returns SLACK_MCP_SUBSCRIPTION_POLL_INTERVAL or the default (30s).
+// Supports formats: "30s", "1m", "60" (seconds). Values under a second fall back to the default.
+func subscriptionPollInterval() time.Duration {
+	raw := os.Getenv("SLACK_MCP_SUBSCRIPTION_POLL_INTERVAL")
+	if raw == "" {
+		return defaultSubscriptionPollInterval
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return defaultSubscriptionPollInterval
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d < time.Second {
+		return defaultSubscriptionPollInterval
+	}
+	return d
+}
+
+// handleMessage answers a subscribe or unsubscribe request of a session. It returns false for any
+// other message, which the caller passes on to mcp-go.
+func (r *subscriptionRouter) handleMessage(ctx context.Context, sessionID string, message []byte) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, false
+	}
+	if request.Method != methodResourcesSubscribe && request.Method != methodResourcesUnsubscribe {
+		return nil, false
+	}
+
+	if authenticated, err := auth.IsAuthenticated(ctx, r.transport, r.logger); !authenticated {
+		r.logger.Error("Authentication failed for resource subscription", zap.Error(err))
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_REQUEST, "unauthorized", nil), true
+	}
+	if sessionID == "" {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_REQUEST, "resource subscriptions need a session", nil), true
+	}
+	if request.Params.URI == "" {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, "uri must be provided", nil), true
+	}
+
+	if request.Method == methodResourcesUnsubscribe {
+		r.subscriptions.Unsubscribe(sessionID, request.Params.URI)
+		return mcp.NewJSONRPCResultResponse(request.ID, mcp.EmptyResult{}), true
+	}
+	if err := r.subscriptions.Subscribe(sessionID, request.Params.URI); err != nil {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil), true
+	}
+	return mcp.NewJSONRPCResultResponse(request.ID, mcp.EmptyResult{}), true
+}
+
+// stdio forwards the lines of in to forward, answering subscription requests on out itself. out is
+// shared with the stdio server and must be a lockedWriter. forward is closed when in is exhausted.
+func (r *subscriptionRouter) stdio(ctx context.Context, in io.Reader, out io.Writer, forward io.WriteCloser) {
+	defer forward.Close()
+
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if response, ok := r.handleMessage(ctx, stdioSessionID, line); ok {
+				if writeErr := writeJSONLine(out, response); writeErr != nil {
+					r.logger.Error("Failed to write subscription response", zap.Error(writeErr))
+				}
+			} else if _, writeErr := forward.Write(line); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// http wraps the handler of the streamable HTTP and SSE transports. sessionID extracts the session of a
+// request; respond delivers the answer, in the response body or on the session's event stream.
+func (r *subscriptionRouter) http(next http.Handler, sessionID func(*http.Request) string, respond func(http.ResponseWriter, *http.Request, mcp.JSONRPCMessage)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Body == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := auth.AuthFromRequest(r.logger)(req.Context(), req)
+		if response, ok := r.handleMessage(ctx, sessionID(req), body); ok {
+			respond(w, req, response)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// notify sends notifications/resources/updated to a subscribed session. Sessions mcp-go no longer knows
+// lose their subscriptions.
+func (r *subscriptionRouter) notify(s *server.MCPServer) func(sessionID, uri string) {
+	return func(sessionID, uri string) {
+		err := s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if errors.Is(err, server.ErrSessionNotFound) {
+			r.subscriptions.RemoveSession(sessionID)
+			return
+		}
+		if err != nil {
+			r.logger.Warn("Failed to notify subscriber", zap.String("session", sessionID), zap.String("uri", uri), zap.Error(err))
+			return
+		}
+		r.logger.Debug("Notified subscriber", zap.String("session", sessionID), zap.String("uri", uri))
+	}
+}
+
+// event passes events received from Slack to the subscriptions, so messages reach subscribers before the
+// next poll
+func (r *subscriptionRouter) event(s *server.MCPServer) func(provider.Event) {
+	notify := r.notify(s)
+	return func(event provider.Event) {
+		r.subscriptions.HandleEvent(event, notify)
+	}
+}
+
+// stdioSessionID is the ID mcp-go gives the single stdio session
+const stdioSessionID = "stdio"
+
+// lockedWriter serializes writes, so lines written by the stdio server and the subscription router
+// never interleave
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
+
+func writeJSONLine(w io.Writer, message mcp.JSONRPCMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,325 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	slackTokenHeader  = "X-Slack-Token"
+	slackCookieHeader = "X-Slack-Cookie"
+
+	defaultTenantIdleTimeout = 30 * time.Minute
+
+	// tenantFailureTTL is how long credentials that failed to connect are answered with the same error
+	// instead of being sent to Slack again
+	tenantFailureTTL = 30 * time.Second
+)
+
+// MultiTenant reports whether SLACK_MCP_MULTI_TENANT asks the HTTP transport to serve every client with
+// the Slack credentials it sends instead of the server's own
+func MultiTenant() bool {
+	v := os.Getenv("SLACK_MCP_MULTI_TENANT")
+	return v == "true" || v == "1"
+}
+
+// tenantIdleTimeout returns SLACK_MCP_TENANT_IDLE_TIMEOUT or the default (30m).
+// Supports formats: "30m", "1h", "1800" (seconds). Values under a second fall back to the default.
+func tenantIdleTimeout() time.Duration {
+	raw := os.Getenv("SLACK_MCP_TENANT_IDLE_TIMEOUT")
+	if raw == "" {
+		return defaultTenantIdleTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return defaultTenantIdleTimeout
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d < time.Second {
+		return defaultTenantIdleTimeout
+	}
+	return d
+}
+
+// Tenants serves the HTTP transport for many Slack identities. Clients send their Slack token in the
+// X-Slack-Token header, plus the d cookie in X-Slack-Cookie for xoxc tokens, and each identity gets its
+// own provider, caches and MCP server. Tenants without requests for the idle timeout are shut down, and
+// reconnected by the next request with their credentials.
+type Tenants struct {
+	transport    string
+	enabledTools []string
+	logger       *zap.Logger
+	idleTimeout  time.Duration
+	failureTTL   time.Duration
+
+	// connect authenticates the credentials and returns the MCP server and the "team/user" identity of
+	// the tenant. ctx lives as long as the tenant.
+	connect func(ctx context.Context, token, cookie string) (*MCPServer, string, error)
+
+	mu       sync.Mutex
+	tenants  map[string]*tenant       // by hash of the credentials
+	failures map[string]tenantFailure // by hash of the credentials
+}
+
+// tenantFailure is the error connecting some credentials failed with
+type tenantFailure struct {
+	err error
+	at  time.Time
+}
+
+type tenant struct {
+	ready chan struct{} // closed once connected; err is set if that failed
+	err   error
+
+	identity string
+	server   *MCPServer
+	handler  http.Handler
+	cancel   context.CancelFunc
+
+	// guarded by Tenants.mu
+	active   int
+	lastUsed time.Time
+	replaced bool // by a newer tenant of the same identity; closed when the last request releases it
+}
+
+func NewTenants(transport string, logger *zap.Logger, enabledTools []string) *Tenants {
+	t := &Tenants{
+		transport:    transport,
+		enabledTools: enabledTools,
+		logger:       logger,
+		idleTimeout:  tenantIdleTimeout(),
+		failureTTL:   tenantFailureTTL,
+		tenants:      make(map[string]*tenant),
+		failures:     make(map[string]tenantFailure),
+	}
+	t.connect = t.connectSlack
+	return t
+}
+
+// connectSlack authenticates to Slack and starts filling the tenant's caches in the background
+func (t *Tenants) connectSlack(ctx context.Context, token, cookie string) (*MCPServer, string, error) {
+	p, authResp, err := provider.NewForTenant(t.transport, token, cookie, t.logger)
+	if err != nil {
+		return nil, "", err
+	}
+	identity := authResp.TeamID + "/" + authResp.UserID
+	logger := t.logger.With(zap.String("tenant", identity))
+
+	go func() {
+		for name, refresh := range map[string]func(context.Context) error{
+			"users":    p.RefreshUsers,
+			"channels": p.RefreshChannels,
+			"emojis":   p.RefreshEmojis,
+		} {
+			if err := refresh(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("Failed to cache "+name, zap.Error(err))
+			}
+		}
+		if syncer := p.Syncer(); syncer != nil {
+			go syncer.Run(ctx)
+		}
+		p.RefreshPeriodically(ctx)
+	}()
+	return NewMCPServer(p, logger, t.enabledTools), identity, nil
+}
+
+// Handler returns the HTTP handler of the multi-tenant server, with /mcp behind OAuth when configured
+func (t *Tenants) Handler() http.Handler {
+	mux := http.NewServeMux()
+	var handler http.Handler = t
+	if oauth := oauthFromEnv(t.logger); oauth != nil {
+		handler = oauth.Middleware(handler)
+		for _, path := range oauth.MetadataPaths() {
+			mux.Handle(path, oauth.MetadataHandler())
+		}
+	}
+	mux.Handle("/mcp", handler)
+	return mux
+}
+
+// ServeHTTP passes the request on to the MCP server of the Slack identity it carries credentials for
+func (t *Tenants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(slackTokenHeader)
+	if token == "" {
+		http.Error(w, "missing "+slackTokenHeader+" header", http.StatusUnauthorized)
+		return
+	}
+
+	tn, err := t.acquire(r.Context(), token, r.Header.Get(slackCookieHeader))
+	if err != nil {
+		if r.Context().Err() != nil {
+			return
+		}
+		t.logger.Warn("Failed to connect tenant to Slack", zap.String("context", "http"), zap.Error(err))
+		http.Error(w, "Slack rejected the credentials", http.StatusUnauthorized)
+		return
+	}
+	defer t.release(tn)
+
+	tn.handler.ServeHTTP(w, r)
+}
+
+// acquire returns the connected tenant for the credentials, connecting it first if needed. The tenant
+// stays in use, and is not evicted, until release. Credentials that failed to connect within the failure
+// TTL get the same error again.
+func (t *Tenants) acquire(ctx context.Context, token, cookie string) (*tenant, error) {
+	sum := sha256.Sum256([]byte(token + "\x00" + cookie))
+	key := hex.EncodeToString(sum[:])
+
+	t.mu.Lock()
+	if failure, ok := t.failures[key]; ok {
+		if time.Since(failure.at) < t.failureTTL {
+			t.mu.Unlock()
+			return nil, failure.err
+		}
+		delete(t.failures, key)
+	}
+	tn, ok := t.tenants[key]
+	if !ok {
+		tn = &tenant{ready: make(chan struct{})}
+		t.tenants[key] = tn
+		go t.start(key, tn, token, cookie)
+	}
+	tn.active++
+	t.mu.Unlock()
+
+	select {
+	case <-tn.ready:
+	case <-ctx.Done():
+		t.release(tn)
+		return nil, ctx.Err()
+	}
+	if tn.err != nil {
+		t.release(tn)
+		return nil, tn.err
+	}
+	return tn, nil
+}
+
+func (t *Tenants) release(tn *tenant) {
+	t.mu.Lock()
+	tn.active--
+	tn.lastUsed = time.Now()
+	closeReplaced := tn.replaced && tn.active == 0
+	t.mu.Unlock()
+
+	if closeReplaced {
+		tn.close()
+	}
+}
+
+// start connects a new tenant. Failed credentials are remembered for the failure TTL, after which the
+// next request tries again. A tenant replacing another for the same identity, as after a token rotation,
+// closes the old one so that the two don't share a cache directory; requests still running on the old
+// one finish first.
+func (t *Tenants) start(key string, tn *tenant, token, cookie string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mcpServer, identity, err := t.connect(ctx, token, cookie)
+	var handler http.Handler
+	if err == nil {
+		_, handler = mcpServer.streamableHandler()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer close(tn.ready)
+	if err != nil {
+		cancel()
+		tn.err = err
+		delete(t.tenants, key)
+		t.failures[key] = tenantFailure{err: err, at: time.Now()}
+		return
+	}
+
+	tn.identity = identity
+	tn.cancel = cancel
+	tn.server = mcpServer
+	tn.handler = handler
+	tn.lastUsed = time.Now()
+
+	for otherKey, other := range t.tenants {
+		if otherKey != key && other.identity == identity {
+			delete(t.tenants, otherKey)
+			if other.active > 0 {
+				other.replaced = true
+			} else {
+				go other.close()
+			}
+		}
+	}
+	t.logger.Info("Tenant connected", zap.String("context", "console"), zap.String("tenant", identity))
+}
+
+func (tn *tenant) close() {
+	tn.cancel()
+	tn.server.Cleanup()
+}
+
+// Run evicts idle tenants until ctx is done
+func (t *Tenants) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(t.idleTimeout/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the tenants without a request in flight whose last request ended over the idle
+// timeout before now, and returns how many it closed. Expired connection failures are dropped as well.
+func (t *Tenants) evictIdle(now time.Time) int {
+	var idle []*tenant
+	t.mu.Lock()
+	for key, failure := range t.failures {
+		if now.Sub(failure.at) >= t.failureTTL {
+			delete(t.failures, key)
+		}
+	}
+	for key, tn := range t.tenants {
+		if tn.server == nil || tn.active > 0 || now.Sub(tn.lastUsed) < t.idleTimeout {
+			continue
+		}
+		delete(t.tenants, key)
+		idle = append(idle, tn)
+	}
+	t.mu.Unlock()
+
+	for _, tn := range idle {
+		t.logger.Info("Evicting idle tenant", zap.String("context", "console"), zap.String("tenant", tn.identity))
+		tn.close()
+	}
+	return len(idle)
+}
+
+// Close shuts down every connected tenant
+func (t *Tenants) Close() {
+	t.mu.Lock()
+	var all []*tenant
+	for key, tn := range t.tenants {
+		if tn.server != nil {
+			delete(t.tenants, key)
+			all = append(all, tn)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, tn := range all {
+		tn.close()
+	}
+}
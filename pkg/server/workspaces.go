@@ -0,0 +1,275 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// allWorkspaces in the workspace argument runs a search in every workspace
+const allWorkspaces = "all"
+
+// fanOutTools accept "all" in their workspace argument
+var fanOutTools = []string{ToolSearchMessages}
+
+// workspace is a Slack workspace the server is signed in to
+type workspace struct {
+	name   string
+	domain string
+	auth   *slack.AuthTestResponse
+	server *MCPServer // nil for the workspace of the server's own provider
+}
+
+// workspaceRouter sends tool calls to the workspace named in their workspace argument. It runs before the
+// middlewares that depend on the workspace; a routed call runs those of the workspace's server instead.
+type workspaceRouter struct {
+	mu         sync.RWMutex
+	workspaces []*workspace // the server's own first
+	logger     *zap.Logger
+}
+
+func (r *workspaceRouter) add(ws *workspace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workspaces = append(r.workspaces, ws)
+}
+
+func (r *workspaceRouter) all() []*workspace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.workspaces)
+}
+
+// find returns the workspace with the given name, team ID or domain, or nil
+func (r *workspaceRouter) find(name string) *workspace {
+	for _, ws := range r.all() {
+		if strings.EqualFold(ws.name, name) || strings.EqualFold(ws.domain, name) || (ws.auth != nil && strings.EqualFold(ws.auth.TeamID, name)) {
+			return ws
+		}
+	}
+	return nil
+}
+
+// list returns the rows of list_workspaces and slack://workspaces
+func (r *workspaceRouter) list() []handler.Workspace {
+	var rows []handler.Workspace
+	for i, ws := range r.all() {
+		row := handler.Workspace{Name: ws.name, Default: i == 0}
+		if ws.auth != nil {
+			row.TeamID = ws.auth.TeamID
+			row.TeamName = ws.auth.Team
+			row.WorkspaceURL = ws.auth.URL
+			row.UserName = ws.auth.User
+			row.EnterpriseID = ws.auth.EnterpriseID
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (r *workspaceRouter) names() []string {
+	var names []string
+	for _, ws := range r.all() {
+		names = append(names, ws.name)
+	}
+	return names
+}
+
+func (r *workspaceRouter) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := req.GetString("workspace", "")
+		if name == "" || req.Params.Name == ToolListWorkspaces {
+			return next(ctx, req)
+		}
+
+		if strings.EqualFold(name, allWorkspaces) {
+			if !slices.Contains(fanOutTools, req.Params.Name) {
+				return mcp.NewToolResultError(fmt.Sprintf("workspace %q is only supported by %s", allWorkspaces, strings.Join(fanOutTools, ", "))), nil
+			}
+			return r.fanOut(ctx, withoutWorkspace(req), next)
+		}
+
+		ws := r.find(name)
+		if ws == nil {
+			// create_channel also takes the team ID of an Enterprise Grid workspace of the default token
+			if req.Params.Name == ToolCreateChannel {
+				return next(ctx, req)
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("unknown workspace %q, known workspaces: %s", name, strings.Join(r.names(), ", "))), nil
+		}
+		r.logger.Debug("Routing tool call", zap.String("tool", req.Params.Name), zap.String("workspace", ws.name))
+		return r.call(ctx, ws, withoutWorkspace(req), next)
+	}
+}
+
+func (r *workspaceRouter) call(ctx context.Context, ws *workspace, req mcp.CallToolRequest, next server.ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	if ws.server == nil {
+		return next(ctx, req)
+	}
+	return ws.server.callTool(ctx, req)
+}
+
+// fanOut runs a search in every workspace and merges the CSV results, each row prefixed with the name of
+// its workspace. Workspaces where the search fails are reported in "#" lines above the rows, unless it
+// failed everywhere.
+func (r *workspaceRouter) fanOut(ctx context.Context, req mcp.CallToolRequest, next server.ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	var (
+		header   []string
+		rows     [][]string
+		failures []string
+	)
+	for _, ws := range r.all() {
+		records, err := csvRecords(r.call(ctx, ws, req, next))
+		if err != nil {
+			r.logger.Warn("Search failed in workspace", zap.String("workspace", ws.name), zap.Error(err))
+			failures = append(failures, fmt.Sprintf("%s failed (%v)", ws.name, err))
+			continue
+		}
+		if len(records) == 0 {
+			continue
+		}
+		if header == nil {
+			header = append([]string{"workspace"}, records[0]...)
+		}
+		for _, record := range records[1:] {
+			rows = append(rows, append([]string{ws.name}, record...))
+		}
+	}
+	if header == nil && len(failures) > 0 {
+		return mcp.NewToolResultError("Search failed in every workspace: " + strings.Join(failures, "; ")), nil
+	}
+
+	var buf bytes.Buffer
+	for _, failure := range failures {
+		fmt.Fprintf(&buf, "# %s\n", failure)
+	}
+	w := csv.NewWriter(&buf)
+	if header != nil {
+		_ = w.Write(header)
+	}
+	_ = w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format search results as CSV", err), nil
+	}
+	return mcp.NewToolResultText(buf.String()), nil
+}
+
+// csvRecords parses the CSV text of a successful tool result, skipping its "#" lines
+func csvRecords(result *mcp.CallToolResult, err error) ([][]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || len(result.Content) != 1 {
+		return nil, errors.New("unexpected result")
+	}
+	content, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return nil, errors.New("unexpected result")
+	}
+	if result.IsError {
+		return nil, errors.New(content.Text)
+	}
+	reader := csv.NewReader(strings.NewReader(content.Text))
+	reader.Comment = '#'
+	return reader.ReadAll()
+}
+
+// withoutWorkspace returns the request without its workspace argument, which a routed call has used up
+func withoutWorkspace(req mcp.CallToolRequest) mcp.CallToolRequest {
+	args := make(map[string]any)
+	for key, value := range req.GetArguments() {
+		if key != "workspace" {
+			args[key] = value
+		}
+	}
+	req.Params.Arguments = args
+	return req
+}
+
+// callTool runs a tool of this server with the middlewares that depend on its workspace, for calls routed
+// here from the server of another workspace
+func (s *MCPServer) callTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tool := s.server.GetTool(req.Params.Name)
+	if tool == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is not available in this workspace", req.Params.Name)), nil
+	}
+	handler := tool.Handler
+	for i := len(s.workspaceMiddlewares) - 1; i >= 0; i-- {
+		handler = s.workspaceMiddlewares[i](handler)
+	}
+	return handler(ctx, req)
+}
+
+// AddWorkspace serves another Slack workspace from this server. Tools then take a workspace argument, and
+// calls naming the workspace run against its provider, with channel and user names resolved in its
+// caches. The workspace's channel and user directories are added to the resources.
+func (s *MCPServer) AddWorkspace(name string, p *provider.ApiProvider, enabledTools []string) {
+	wsServer := NewMCPServer(p, s.logger.With(zap.String("workspace", name)), enabledTools)
+	ws := &workspace{name: name, server: wsServer}
+	if ar, err := p.Slack().AuthTest(); err == nil {
+		ws.auth = ar
+		ws.domain, _ = text.Workspace(ar.URL)
+	}
+	s.workspaces.add(ws)
+	s.extraWorkspaces = append(s.extraWorkspaces, wsServer)
+
+	for _, directory := range wsServer.directories {
+		s.server.AddResource(directory.Resource, directory.Handler)
+	}
+
+	// Tools the default workspace lacks, such as search with a bot token, are still offered for the others
+	for name, tool := range wsServer.server.ListTools() {
+		if s.server.GetTool(name) == nil {
+			s.server.AddTool(tool.Tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s is not available in the default workspace, choose another one with the workspace argument", req.Params.Name)), nil
+			})
+		}
+	}
+	s.addWorkspaceArgument()
+}
+
+// addWorkspaceArgument adds the workspace argument to every tool but list_workspaces
+func (s *MCPServer) addWorkspaceArgument() {
+	defaultName := ""
+	if all := s.workspaces.all(); len(all) > 0 {
+		defaultName = all[0].name
+	}
+
+	for name, tool := range s.server.ListTools() {
+		if name == ToolListWorkspaces {
+			continue
+		}
+		description := fmt.Sprintf("Workspace to use: a name, team ID or domain from list_workspaces. Defaults to %s.", defaultName)
+		switch {
+		case slices.Contains(fanOutTools, name):
+			description += " Use 'all' to search every workspace; rows then start with a workspace column."
+		case name == ToolCreateChannel:
+			description += " Also takes the team ID of another Enterprise Grid workspace of the default workspace's user."
+		}
+
+		properties := make(map[string]any, len(tool.Tool.InputSchema.Properties)+1)
+		for key, value := range tool.Tool.InputSchema.Properties {
+			properties[key] = value
+		}
+		properties["workspace"] = map[string]any{
+			"type":        "string",
+			"description": description,
+		}
+		updated := tool.Tool
+		updated.InputSchema.Properties = properties
+		s.server.AddTool(updated, tool.Handler)
+	}
+}
@@ -0,0 +1,57 @@
+package text
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MessageRef identifies a message by channel and timestamp, as encoded in a Slack archive URL
+type MessageRef struct {
+	ChannelID string
+	Timestamp string
+	// ThreadTs is the parent of the thread the message belongs to, empty for top-level messages
+	ThreadTs string
+}
+
+var archivePathRe = regexp.MustCompile(`^/archives/([A-Z0-9]+)/p(\d{7,})(\d{6})/?$`)
+
+// ParseMessageURL parses a message permalink such as
+// https://team.slack.com/archives/C0123456789/p1700000000000100?thread_ts=1699999999.000200&cid=C0123456789.
+// The second return value is false when raw is not such a URL.
+func ParseMessageURL(raw string) (MessageRef, bool) {
+	raw = strings.Trim(strings.TrimSpace(raw), "<>")
+	if !strings.HasPrefix(raw, "https://") && !strings.HasPrefix(raw, "http://") {
+		return MessageRef{}, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return MessageRef{}, false
+	}
+	m := archivePathRe.FindStringSubmatch(u.Path)
+	if m == nil {
+		return MessageRef{}, false
+	}
+
+	ref := MessageRef{
+		ChannelID: m[1],
+		Timestamp: m[2] + "." + m[3],
+	}
+	if threadTs := u.Query().Get("thread_ts"); threadTs != "" && threadTs != ref.Timestamp {
+		ref.ThreadTs = threadTs
+	}
+	return ref, true
+}
+
+// MessagePermalink builds the archive URL of a message without calling chat.getPermalink.
+// workspaceURL is the team URL returned by auth.test, e.g. https://team.slack.com/.
+func MessagePermalink(workspaceURL, channelID, ts, threadTs string) string {
+	if workspaceURL == "" || channelID == "" || ts == "" {
+		return ""
+	}
+	link := strings.TrimSuffix(workspaceURL, "/") + "/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+	if threadTs != "" && threadTs != ts {
+		link += "?thread_ts=" + threadTs + "&cid=" + channelID
+	}
+	return link
+}
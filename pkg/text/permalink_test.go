@@ -0,0 +1,77 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestParseMessageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want MessageRef
+		ok   bool
+	}{
+		{
+			name: "top-level message",
+			raw:  "https://team.slack.com/archives/C0123456789/p1700000000000100",
+			want: MessageRef{ChannelID: "C0123456789", Timestamp: "1700000000.000100"},
+			ok:   true,
+		},
+		{
+			name: "thread reply",
+			raw:  "https://team.slack.com/archives/C0123456789/p1700000000000100?thread_ts=1699999999.000200&cid=C0123456789",
+			want: MessageRef{ChannelID: "C0123456789", Timestamp: "1700000000.000100", ThreadTs: "1699999999.000200"},
+			ok:   true,
+		},
+		{
+			name: "thread parent links to itself",
+			raw:  "https://team.slack.com/archives/C0123456789/p1699999999000200?thread_ts=1699999999.000200&cid=C0123456789",
+			want: MessageRef{ChannelID: "C0123456789", Timestamp: "1699999999.000200"},
+			ok:   true,
+		},
+		{
+			name: "enterprise host in angle brackets",
+			raw:  "<https://acme.enterprise.slack.com/archives/G0123456789/p1700000000000100>",
+			want: MessageRef{ChannelID: "G0123456789", Timestamp: "1700000000.000100"},
+			ok:   true,
+		},
+		{name: "plain timestamp", raw: "1700000000.000100"},
+		{name: "channel ID", raw: "C0123456789"},
+		{name: "channel archive without message", raw: "https://team.slack.com/archives/C0123456789"},
+		{name: "other site", raw: "https://example.com/page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseMessageURL(tt.raw)
+			if ok != tt.ok {
+				t.Fatalf("ParseMessageURL(%q) ok = %v, want %v", tt.raw, ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMessageURL(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessagePermalink(t *testing.T) {
+	got := MessagePermalink("https://team.slack.com/", "C0123456789", "1700000000.000100", "")
+	if want := "https://team.slack.com/archives/C0123456789/p1700000000000100"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = MessagePermalink("https://team.slack.com/", "C0123456789", "1700000000.000100", "1699999999.000200")
+	if want := "https://team.slack.com/archives/C0123456789/p1700000000000100?thread_ts=1699999999.000200&cid=C0123456789"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Round trip
+	ref, ok := ParseMessageURL(got)
+	if !ok || ref.ChannelID != "C0123456789" || ref.Timestamp != "1700000000.000100" || ref.ThreadTs != "1699999999.000200" {
+		t.Errorf("round trip failed: %+v %v", ref, ok)
+	}
+
+	if got := MessagePermalink("", "C0123456789", "1700000000.000100", ""); got != "" {
+		t.Errorf("expected empty permalink without workspace URL, got %q", got)
+	}
+}
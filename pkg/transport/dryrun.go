@@ -0,0 +1,304 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Placeholders for what Slack would create. Intercepted calls answer with them so that handlers go on to
+// their next request, and a dry run records every call a tool would make rather than just the first.
+const (
+	dryRunChannelID   = "C0DRYRUN"
+	dryRunFileID      = "F0DRYRUN"
+	dryRunTimestamp   = "0000000000.000000"
+	dryRunScheduledID = "Q0DRYRUN"
+	dryRunBookmarkID  = "Bk0DRYRUN"
+	dryRunGroupID     = "S0DRYRUN"
+	dryRunReminderID  = "Rm0DRYRUN"
+
+	// dryRunUploadURL is handed out by files.getUploadURLExternal. The file contents posted to it are
+	// dropped unrecorded, the recorded call already names the file and its length.
+	dryRunUploadURL = "https://files.slack.com/upload/v1/dry-run"
+)
+
+// mutatingMethods lists the Slack API methods that change workspace state. In dry-run mode these are
+// recorded instead of sent; everything else (lookups, history, auth.test) still goes to Slack so
+// channels and users resolve exactly as they would for a real call.
+var mutatingMethods = map[string]bool{
+	"chat.postMessage":             true,
+	"chat.postEphemeral":           true,
+	"chat.meMessage":               true,
+	"chat.update":                  true,
+	"chat.delete":                  true,
+	"chat.scheduleMessage":         true,
+	"chat.deleteScheduledMessage":  true,
+	"reactions.add":                true,
+	"reactions.remove":             true,
+	"pins.add":                     true,
+	"pins.remove":                  true,
+	"bookmarks.add":                true,
+	"bookmarks.edit":               true,
+	"bookmarks.remove":             true,
+	"conversations.create":         true,
+	"conversations.archive":        true,
+	"conversations.unarchive":      true,
+	"conversations.rename":         true,
+	"conversations.setTopic":       true,
+	"conversations.setPurpose":     true,
+	"conversations.invite":         true,
+	"conversations.kick":           true,
+	"conversations.join":           true,
+	"conversations.leave":          true,
+	"conversations.mark":           true,
+	"files.upload":                 true,
+	"files.getUploadURLExternal":   true,
+	"files.completeUploadExternal": true,
+	"files.sharedPublicURL":        true,
+	"files.delete":                 true,
+	"usergroups.create":            true,
+	"usergroups.update":            true,
+	"usergroups.users.update":      true,
+	"usergroups.disable":           true,
+	"usergroups.enable":            true,
+	"users.profile.set":            true,
+	"users.setPresence":            true,
+	"dnd.setSnooze":                true,
+	"dnd.endSnooze":                true,
+	"dnd.endDnd":                   true,
+	"reminders.add":                true,
+	"reminders.complete":           true,
+	"reminders.delete":             true,
+}
+
+// IsMutatingMethod reports whether a Slack API method changes workspace state
+func IsMutatingMethod(method string) bool {
+	return mutatingMethods[method]
+}
+
+// DryRunCall is a Slack API request that was intercepted in dry-run mode
+type DryRunCall struct {
+	Method string
+	Params map[string]any
+}
+
+// DryRunRecorder collects the requests intercepted during one dry-run tool call
+type DryRunRecorder struct {
+	mu    sync.Mutex
+	calls []DryRunCall
+}
+
+// Calls returns the intercepted requests in the order they were made
+func (r *DryRunRecorder) Calls() []DryRunCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DryRunCall(nil), r.calls...)
+}
+
+func (r *DryRunRecorder) add(call DryRunCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context under which mutating Slack API requests are recorded instead of sent
+func WithDryRun(ctx context.Context) (context.Context, *DryRunRecorder) {
+	recorder := &DryRunRecorder{}
+	return context.WithValue(ctx, dryRunKey{}, recorder), recorder
+}
+
+// IsDryRun reports whether ctx was returned by WithDryRun
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(*DryRunRecorder)
+	return ok
+}
+
+// DryRunTransport wraps another RoundTripper and intercepts mutating Slack API requests made with a
+// WithDryRun context
+type DryRunTransport struct {
+	roundTripper http.RoundTripper
+}
+
+// NewDryRunTransport creates a new DryRunTransport
+func NewDryRunTransport(roundTripper http.RoundTripper) *DryRunTransport {
+	return &DryRunTransport{roundTripper: roundTripper}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder, ok := req.Context().Value(dryRunKey{}).(*DryRunRecorder)
+	if !ok {
+		return t.roundTripper.RoundTrip(req)
+	}
+	if req.URL.String() == dryRunUploadURL {
+		if req.Body != nil {
+			_, _ = io.Copy(io.Discard, req.Body)
+			req.Body.Close()
+		}
+		return dryRunHTTPResponse(req, "OK"), nil
+	}
+	method := path.Base(req.URL.Path)
+	if !IsMutatingMethod(method) {
+		return t.roundTripper.RoundTrip(req)
+	}
+
+	params, err := requestParams(req)
+	if err != nil {
+		return nil, err
+	}
+	recorder.add(DryRunCall{Method: method, Params: params})
+
+	body, err := json.Marshal(dryRunReply(method, params))
+	if err != nil {
+		return nil, err
+	}
+	return dryRunHTTPResponse(req, string(body)), nil
+}
+
+func dryRunHTTPResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// dryRunReply is the successful answer to an intercepted method. Objects the call would create get
+// placeholder IDs, those it changes keep the IDs of the request.
+func dryRunReply(method string, params map[string]any) map[string]any {
+	param := func(key, fallback string) string {
+		if v, ok := params[key].(string); ok && v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	reply := map[string]any{"ok": true}
+	switch method {
+	case "chat.postMessage", "chat.meMessage":
+		reply["channel"] = param("channel", dryRunChannelID)
+		reply["ts"] = dryRunTimestamp
+	case "chat.postEphemeral":
+		reply["message_ts"] = dryRunTimestamp
+	case "chat.update", "chat.delete":
+		reply["channel"] = param("channel", dryRunChannelID)
+		reply["ts"] = param("ts", dryRunTimestamp)
+		reply["text"] = param("text", "")
+	case "chat.scheduleMessage":
+		reply["channel"] = param("channel", dryRunChannelID)
+		reply["scheduled_message_id"] = dryRunScheduledID
+	case "conversations.create":
+		reply["channel"] = map[string]any{
+			"id":         dryRunChannelID,
+			"name":       param("name", ""),
+			"is_private": param("is_private", "") == "true",
+		}
+	case "conversations.rename", "conversations.setTopic", "conversations.setPurpose",
+		"conversations.invite", "conversations.join":
+		reply["channel"] = map[string]any{"id": param("channel", dryRunChannelID), "name": param("name", "")}
+	case "files.getUploadURLExternal":
+		reply["upload_url"] = dryRunUploadURL
+		reply["file_id"] = dryRunFileID
+	case "files.completeUploadExternal":
+		var files []map[string]any
+		if err := json.Unmarshal([]byte(param("files", "")), &files); err != nil || len(files) == 0 {
+			files = []map[string]any{{"id": dryRunFileID}}
+		}
+		reply["files"] = files
+	case "files.upload":
+		reply["file"] = map[string]any{"id": dryRunFileID}
+	case "files.sharedPublicURL":
+		reply["file"] = map[string]any{"id": param("file", dryRunFileID)}
+	case "bookmarks.add", "bookmarks.edit":
+		reply["bookmark"] = map[string]any{
+			"id":         param("bookmark_id", dryRunBookmarkID),
+			"channel_id": param("channel_id", dryRunChannelID),
+			"title":      param("title", ""),
+			"link":       param("link", ""),
+		}
+	case "usergroups.create":
+		reply["usergroup"] = map[string]any{"id": dryRunGroupID, "name": param("name", ""), "handle": param("handle", "")}
+	case "usergroups.update", "usergroups.users.update", "usergroups.disable", "usergroups.enable":
+		reply["usergroup"] = map[string]any{"id": param("usergroup", dryRunGroupID)}
+	case "users.profile.set":
+		reply["profile"] = map[string]any{}
+	case "dnd.setSnooze":
+		reply["snooze_enabled"] = true
+	case "reminders.add":
+		reply["reminder"] = map[string]any{"id": dryRunReminderID, "text": param("text", "")}
+	}
+	return reply
+}
+
+// requestParams decodes the body of a Slack API request (form, multipart or JSON) without the token
+func requestParams(req *http.Request) (map[string]any, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	params := make(map[string]any)
+	mediaType, mediaParams, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/json":
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &params); err != nil {
+				return nil, err
+			}
+		}
+	case mediaType == "multipart/form-data":
+		form, err := multipart.NewReader(bytes.NewReader(body), mediaParams["boundary"]).ReadForm(32 << 20)
+		if err != nil {
+			return nil, err
+		}
+		addValues(params, form.Value)
+		for name, files := range form.File {
+			for _, f := range files {
+				params[name] = map[string]any{"filename": f.Filename, "size": f.Size}
+			}
+		}
+		_ = form.RemoveAll()
+	case len(body) > 0:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		addValues(params, values)
+	default:
+		addValues(params, req.URL.Query())
+	}
+
+	delete(params, "token")
+	return params, nil
+}
+
+func addValues(params map[string]any, values map[string][]string) {
+	for key, vals := range values {
+		if len(vals) == 1 {
+			params[key] = vals[0]
+		} else {
+			params[key] = vals
+		}
+	}
+}